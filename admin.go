@@ -0,0 +1,150 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// KeyInfo struct holds the key metadata reported by memcache server.
+type KeyInfo struct {
+	Server     string
+	Key        string
+	Expiration time.Time // zero value means key does not expire
+	Size       int
+}
+
+// DumpKeys method drives `lru_crawler metadump all` on every memcache server
+// over admin connection and calls given func for each key having given
+// prefix, keys are streamed as the server reports them. Returning error from
+// the func stops the dump and the error is returned. It requires memcached
+// 1.4.31 or later; it is meant for debugging and audit tooling.
+func (p *Provider) DumpKeys(prefix string, fn func(KeyInfo) error) error {
+	for _, addr := range p.serverAddrs() {
+		if err := p.dumpServerKeys(addr, prefix, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Admin connection and its methods
+//______________________________________________________________________________
+
+// adminConn is plain text protocol connection to single memcache server,
+// used for the commands not supported by memcache client library.
+type adminConn struct {
+	addr    string
+	conn    net.Conn
+	rw      *bufio.ReadWriter
+	timeout time.Duration
+}
+
+func (p *Provider) dialAdmin(addr string) (*adminConn, error) {
+	if p.dryRun != nil {
+		return nil, ErrDryRun
+	}
+	timeout := p.Client().Timeout
+	conn, err := net.DialTimeout("tcp", addr, timeout)
+	if err != nil {
+		return nil, fmt.Errorf("aah/cache/%s: server(%s) %v", p.name, addr, err)
+	}
+	return &adminConn{
+		addr:    addr,
+		conn:    conn,
+		rw:      bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn)),
+		timeout: timeout,
+	}, nil
+}
+
+// command method sends the command line and calls given func for every
+// response line until it returns false.
+func (ac *adminConn) command(cmd string, fn func(line string) (bool, error)) error {
+	_ = ac.conn.SetDeadline(time.Now().Add(ac.timeout))
+	if _, err := ac.rw.WriteString(cmd + "\r\n"); err != nil {
+		return err
+	}
+	if err := ac.rw.Flush(); err != nil {
+		return err
+	}
+	for {
+		_ = ac.conn.SetReadDeadline(time.Now().Add(ac.timeout))
+		line, err := ac.rw.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "ERROR") || strings.HasPrefix(line, "CLIENT_ERROR") ||
+			strings.HasPrefix(line, "SERVER_ERROR") || strings.HasPrefix(line, "BUSY") {
+			return fmt.Errorf("%s", line)
+		}
+		more, err := fn(line)
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+func (ac *adminConn) Close() error {
+	return ac.conn.Close()
+}
+
+func (p *Provider) dumpServerKeys(addr, prefix string, fn func(KeyInfo) error) error {
+	ac, err := p.dialAdmin(addr)
+	if err != nil {
+		return err
+	}
+	defer ac.Close()
+
+	err = ac.command("lru_crawler metadump all", func(line string) (bool, error) {
+		if line == "END" {
+			return false, nil
+		}
+		ki, ok := parseMetadumpLine(addr, line)
+		if !ok || !strings.HasPrefix(ki.Key, prefix) {
+			return true, nil
+		}
+		return true, fn(ki)
+	})
+	if err != nil {
+		return fmt.Errorf("aah/cache/%s: server(%s) metadump %v", p.name, addr, err)
+	}
+	return nil
+}
+
+// parseMetadumpLine method parses the metadump line e.g.
+//
+//	key=mycache-key1 exp=1543735888 la=1543735858 cas=12 fetch=no cls=1 size=71
+func parseMetadumpLine(addr, line string) (KeyInfo, bool) {
+	ki := KeyInfo{Server: addr}
+	for _, f := range strings.Fields(line) {
+		kv := strings.SplitN(f, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		switch kv[0] {
+		case "key":
+			k, err := url.PathUnescape(kv[1])
+			if err != nil {
+				return ki, false
+			}
+			ki.Key = k
+		case "exp":
+			if exp, err := strconv.ParseInt(kv[1], 10, 64); err == nil && exp > 0 {
+				ki.Expiration = time.Unix(exp, 0)
+			}
+		case "size":
+			ki.Size, _ = strconv.Atoi(kv[1])
+		}
+	}
+	return ki, ki.Key != ""
+}
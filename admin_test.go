@@ -0,0 +1,57 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheDumpKeys(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "dumpcache", ProviderName: "memcache1"}))
+	c := mgr.Cache("dumpcache")
+	assert.Nil(t, c.Put("key1", "value1", 10*time.Second))
+	assert.Nil(t, c.Put("key2", "value2", 10*time.Second))
+
+	p := mgr.Provider("memcache1").(*Provider)
+	var keys []string
+	err := p.DumpKeys("dumpcache-", func(ki KeyInfo) error {
+		assert.Equal(t, "localhost:11211", ki.Server)
+		assert.False(t, ki.Expiration.IsZero())
+		keys = append(keys, ki.Key)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Contains(t, keys, "dumpcache-key1")
+	assert.Contains(t, keys, "dumpcache-key2")
+
+	c.Flush()
+}
+
+func TestParseMetadumpLine(t *testing.T) {
+	ki, ok := parseMetadumpLine("localhost:11211", "key=mycache-user%3A42 exp=1543735888 la=1543735858 cas=12 fetch=no cls=1 size=71")
+	assert.True(t, ok)
+	assert.Equal(t, "mycache-user:42", ki.Key)
+	assert.Equal(t, int64(1543735888), ki.Expiration.Unix())
+	assert.Equal(t, 71, ki.Size)
+
+	ki, ok = parseMetadumpLine("localhost:11211", "key=mycache-key1 exp=-1 la=1543735858 cas=12 fetch=no cls=1 size=71")
+	assert.True(t, ok)
+	assert.True(t, ki.Expiration.IsZero())
+
+	_, ok = parseMetadumpLine("localhost:11211", "garbage")
+	assert.False(t, ok)
+}
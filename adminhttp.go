@@ -0,0 +1,151 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// defaultAdminKeysLimit is the max number of keys returned by `/_cache/keys`
+// unless `limit` is given.
+const defaultAdminKeysLimit = 1000
+
+var errAdminLimit = errors.New("aah/cache: admin keys limit reached")
+
+// AdminHandler method returns the HTTP handler exposing the provider stats
+// and management operations of the caches created by the provider. Handler
+// does not authenticate, mount it behind the app auth.
+//
+//	GET  /_cache/stats                           server and cache stats
+//	GET  /_cache/keys?cache=users&prefix=u:&limit=100  keys of the cache
+//	POST /_cache/flush?cache=users               flushes the cache
+//
+// Flush of the cache without `key_tracking` flushes all the entries of the
+// memcache servers, i.e. every cache and app sharing them; it is refused
+// unless confirmed with `all=true`.
+//
+// Paths are matched by suffix, so the handler can be mounted under any
+// prefix.
+func (p *Provider) AdminHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/_cache/stats"):
+			p.adminStats(w, r)
+		case strings.HasSuffix(r.URL.Path, "/_cache/keys"):
+			p.adminKeys(w, r)
+		case strings.HasSuffix(r.URL.Path, "/_cache/flush"):
+			p.adminFlush(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+type adminStats struct {
+	Provider string
+	Servers  map[string]ServerStats `json:",omitempty"`
+	Caches   map[string]Stats
+	Error    string `json:",omitempty"`
+}
+
+func (p *Provider) adminStats(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		adminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	res := adminStats{Provider: p.name, Caches: make(map[string]Stats)}
+	var err error
+	if res.Servers, err = p.Stats(); err != nil {
+		res.Error = err.Error()
+	}
+	for _, name := range p.cacheNames() {
+		if m, found := p.cache(name); found {
+			res.Caches[name] = m.Stats()
+		}
+	}
+	adminJSON(w, http.StatusOK, res)
+}
+
+func (p *Provider) adminKeys(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		adminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	m, ok := p.adminCache(w, r)
+	if !ok {
+		return
+	}
+	limit := defaultAdminKeysLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			adminError(w, http.StatusBadRequest, "invalid limit")
+			return
+		}
+		limit = n
+	}
+	keys := make([]KeyInfo, 0)
+	err := m.dumpKeys(r.URL.Query().Get("prefix"), func(ki KeyInfo) error {
+		if len(keys) == limit {
+			return errAdminLimit
+		}
+		keys = append(keys, ki)
+		return nil
+	})
+	if err != nil && err != errAdminLimit {
+		adminError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	adminJSON(w, http.StatusOK, keys)
+}
+
+func (p *Provider) adminFlush(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		adminError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	m, ok := p.adminCache(w, r)
+	if !ok {
+		return
+	}
+	if m.flushesAll() && r.URL.Query().Get("all") != "true" {
+		adminError(w, http.StatusConflict, fmt.Sprintf("cache '%s' has no key_tracking, flush would flush all "+
+			"the memcache servers; confirm with all=true", m.Name()))
+		return
+	}
+	if err := m.Flush(); err != nil {
+		adminError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	adminJSON(w, http.StatusOK, map[string]string{"flushed": m.Name()})
+}
+
+func (p *Provider) adminCache(w http.ResponseWriter, r *http.Request) (*memcacheCache, bool) {
+	name := r.URL.Query().Get("cache")
+	m, found := p.cache(name)
+	if !found {
+		adminError(w, http.StatusNotFound, fmt.Sprintf("cache '%s' not found", name))
+	}
+	return m, found
+}
+
+func adminError(w http.ResponseWriter, code int, msg string) {
+	adminJSON(w, code, map[string]string{"error": msg})
+}
+
+func adminJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheAdminHandler(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(ioutil.Discard)
+	p := &Provider{name: "memcache1", logger: l}
+	m := &memcacheCache{cfg: &cache.Config{Name: "users"}, p: p, counters: new(counters)}
+	m.counters.hits = 3
+	p.addCache(m)
+	h := p.AdminHandler()
+
+	serve := func(method, target string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(method, target, nil))
+		return w
+	}
+
+	w := serve(http.MethodGet, "/admin/_cache/stats")
+	assert.Equal(t, http.StatusOK, w.Code)
+	var res adminStats
+	assert.Nil(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, "memcache1", res.Provider)
+	assert.Equal(t, uint64(3), res.Caches["users"].Hits)
+
+	assert.Equal(t, http.StatusMethodNotAllowed, serve(http.MethodGet, "/_cache/flush?cache=users").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodPost, "/_cache/flush?cache=orders").Code)
+	w = serve(http.MethodPost, "/_cache/flush?cache=users")
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.True(t, strings.Contains(w.Body.String(), "all=true"))
+	m.registry = newKeyRegistry(10)
+	assert.Equal(t, http.StatusOK, serve(http.MethodPost, "/_cache/flush?cache=users").Code)
+	assert.Equal(t, http.StatusBadRequest, serve(http.MethodGet, "/_cache/keys?cache=users&limit=x").Code)
+	assert.Equal(t, http.StatusNotFound, serve(http.MethodGet, "/_cache/unknown").Code)
+}
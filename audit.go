@@ -0,0 +1,95 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// EventOnAudit event is published for the destructive operations when
+// `audit.events` is enabled, event data is `AuditEvent`.
+const EventOnAudit = "cache:audit"
+
+// AuditEvent struct describes the destructive cache operation, i.e. Flush,
+// InvalidatePrefix and DeleteMulti.
+type AuditEvent struct {
+	Provider string
+	Cache    string
+	Op       string
+	Target   string // prefix of InvalidatePrefix
+	Keys     int    // number of keys deleted, -1 when unknown e.g. flush all
+	Caller   string // file:line of the app code
+	Err      error
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+const pkgPath = "aahframe.work/cache/provider/memcache."
+
+// audit method logs the destructive operation at INFO level along with its
+// caller, so that they stand out from routine traffic. Failed operations are
+// audited too.
+//
+//	cache {
+//	  mycache {
+//	    audit {
+//	      # default value is true
+//	      log = true
+//
+//	      # publishes `cache:audit` events via the event publisher;
+//	      # default value is false
+//	      events = false
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) audit(op, target string, keys int, err error) {
+	logIt, events := m.settingBool("audit.log", true), m.settingBool("audit.events", false)
+	if !logIt && !events {
+		return
+	}
+	e := AuditEvent{Provider: m.p.name, Cache: m.Name(), Op: op, Target: target,
+		Keys: keys, Caller: caller(), Err: err}
+	if logIt {
+		var details string
+		if target != "" {
+			details += " prefix(" + m.logKey(target) + ")"
+		}
+		if keys >= 0 {
+			details += " keys(" + strconv.Itoa(keys) + ")"
+		}
+		if err != nil {
+			m.p.logger.Infof("aah/cache/%s: audit %s%s caller(%s) failed: %v", e.Cache, op, details, e.Caller, err)
+		} else {
+			m.p.logger.Infof("aah/cache/%s: audit %s%s caller(%s)", e.Cache, op, details, e.Caller)
+		}
+	}
+	if events {
+		if pub := m.p.publisher(); pub != nil {
+			pub.PublishEvent(EventOnAudit, e)
+		}
+	}
+}
+
+// caller function returns the file:line of the first caller outside of the
+// provider package.
+func caller() string {
+	pc := make([]uintptr, 16)
+	n := runtime.Callers(3, pc)
+	frames := runtime.CallersFrames(pc[:n])
+	for {
+		f, more := frames.Next()
+		if !strings.HasPrefix(f.Function, pkgPath) || strings.HasSuffix(f.File, "_test.go") {
+			return filepath.Base(f.File) + ":" + strconv.Itoa(f.Line)
+		}
+		if !more {
+			return "unknown"
+		}
+	}
+}
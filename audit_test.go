@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheAudit(t *testing.T) {
+	cfg, _ := config.ParseString(`
+	cache {
+		memcache1 {
+			audit {
+				events = true
+			}
+		}
+	}
+`)
+	buf := new(bytes.Buffer)
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(buf)
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(cfg)
+	pub := &auditPublisher{}
+	p.SetEventPublisher(pub)
+	m := &memcacheCache{cfg: &cache.Config{Name: "auditcache"}, p: p}
+
+	m.audit("deletemulti", "", 3, nil)
+	m.audit("invalidateprefix", "user:42:", -1, errors.New("server error"))
+
+	out := buf.String()
+	assert.True(t, strings.Contains(out, "aah/cache/auditcache: audit deletemulti keys(3) caller(audit_test.go:"))
+	assert.True(t, strings.Contains(out, "audit invalidateprefix prefix(user:42:) caller("))
+	assert.True(t, strings.Contains(out, "failed: server error"))
+
+	assert.Equal(t, 2, len(pub.events))
+	assert.Equal(t, "deletemulti", pub.events[0].Op)
+	assert.Equal(t, 3, pub.events[0].Keys)
+	assert.Equal(t, "auditcache", pub.events[0].Cache)
+	assert.True(t, strings.HasPrefix(pub.events[0].Caller, "audit_test.go:"))
+	assert.Equal(t, "user:42:", pub.events[1].Target)
+	assert.NotNil(t, pub.events[1].Err)
+}
+
+func TestMemcacheCaller(t *testing.T) {
+	assert.True(t, strings.HasPrefix(callerOf(), "audit_test.go:"))
+}
+
+// callerOf mimics the provider method calling caller.
+func callerOf() string {
+	return caller()
+}
+
+type auditPublisher struct {
+	events []AuditEvent
+}
+
+func (a *auditPublisher) PublishEvent(name string, data interface{}) {
+	if name == EventOnAudit {
+		a.events = append(a.events, data.(AuditEvent))
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the largest buffer capacity retained by the buffer
+// pool, larger buffers are left to the GC so a single large Put does not pin
+// its buffer in the pool.
+const maxPooledBuffer = 1 << 20
+
+// bufferClasses are the capacities of the buffer pool size classes.
+var bufferClasses = [...]int{1 << 10, 4 << 10, 16 << 10, 64 << 10, 256 << 10, maxPooledBuffer}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+var bufPools [len(bufferClasses)]sync.Pool
+
+func init() {
+	for i := range bufPools {
+		size := bufferClasses[i]
+		bufPools[i].New = func() interface{} {
+			return bytes.NewBuffer(make([]byte, 0, size))
+		}
+	}
+}
+
+// acquireBuffer function returns the empty buffer of the smallest size class
+// fitting given size hint, hint over the largest class gets unpooled buffer.
+func acquireBuffer(hint int) *bytes.Buffer {
+	for i, size := range bufferClasses {
+		if hint <= size {
+			return bufPools[i].Get().(*bytes.Buffer)
+		}
+	}
+	return bytes.NewBuffer(make([]byte, 0, hint))
+}
+
+// releaseBuffer function returns the buffer to the largest size class its
+// capacity fills, buffers grown over `maxPooledBuffer` are dropped.
+func releaseBuffer(b *bytes.Buffer) {
+	if b == nil || b.Cap() > maxPooledBuffer {
+		return
+	}
+	for i := len(bufferClasses) - 1; i >= 0; i-- {
+		if b.Cap() >= bufferClasses[i] {
+			b.Reset()
+			bufPools[i].Put(b)
+			return
+		}
+	}
+}
+
+// readerPool holds the readers the gob payloads are decoded from. Gob
+// decoders are not pooled: each payload is a gob stream of its own carrying
+// the type descriptors, a reused decoder rejects them as duplicate types.
+var readerPool = sync.Pool{New: func() interface{} { return new(bytes.Reader) }}
+
+// acquireReader function returns the pooled reader over given bytes, it
+// reads the bytes in place.
+func acquireReader(b []byte) *bytes.Reader {
+	r := readerPool.Get().(*bytes.Reader)
+	r.Reset(b)
+	return r
+}
+
+// releaseReader function returns the reader to the pool, the reader must
+// not be used after.
+func releaseReader(r *bytes.Reader) {
+	r.Reset(nil)
+	readerPool.Put(r)
+}
+
+// sizeHint function returns the expected encoded size of the value, 0 if
+// unknown.
+func sizeHint(v interface{}) int {
+	switch t := v.(type) {
+	case []byte:
+		return len(t) + 64
+	case string:
+		return len(t) + 64
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheBufferPool(t *testing.T) {
+	buf := acquireBuffer(0)
+	assert.Equal(t, 0, buf.Len())
+	assert.True(t, buf.Cap() >= 1<<10)
+	releaseBuffer(buf)
+
+	buf = acquireBuffer(100 << 10)
+	assert.True(t, buf.Cap() >= 256<<10)
+	releaseBuffer(buf)
+
+	buf = acquireBuffer(4 << 20)
+	assert.True(t, buf.Cap() >= 4<<20)
+	releaseBuffer(buf)
+
+	// grown buffer goes to the class of its capacity
+	buf = acquireBuffer(0)
+	buf.Write(make([]byte, 20<<10))
+	releaseBuffer(buf)
+	assert.Equal(t, 0, buf.Len())
+
+	// over retained capacity is dropped, below smallest class too
+	releaseBuffer(bytes.NewBuffer(make([]byte, 0, 2<<20)))
+	releaseBuffer(new(bytes.Buffer))
+	releaseBuffer(nil)
+
+	r := acquireReader([]byte("abc"))
+	assert.Equal(t, 3, r.Len())
+	releaseReader(r)
+	assert.Equal(t, 0, r.Len())
+
+	assert.Equal(t, 0, sizeHint(42))
+	assert.Equal(t, 69, sizeHint("hello"))
+	assert.Equal(t, 67, sizeHint([]byte("abc")))
+}
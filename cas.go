@@ -0,0 +1,68 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Gets method returns the cached value of given key along with its CAS
+// token, error of class `ErrMiss` is returned if it does not exist. Token is
+// used with `PutCAS` for optimistic concurrency.
+func (m *memcacheCache) Gets(k string) (interface{}, CASToken, error) {
+	m.hot.observe(k)
+	o := m.begin("get", k)
+	mk := m.key(k)
+	v, err := m.client().Get(mk)
+	if err == memcache.ErrCacheMiss {
+		o.miss()
+		o.end(nil)
+		return nil, CASToken{}, m.opError("get", k, mk, nil, err)
+	}
+	if err != nil {
+		o.end(err)
+		return nil, CASToken{}, m.opError("get", k, mk, nil, err)
+	}
+	o.hit(len(v.Value))
+	o.end(nil)
+	e, found := m.decodeItem(k, v)
+	if !found || e.notFound {
+		return nil, CASToken{}, m.opError("get", k, mk, nil, memcache.ErrCacheMiss)
+	}
+	m.onRead(k, e)
+	return e.V, CASToken{item: v}, nil
+}
+
+// PutCAS method stores the value of given key only if it has not been
+// modified since given token was read via `Gets`. Error wrapping
+// `memcache.ErrCASConflict` is returned if it was modified, or
+// `memcache.ErrCacheMiss` if it was deleted meanwhile. Zero token adds the
+// value only if the key does not exist, `memcache.ErrNotStored` otherwise.
+//
+// Values over the chunk size cannot be stored atomically, they are rejected
+// with `ErrValueTooLarge`.
+func (m *memcacheCache) PutCAS(k string, v interface{}, d time.Duration, token CASToken) error {
+	e, err := m.newEntry(k, v, d)
+	if err != nil {
+		return err
+	}
+	return m.putCASEntry(k, e, token)
+}
+
+// putCASEntry method stores the entry via add for zero token, otherwise
+// via compare-and-swap.
+func (m *memcacheCache) putCASEntry(k string, e *entry, token CASToken) error {
+	if token.IsZero() {
+		return m.storeEntryWith(context.Background(), m.client().Add, k, e, false)
+	}
+	return m.storeEntryWith(context.Background(), func(item *memcache.Item) error {
+		ci := *token.item
+		ci.Key, ci.Value, ci.Flags, ci.Expiration = item.Key, item.Value, item.Flags, item.Expiration
+		return m.client().CompareAndSwap(&ci)
+	}, k, e, false)
+}
@@ -0,0 +1,54 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheGetsPutCAS(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		cas {
+			chunk_size = "1KB"
+		}
+	}
+`, &cache.Config{Name: "cas", ProviderName: "memcache1"}).(Cache)
+
+	_, token, err := c.Gets("key1")
+	assert.True(t, errors.Is(err, ErrMiss))
+	assert.True(t, token.IsZero())
+
+	// zero token adds if absent
+	assert.Nil(t, c.PutCAS("key1", 1, time.Minute, token))
+	assert.NotNil(t, c.PutCAS("key1", 2, time.Minute, token))
+
+	v, token, err := c.Gets("key1")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, v)
+
+	_, other, _ := c.Gets("key1")
+	assert.Nil(t, c.PutCAS("key1", 2, time.Minute, other))
+	err = c.PutCAS("key1", 3, time.Minute, token)
+	assert.True(t, errors.Is(err, memcache.ErrCASConflict))
+	assert.Equal(t, 2, c.Get("key1"))
+
+	_, token, _ = c.Gets("key1")
+	err = c.PutCAS("key1", strings.Repeat("x", 2048), time.Minute, token)
+	assert.True(t, errors.Is(err, ErrValueTooLarge))
+
+	assert.Nil(t, c.Delete("key1"))
+}
@@ -0,0 +1,175 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Chaos latency bounds, the provider refuses the configuration beyond them
+// so the chaos could be left enabled in production.
+const (
+	maxChaosRate    = 0.05
+	maxChaosLatency = 500 * time.Millisecond
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// chaosLatency adds artificial latency to the small fraction of memcache
+// calls, to continuously validate the timeout and hedging settings of the
+// app. Unlike `FaultPolicy` it never fails the call. The rate is capped at
+// 5% and the added latency at 500ms. Delayed calls are counted in
+// `Stats.ChaosDelays` and `Stats.ChaosLatency`, and published under the
+// `chaos_delays` expvar counter of the cache.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//	    chaos {
+//	      # default value is false
+//	      enable = true
+//
+//	      # fraction of the calls delayed; default value is 0.001
+//	      rate = 0.01
+//
+//	      # default value is 10ms, plus random jitter up to given value
+//	      latency = "10ms"
+//	      jitter = "20ms"
+//	    }
+//	  }
+//	}
+type chaosLatency struct {
+	rate    float64
+	latency time.Duration
+	jitter  time.Duration
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// newChaosLatency method returns the chaos latency of the provider config,
+// nil if it is not enabled.
+func newChaosLatency(p *Provider) (*chaosLatency, error) {
+	cfgPrefix := "cache." + p.name + ".chaos."
+	cfg := p.config()
+	if !cfg.BoolDefault(cfgPrefix+"enable", false) {
+		return nil, nil
+	}
+	c := &chaosLatency{
+		latency: parseDuration(cfg.StringDefault(cfgPrefix+"latency", ""), "10ms"),
+		jitter:  parseDuration(cfg.StringDefault(cfgPrefix+"jitter", ""), "0s"),
+		rnd:     rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+	var err error
+	if c.rate, err = strconv.ParseFloat(cfg.StringDefault(cfgPrefix+"rate", "0.001"), 64); err != nil {
+		return nil, fmt.Errorf("aah/cache/%s: chaos rate %v", p.name, err)
+	}
+	if c.rate <= 0 || c.rate > maxChaosRate {
+		return nil, fmt.Errorf("aah/cache/%s: chaos rate must be within (0, %v]", p.name, maxChaosRate)
+	}
+	if c.latency < 0 || c.jitter < 0 || c.latency+c.jitter > maxChaosLatency {
+		return nil, fmt.Errorf("aah/cache/%s: chaos latency plus jitter must be within %v", p.name, maxChaosLatency)
+	}
+	p.logger.Warnf("aah/cache/provider: %s chaos latency enabled, rate %v, latency %v, jitter %v",
+		p.name, c.rate, c.latency, c.jitter)
+	return c, nil
+}
+
+// delay method returns the latency to add to the call, 0 if the call is not
+// selected.
+func (c *chaosLatency) delay() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rnd.Float64() >= c.rate {
+		return 0
+	}
+	d := c.latency
+	if c.jitter > 0 {
+		d += time.Duration(c.rnd.Int63n(int64(c.jitter)))
+	}
+	return d
+}
+
+// chaosClient delays the memcache calls selected by the chaos latency.
+type chaosClient struct {
+	client
+	c *chaosLatency
+	m *memcacheCache
+}
+
+func (cc chaosClient) wait() {
+	d := cc.c.delay()
+	if d <= 0 {
+		return
+	}
+	atomic.AddUint64(&cc.m.counters.chaosDelays, 1)
+	atomic.AddInt64(&cc.m.counters.chaosLatency, int64(d))
+	cc.m.vars.add("chaos_delays", 1)
+	time.Sleep(d)
+}
+
+func (cc chaosClient) Get(key string) (*memcache.Item, error) {
+	cc.wait()
+	return cc.client.Get(key)
+}
+
+func (cc chaosClient) metaGet(key, flags string) (string, error) {
+	cc.wait()
+	return cc.client.metaGet(key, flags)
+}
+
+func (cc chaosClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	cc.wait()
+	return cc.client.GetMulti(keys)
+}
+
+func (cc chaosClient) Set(item *memcache.Item) error {
+	cc.wait()
+	return cc.client.Set(item)
+}
+
+func (cc chaosClient) Add(item *memcache.Item) error {
+	cc.wait()
+	return cc.client.Add(item)
+}
+
+func (cc chaosClient) Replace(item *memcache.Item) error {
+	cc.wait()
+	return cc.client.Replace(item)
+}
+
+func (cc chaosClient) CompareAndSwap(item *memcache.Item) error {
+	cc.wait()
+	return cc.client.CompareAndSwap(item)
+}
+
+func (cc chaosClient) Delete(key string) error {
+	cc.wait()
+	return cc.client.Delete(key)
+}
+
+func (cc chaosClient) Increment(key string, delta uint64) (uint64, error) {
+	cc.wait()
+	return cc.client.Increment(key, delta)
+}
+
+func (cc chaosClient) Decrement(key string, delta uint64) (uint64, error) {
+	cc.wait()
+	return cc.client.Decrement(key, delta)
+}
+
+func (cc chaosClient) Touch(key string, seconds int32) error {
+	cc.wait()
+	return cc.client.Touch(key, seconds)
+}
@@ -0,0 +1,76 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheChaosLatency(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(config.NewEmpty())
+	c, err := newChaosLatency(p)
+	assert.Nil(t, err)
+	assert.Nil(t, c)
+
+	m := &memcacheCache{p: p, counters: new(counters)}
+	_, ok := m.client().(chaosClient)
+	assert.False(t, ok)
+
+	p.chaos = &chaosLatency{rate: 1, latency: 10 * time.Millisecond, jitter: 5 * time.Millisecond,
+		rnd: rand.New(rand.NewSource(1))}
+	cc, ok := m.client().(chaosClient)
+	assert.True(t, ok)
+	cc.client = &dryRunClient{}
+
+	start := time.Now()
+	assert.Nil(t, cc.Set(&memcache.Item{Key: "key1"}))
+	_, err = cc.Get("key1")
+	assert.Equal(t, memcache.ErrCacheMiss, err)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+
+	s := m.Stats()
+	assert.Equal(t, uint64(2), s.ChaosDelays)
+	assert.True(t, s.ChaosLatency >= 20*time.Millisecond && s.ChaosLatency < 30*time.Millisecond)
+
+	p.chaos.rate = 0.01
+	delayed := 0
+	for i := 0; i < 10000; i++ {
+		if p.chaos.delay() > 0 {
+			delayed++
+		}
+	}
+	assert.True(t, delayed > 50 && delayed < 150)
+}
+
+func TestMemcacheChaosLatencyBounds(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	for _, tc := range []struct{ cfg, err string }{
+		{`rate = 0.2`, "aah/cache/memcache1: chaos rate must be within (0, 0.05]"},
+		{`latency = "400ms", jitter = "200ms"`, "aah/cache/memcache1: chaos latency plus jitter must be within 500ms"},
+		{`rate = 0.01`, ""},
+	} {
+		cfg, _ := config.ParseString(`cache { memcache1 { chaos { enable = true
+		` + tc.cfg + ` } } }`)
+		p := &Provider{name: "memcache1", logger: l}
+		p.appCfg.Store(cfg)
+		c, err := newChaosLatency(p)
+		if tc.err == "" {
+			assert.Nil(t, err)
+			assert.Equal(t, 0.01, c.rate)
+			assert.Equal(t, 10*time.Millisecond, c.latency)
+			continue
+		}
+		assert.Equal(t, tc.err, err.Error())
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// flagChunked flag marks the memcache item as manifest of the chunked value.
+const flagChunked uint32 = 1 << 4
+
+// defaultChunkSize stays below memcache default item size limit of 1MB,
+// leaving room for the key and item overhead.
+const defaultChunkSize = 1000 * 1024
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// chunkSize method returns the encoded value size above which the value is
+// split into chunks. Chunks are stored as separate items and the entry key
+// holds the manifest with chunk count, size, SHA-256 checksum of the value
+// and the generation of the chunks; Get reassembles and verifies the value.
+// Every write stores its chunks under a new generation, so concurrent
+// overwrites of the key never mix their chunks. Chunks carry the entry
+// expiration, Delete and overwrite leave the old chunks to expire or get
+// evicted. Oversize policy `chunk` chunks the values above
+// `max_value_size` too.
+//
+//	cache {
+//	  mycache {
+//	    # size in bytes or with KB, MB suffix; default value is 1000KB
+//	    chunk_size = "1000KB"
+//	  }
+//	}
+//
+// Values are not chunked in the interop mode.
+func (m *memcacheCache) chunkSize() int {
+	if m.interop != interopNone {
+		return math.MaxInt32
+	}
+	n := m.chunkLimit
+	if m.size.policy == oversizeChunk && m.size.max > 0 && m.size.max < n {
+		n = m.size.max
+	}
+	return n
+}
+
+// storeChunked method stores the encoded value of the item as chunks, then
+// stores the manifest using given func.
+func (m *memcacheCache) storeChunked(fn func(*memcache.Item) error, k string, item *memcache.Item) error {
+	size := m.chunkSize()
+	value := item.Value
+	n := (len(value) + size - 1) / size
+	gen := newChunkGen()
+	for i := 0; i < n; i++ {
+		end := (i + 1) * size
+		if end > len(value) {
+			end = len(value)
+		}
+		err := m.client().Set(&memcache.Item{
+			Key:        chunkKey(item.Key, gen, i),
+			Value:      value[i*size : end],
+			Expiration: item.Expiration,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	sum := sha256.Sum256(value)
+	return fn(manifestItem(item.Key, manifest{n: n, size: len(value), sum: hex.EncodeToString(sum[:]), gen: gen},
+		item.Flags, item.Expiration))
+}
+
+// loadChunks method returns the value reassembled from the chunks of given
+// manifest item.
+func (m *memcacheCache) loadChunks(v *memcache.Item) ([]byte, error) {
+	mf, err := parseManifest(v)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make([]string, mf.n)
+	for i := range keys {
+		keys[i] = chunkKey(v.Key, mf.gen, i)
+	}
+	items, err := m.client().GetMulti(keys)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, mf.size))
+	for _, ck := range keys {
+		item, found := items[ck]
+		if !found {
+			return nil, memcache.ErrCacheMiss
+		}
+		buf.Write(item.Value)
+	}
+
+	vsum := sha256.Sum256(buf.Bytes())
+	if buf.Len() != mf.size || hex.EncodeToString(vsum[:]) != mf.sum {
+		return nil, errChecksum
+	}
+	return buf.Bytes(), nil
+}
+
+// touchChunks method extends the expiration of the chunks of given manifest
+// item.
+func (m *memcacheCache) touchChunks(k string, v *memcache.Item, d int32) {
+	mf, err := parseManifest(v)
+	if err != nil {
+		return
+	}
+	for i := 0; i < mf.n; i++ {
+		m.touch(k, chunkKey(v.Key, mf.gen, i), d)
+	}
+}
+
+var (
+	errManifest = &corruptError{errors.New("invalid chunk manifest")}
+	errChecksum = &corruptError{errors.New("chunked value checksum mismatch")}
+)
+
+// manifest describes the chunked value: chunk count, value size, hex
+// SHA-256 checksum and the generation in the chunk keys, empty for the
+// manifest written without it.
+type manifest struct {
+	n    int
+	size int
+	sum  string
+	gen  string
+}
+
+func manifestItem(mk string, mf manifest, flags uint32, exp int32) *memcache.Item {
+	return &memcache.Item{
+		Key:        mk,
+		Value:      []byte(fmt.Sprintf("%d %d %s %s", mf.n, mf.size, mf.sum, mf.gen)),
+		Flags:      flags | flagChunked,
+		Expiration: exp,
+	}
+}
+
+// parseManifest function returns the manifest of given manifest item.
+func parseManifest(v *memcache.Item) (manifest, error) {
+	parts := strings.Fields(string(v.Value))
+	if len(parts) != 3 && len(parts) != 4 {
+		return manifest{}, errManifest
+	}
+	n, err1 := strconv.Atoi(parts[0])
+	size, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || n < 0 || size < 0 {
+		return manifest{}, errManifest
+	}
+	mf := manifest{n: n, size: size, sum: parts[2]}
+	if len(parts) == 4 {
+		mf.gen = parts[3]
+	}
+	return mf, nil
+}
+
+// newChunkGen function returns the random generation of the chunks of a
+// write.
+func newChunkGen() string {
+	b := make([]byte, 6)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// chunkKey function returns the key of i-th chunk of given generation.
+func chunkKey(mk, gen string, i int) string {
+	ck := mk + "~"
+	if gen != "" {
+		ck += gen + "~"
+	}
+	ck += strconv.Itoa(i)
+	if len(ck) > maxKeyLength {
+		sum := sha256.Sum256([]byte(ck))
+		return "#" + base64.RawURLEncoding.EncodeToString(sum[:])
+	}
+	return ck
+}
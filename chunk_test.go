@@ -0,0 +1,82 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheChunkKey(t *testing.T) {
+	assert.Equal(t, "page-key1~0", chunkKey("page-key1", "", 0))
+	assert.Equal(t, "page-key1~g1~12", chunkKey("page-key1", "g1", 12))
+	assert.True(t, isChunkKey(chunkKey("page-key1", newChunkGen(), 3)))
+	assert.NotEqual(t, newChunkGen(), newChunkGen())
+
+	ck := chunkKey(strings.Repeat("k", maxKeyLength), "g1", 1)
+	assert.True(t, strings.HasPrefix(ck, "#"))
+	assert.True(t, len(ck) <= maxKeyLength)
+}
+
+func TestMemcacheChunkManifest(t *testing.T) {
+	item := manifestItem("page-key1", manifest{n: 2, size: 10, sum: "ab", gen: "g1"}, 0, 60)
+	assert.Equal(t, flagChunked, item.Flags)
+	mf, err := parseManifest(item)
+	assert.Nil(t, err)
+	assert.Equal(t, manifest{n: 2, size: 10, sum: "ab", gen: "g1"}, mf)
+
+	mf, err = parseManifest(&memcache.Item{Value: []byte("2 10 ab")})
+	assert.Nil(t, err)
+	assert.Equal(t, "", mf.gen)
+
+	_, err = parseManifest(&memcache.Item{Value: []byte("2 10")})
+	assert.Equal(t, errManifest, err)
+}
+
+func TestMemcacheChunkSize(t *testing.T) {
+	m := &memcacheCache{chunkLimit: defaultChunkSize}
+	assert.Equal(t, defaultChunkSize, m.chunkSize())
+
+	m.size = sizeLimit{max: 1024, policy: oversizeChunk}
+	assert.Equal(t, 1024, m.chunkSize())
+
+	m.size.policy = oversizeReject
+	assert.Equal(t, defaultChunkSize, m.chunkSize())
+}
+
+func TestMemcacheChunkedValue(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		chunkcache {
+			chunk_size = "1KB"
+		}
+	}
+`, &cache.Config{Name: "chunkcache", ProviderName: "memcache1"})
+
+	page := strings.Repeat("<p>rendered page</p>", 500)
+	assert.Nil(t, c.Put("page1", page, 3*time.Second))
+	assert.Equal(t, page, c.Get("page1"))
+
+	mc := c.(*memcacheCache)
+	item, err := mc.p.Client().Get("chunkcache-page1")
+	assert.Nil(t, err)
+	assert.Equal(t, flagChunked, item.Flags&flagChunked)
+
+	// missing chunk is a cache miss
+	mf, _ := parseManifest(item)
+	assert.Nil(t, mc.p.Client().Delete(chunkKey("chunkcache-page1", mf.gen, 1)))
+	assert.Nil(t, c.Get("page1"))
+
+	assert.Nil(t, c.Delete("page1"))
+}
@@ -0,0 +1,157 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+)
+
+const cliUsage = `usage: aah-cache <command> [flags] [args]
+
+commands:
+  list [prefix]    lists the keys of the cache
+  get <key>        prints the value of the key
+  delete <key>...  deletes the keys
+  flush            flushes the cache, cache without key_tracking requires
+                   --all since it flushes all the memcache servers
+
+flags:
+`
+
+// RunCommand method runs the cache management command of given args against
+// the memcache provider configured in given app config, output is written
+// to given writer. It backs the `aah-cache` command, flags are accepted
+// before and after the command as `aah cache <command> --cache <name>`.
+//
+//	aah-cache list --provider memcache1 --cache users user:
+//	aah-cache get --provider memcache1 --cache users user:1
+//	aah-cache delete --provider memcache1 --cache users user:1 user:2
+//	aah-cache flush --provider memcache1 --cache users
+func RunCommand(appCfg *config.Config, logger log.Loggerer, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("aah-cache", flag.ContinueOnError)
+	fs.SetOutput(w)
+	providerName := fs.String("provider", "memcache", "cache provider name in the app config")
+	cacheName := fs.String("cache", "", "cache name")
+	all := fs.Bool("all", false, "confirms flush of all the memcache servers")
+	fs.Usage = func() {
+		fmt.Fprint(w, cliUsage)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("aah/cache: cache name and command are required")
+	}
+	cmd := fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if *cacheName == "" {
+		fs.Usage()
+		return errors.New("aah/cache: cache name and command are required")
+	}
+
+	p := new(Provider)
+	if err := p.Init(*providerName, appCfg, logger); err != nil {
+		return err
+	}
+	c, err := p.Create(&cache.Config{Name: *cacheName, ProviderName: *providerName})
+	if err != nil {
+		return err
+	}
+	m, _ := Extended(c)
+	mc := m.(*memcacheCache)
+
+	cargs := fs.Args()
+	switch cmd {
+	case "list":
+		prefix := ""
+		if len(cargs) > 0 {
+			prefix = cargs[0]
+		}
+		return mc.dumpKeys(prefix, func(ki KeyInfo) error {
+			exp := "never"
+			if !ki.Expiration.IsZero() {
+				exp = ki.Expiration.Format(time.RFC3339)
+			}
+			_, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", ki.Key, ki.Server, ki.Size, exp)
+			return err
+		})
+	case "get":
+		if len(cargs) != 1 {
+			return errors.New("aah/cache: get requires a key")
+		}
+		v := mc.Get(cargs[0])
+		if v == nil {
+			return mc.opError("get", cargs[0], mc.key(cargs[0]), ErrMiss, errors.New("key not found"))
+		}
+		_, err = fmt.Fprintf(w, "%v\n", v)
+		return err
+	case "delete":
+		if len(cargs) == 0 {
+			return errors.New("aah/cache: delete requires keys")
+		}
+		if err = mc.DeleteMulti(cargs); err == nil {
+			_, err = fmt.Fprintf(w, "deleted %d key(s)\n", len(cargs))
+		}
+		return err
+	case "flush":
+		if mc.flushesAll() && !*all {
+			return fmt.Errorf("aah/cache: cache %s has no key_tracking, flush would flush all the memcache "+
+				"servers; confirm with --all", mc.Name())
+		}
+		if err = mc.Flush(); err == nil {
+			_, err = fmt.Fprintf(w, "flushed cache %s\n", mc.Name())
+		}
+		return err
+	}
+	fs.Usage()
+	return fmt.Errorf("aah/cache: unknown command '%s'", cmd)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// dumpKeys method calls given func for the keys of this cache having given
+// prefix, key of `KeyInfo` is the cache key without the cache key prefix.
+// Hashed long keys are reported as is.
+func (m *memcacheCache) dumpKeys(prefix string, fn func(KeyInfo) error) error {
+	return m.p.DumpKeys(m.keyPrefix+prefix, func(ki KeyInfo) error {
+		k := strings.TrimPrefix(ki.Key, m.keyPrefix)
+		if strings.HasPrefix(k, "~") || isChunkKey(k) {
+			// locks, counters and chunks of the cache
+			return nil
+		}
+		ki.Key = k
+		return fn(ki)
+	})
+}
+
+// isChunkKey function reports whether given key has the chunk key suffix
+// e.g. "report~3".
+func isChunkKey(k string) bool {
+	i := strings.LastIndexByte(k, '~')
+	if i < 0 || i == len(k)-1 {
+		return false
+	}
+	for _, c := range k[i+1:] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
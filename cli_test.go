@@ -0,0 +1,79 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheRunCommand(t *testing.T) {
+	cfg, err := config.ParseString(`
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}`)
+	assert.Nil(t, err)
+	l, _ := log.New(config.NewEmpty())
+
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "cli", ProviderName: "memcache1"})
+	assert.Nil(t, c.Put("key1", "value1", time.Minute))
+
+	run := func(args ...string) (string, error) {
+		var buf bytes.Buffer
+		err := RunCommand(cfg, l, append([]string{"--provider", "memcache1", "--cache", "cli"}, args...), &buf)
+		return buf.String(), err
+	}
+
+	out, err := run("get", "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "value1\n", out)
+
+	out, err = run("list")
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(out, "key1\t"))
+
+	_, err = run("delete", "key1")
+	assert.Nil(t, err)
+	_, err = run("get", "key1")
+	assert.NotNil(t, err)
+
+	_, err = run("flush")
+	assert.NotNil(t, err)
+	assert.True(t, strings.Contains(err.Error(), "--all"))
+
+	// flags after the command
+	var buf bytes.Buffer
+	assert.Nil(t, RunCommand(cfg, l, []string{"list", "--provider", "memcache1", "--cache", "cli"}, &buf))
+	assert.Equal(t, "", buf.String())
+
+	_, err = run("unknown")
+	assert.NotNil(t, err)
+	err = RunCommand(cfg, l, []string{"list"}, new(bytes.Buffer))
+	assert.NotNil(t, err)
+}
+
+func TestMemcacheIsChunkKey(t *testing.T) {
+	assert.True(t, isChunkKey("report~3"))
+	assert.False(t, isChunkKey("report~"))
+	assert.False(t, isChunkKey("a~b"))
+	assert.False(t, isChunkKey("report"))
+}
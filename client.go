@@ -0,0 +1,73 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// client interface is the subset of `memcache.Client` used by the cache, so
+// that the cache modes can intercept the memcache calls.
+type client interface {
+	Get(key string) (*memcache.Item, error)
+	GetMulti(keys []string) (map[string]*memcache.Item, error)
+	Set(item *memcache.Item) error
+	Add(item *memcache.Item) error
+	Replace(item *memcache.Item) error
+	CompareAndSwap(item *memcache.Item) error
+	Delete(key string) error
+	Increment(key string, delta uint64) (uint64, error)
+	Decrement(key string, delta uint64) (uint64, error)
+	Touch(key string, seconds int32) error
+	FlushAll() error
+	metaGet(key, flags string) (string, error)
+}
+
+// client method returns the memcache client of the cache for its mode.
+func (m *memcacheCache) client() client {
+	return m.clientContext(context.Background())
+}
+
+// clientContext method is `client` whose memcache calls are cut at the
+// deadline of given context, see `Provider.deadlineClient`.
+func (m *memcacheCache) clientContext(ctx context.Context) client {
+	if m.p.dryRun != nil {
+		return m.p.dryRun
+	}
+	mc := m.p.Client()
+	if deadline, ok := ctx.Deadline(); ok {
+		mc = m.p.deadlineClient(deadline)
+	}
+	var c client = metaClient{Client: mc, p: m.p}
+	if m.p.inflight != nil {
+		c = inflightClient{client: c, l: m.p.inflight, m: m}
+	}
+	if fp := m.p.faultPolicy(); fp != nil {
+		c = faultClient{client: c, fp: fp}
+	}
+	if m.p.chaos != nil {
+		c = chaosClient{client: c, c: m.p.chaos, m: m}
+	}
+	if h := m.p.health; h != nil {
+		if m.lowPriority && h.shedding() {
+			c = shedClient{client: c, m: m}
+		} else {
+			c = healthClient{client: c, h: h}
+		}
+	}
+	if m.labels != nil {
+		c = profileClient{client: c, pl: m.labels}
+	}
+	if m.readOnly {
+		return readOnlyClient{client: c, m: m}
+	}
+	return c
+}
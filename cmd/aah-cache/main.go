@@ -0,0 +1,41 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Command aah-cache manages the memcache caches of an aah application, see
+// `memcache.RunCommand` for the commands.
+//
+//	aah-cache --app-config config/aah.conf list --provider memcache1 --cache users
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"aahframe.work/cache/provider/memcache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+)
+
+func main() {
+	args := os.Args[1:]
+	cfgFile := "config/aah.conf"
+	if len(args) > 1 && (args[0] == "--app-config" || args[0] == "-app-config") {
+		cfgFile, args = args[1], args[2:]
+	}
+
+	appCfg, err := config.LoadFile(cfgFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "aah-cache: %s\n", err)
+		os.Exit(1)
+	}
+	logger, err := log.New(config.NewEmpty())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "aah-cache: %s\n", err)
+		os.Exit(1)
+	}
+	if err = memcache.RunCommand(appCfg, logger, args, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "aah-cache: %s\n", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CodecFailure struct describes the encode or decode failures of a Go type,
+// e.g. the type not registered with gob or the schema drift across the app
+// versions. Type is `unknown` if it could not be determined from the
+// payload.
+type CodecFailure struct {
+	Op        string // encode or decode
+	Type      string
+	Count     uint64
+	LastError string
+	Last      time.Time
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// unknownType is the type name of the failures whose value type is unknown.
+const unknownType = "unknown"
+
+var errFastMismatch = errors.New("fast codec type is not registered or its layout differs")
+
+type codecFailure struct {
+	mu sync.Mutex
+	CodecFailure
+}
+
+// codecFailed method records the failure of given op and type name into the
+// codec failure catalog, `Stats.CodecFailures`. Failures are counted
+// regardless of the error log rate limit, so the failing types are visible
+// before the logs are flooded.
+func (c *counters) codecFailed(op, typ string, err error) {
+	if c == nil {
+		return
+	}
+	if typ == "" {
+		typ = unknownType
+	}
+	v, found := c.codecs.Load(op + " " + typ)
+	if !found {
+		v, _ = c.codecs.LoadOrStore(op+" "+typ, &codecFailure{CodecFailure: CodecFailure{Op: op, Type: typ}})
+	}
+	cf := v.(*codecFailure)
+	cf.mu.Lock()
+	cf.Count++
+	cf.LastError = err.Error()
+	cf.Last = time.Now()
+	cf.mu.Unlock()
+}
+
+func (c *counters) codecFailures() []CodecFailure {
+	var result []CodecFailure
+	c.codecs.Range(func(_, v interface{}) bool {
+		cf := v.(*codecFailure)
+		cf.mu.Lock()
+		result = append(result, cf.CodecFailure)
+		cf.mu.Unlock()
+		return true
+	})
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Op+result[i].Type < result[j].Op+result[j].Type
+	})
+	return result
+}
+
+// encodeFailed method records the encode failure of given value and returns
+// the error of `ErrEncode` class.
+func (m *memcacheCache) encodeFailed(k string, v interface{}, err error) error {
+	m.counters.codecFailed("encode", schemaHint(v), err)
+	return m.opError("put", k, "", ErrEncode, err)
+}
+
+// gobTypeName function returns the type name of the gob decode error of the
+// type not registered, empty otherwise.
+func gobTypeName(err error) string {
+	const marker = "name not registered for interface: "
+	if err == nil {
+		return ""
+	}
+	msg := err.Error()
+	i := strings.Index(msg, marker)
+	if i < 0 {
+		return ""
+	}
+	return strings.Trim(msg[i+len(marker):], `"`)
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type codecUnregistered struct{ Name string }
+
+func TestMemcacheCodecFailures(t *testing.T) {
+	m := newBenchCache()
+
+	err := m.encodeFailed("user", codecUnregistered{}, errors.New("gob: type not registered"))
+	assert.True(t, errors.Is(err, ErrEncode))
+	m.encodeFailed("user", codecUnregistered{}, errors.New("gob: type not registered again"))
+	m.undecodable("order", m.key("order"), errors.New(`gob: name not registered for interface: "main.Order"`))
+	m.undecodable("raw", m.key("raw"), errors.New("unexpected EOF"))
+
+	failures := m.Stats().CodecFailures
+	assert.Equal(t, 3, len(failures))
+	assert.Equal(t, "encode", failures[0].Op)
+	assert.Equal(t, schemaHint(codecUnregistered{}), failures[0].Type)
+	assert.Equal(t, uint64(2), failures[0].Count)
+	assert.Equal(t, "gob: type not registered again", failures[0].LastError)
+	assert.False(t, failures[0].Last.IsZero())
+	assert.Equal(t, "decode", failures[1].Op)
+	assert.Equal(t, "main.Order", failures[1].Type)
+	assert.Equal(t, unknownType, failures[2].Type)
+	assert.Equal(t, uint64(2), m.Stats().DecodeFailures)
+
+	var c *counters
+	c.codecFailed("encode", "x", err)
+}
+
+func TestMemcacheGobTypeName(t *testing.T) {
+	assert.Equal(t, "", gobTypeName(nil))
+	assert.Equal(t, "", gobTypeName(errors.New("EOF")))
+	assert.Equal(t, "a.B", gobTypeName(errors.New(`gob: name not registered for interface: "a.B"`)))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/rand"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// maxReportedKeys is the max number of divergent keys listed in the report.
+const maxReportedKeys = 100
+
+// ConsistencyReport struct holds the outcome of `CheckConsistency`.
+type ConsistencyReport struct {
+	Sampled  int
+	Matched  int
+	Missing  int // present in this cluster, absent in the secondary
+	Diverged int // present in both, value or flags differ
+
+	// Keys lists the missing and diverged keys, up to 100.
+	Keys []string
+}
+
+// CheckConsistency method samples up to given number of keys having given
+// key prefix from this provider cluster and compares the digest of their
+// value and flags with the secondary cluster, it is meant to validate the
+// dual-write migrations. Keys are sampled uniformly over the key listing,
+// keys missing in this cluster by the time of fetch are not counted.
+func (p *Provider) CheckConsistency(secondary *Provider, prefix string, sample int) (ConsistencyReport, error) {
+	var r ConsistencyReport
+	if sample < 1 {
+		return r, nil
+	}
+	keys, err := p.sampleKeys(prefix, sample)
+	if err != nil {
+		return r, err
+	}
+	for len(keys) > 0 {
+		n := dumpBatchSize
+		if n > len(keys) {
+			n = len(keys)
+		}
+		batch := keys[:n]
+		keys = keys[n:]
+
+		primary, err := p.Client().GetMulti(batch)
+		if err != nil {
+			return r, fmt.Errorf("aah/cache/%s: consistency %v", p.name, err)
+		}
+		other, err := secondary.Client().GetMulti(batch)
+		if err != nil {
+			return r, fmt.Errorf("aah/cache/%s: consistency %v", secondary.name, err)
+		}
+		for _, k := range batch {
+			pi, found := primary[k]
+			if !found {
+				continue
+			}
+			r.Sampled++
+			si, found := other[k]
+			switch {
+			case !found:
+				r.Missing++
+			case itemDigest(pi) != itemDigest(si):
+				r.Diverged++
+			default:
+				r.Matched++
+				continue
+			}
+			if len(r.Keys) < maxReportedKeys {
+				r.Keys = append(r.Keys, k)
+			}
+		}
+	}
+	return r, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// sampleKeys method returns up to n keys having given prefix via reservoir
+// sampling of the key listing.
+func (p *Provider) sampleKeys(prefix string, n int) ([]string, error) {
+	keys := make([]string, 0, n)
+	seen := 0
+	err := p.DumpKeys(prefix, func(ki KeyInfo) error {
+		seen++
+		if len(keys) < n {
+			keys = append(keys, ki.Key)
+		} else if i := rand.Intn(seen); i < n {
+			keys[i] = ki.Key
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// itemDigest function returns the digest of item value and flags.
+func itemDigest(item *memcache.Item) [sha256.Size]byte {
+	h := sha256.New()
+	var flags [4]byte
+	binary.BigEndian.PutUint32(flags[:], item.Flags)
+	_, _ = h.Write(flags[:])
+	_, _ = h.Write(item.Value)
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheCheckConsistency(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "consistency", ProviderName: "memcache1"})
+	m := c.(*memcacheCache)
+	for _, k := range []string{"key1", "key2", "key3"} {
+		assert.Nil(t, c.Put(k, k, time.Minute))
+	}
+
+	// same cluster is consistent with itself
+	r, err := m.p.CheckConsistency(m.p, m.keyPrefix, 2)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, r.Sampled)
+	assert.Equal(t, 2, r.Matched)
+	assert.Len(t, r.Keys, 0)
+
+	assert.Nil(t, c.Flush())
+}
+
+func TestMemcacheItemDigest(t *testing.T) {
+	a := &memcache.Item{Value: []byte("v"), Flags: 1}
+	assert.Equal(t, itemDigest(a), itemDigest(&memcache.Item{Value: []byte("v"), Flags: 1}))
+	assert.NotEqual(t, itemDigest(a), itemDigest(&memcache.Item{Value: []byte("v"), Flags: 2}))
+	assert.NotEqual(t, itemDigest(a), itemDigest(&memcache.Item{Value: []byte("w"), Flags: 1}))
+}
@@ -0,0 +1,91 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// CopyOptions struct holds the options of `CopyTo`.
+type CopyOptions struct {
+	// Rate limits the items written to the target per second, 0 means
+	// unlimited.
+	Rate int
+
+	// Overwrite overwrites the items already present in the target,
+	// otherwise they are kept.
+	Overwrite bool
+
+	// Progress func is called after every copied item with the number of
+	// items copied so far, optional.
+	Progress func(copied int)
+}
+
+// CopyStats struct holds the outcome of `CopyTo`.
+type CopyStats struct {
+	Copied  int
+	Skipped int // present in the target or expired meanwhile
+	Failed  int
+}
+
+// CopyTo method streams the memcache items having given key prefix from
+// this provider cluster to the target provider cluster, the remaining TTL of
+// the items is preserved. Items are copied as stored, chunks and provider
+// envelope included, so the caches read them on the target as is.
+//
+// Copy continues on write failures of single items, they are counted in
+// `CopyStats.Failed`. Error is returned if keys cannot be listed or fetched.
+func (p *Provider) CopyTo(target *Provider, prefix string, opts CopyOptions) (CopyStats, error) {
+	var (
+		stats    CopyStats
+		last     time.Time
+		interval time.Duration
+	)
+	if opts.Rate > 0 {
+		interval = time.Second / time.Duration(opts.Rate)
+	}
+	err := p.scanItems(prefix, func(item *memcache.Item, exp time.Time) error {
+		var unix int64
+		if !exp.IsZero() {
+			unix = exp.Unix()
+		}
+		e, ok := expiration(unix, time.Now())
+		if !ok {
+			stats.Skipped++
+			return nil
+		}
+		if interval > 0 && !last.IsZero() {
+			if wait := interval - time.Since(last); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		last = time.Now()
+
+		ti := &memcache.Item{Key: item.Key, Value: item.Value, Flags: item.Flags, Expiration: e}
+		var err error
+		if opts.Overwrite {
+			err = target.Client().Set(ti)
+		} else {
+			err = target.Client().Add(ti)
+		}
+		switch err {
+		case nil:
+			stats.Copied++
+			if opts.Progress != nil {
+				opts.Progress(stats.Copied)
+			}
+		case memcache.ErrNotStored:
+			stats.Skipped++
+		default:
+			stats.Failed++
+			target.logError(fmt.Errorf("aah/cache/%s: copy key(%s) %v", target.name, hashKey(item.Key), err))
+		}
+		return nil
+	})
+	return stats, err
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheCopyTo(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "copy", ProviderName: "memcache1"})
+	m := c.(*memcacheCache)
+	assert.Nil(t, c.Put("key1", "value1", time.Minute))
+	assert.Nil(t, c.Put("key2", "value2", time.Minute))
+
+	target := createCacheMgr(t, "memcache2", `
+	cache {
+		memcache2 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`).Provider("memcache2").(*Provider)
+
+	// same cluster, items are present in the target
+	stats, err := m.p.CopyTo(target, m.keyPrefix, CopyOptions{Rate: 100})
+	assert.Nil(t, err)
+	assert.Equal(t, CopyStats{Skipped: 2}, stats)
+
+	copied := 0
+	stats, err = m.p.CopyTo(target, m.keyPrefix, CopyOptions{Overwrite: true, Progress: func(n int) { copied = n }})
+	assert.Nil(t, err)
+	assert.Equal(t, CopyStats{Copied: 2}, stats)
+	assert.Equal(t, 2, copied)
+	assert.Equal(t, "value1", c.Get("key1"))
+
+	assert.Nil(t, c.Flush())
+}
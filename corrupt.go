@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"sync/atomic"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// decodeGob function decodes the gob envelope, panic of the decoder on
+// malformed input is reported as `ErrCorrupt` error.
+func decodeGob(value []byte, e *entry) (err error) {
+	r := acquireReader(value)
+	defer func() {
+		releaseReader(r)
+		if rec := recover(); rec != nil {
+			err = &corruptError{fmt.Errorf("gob decode panic: %v", rec)}
+		}
+	}()
+	return gob.NewDecoder(r).Decode(e)
+}
+
+// corruptError marks the integrity failure of the stored entry.
+type corruptError struct {
+	err error
+}
+
+func (ce *corruptError) Error() string {
+	return ce.err.Error()
+}
+
+// undecodable method handles the entry of given key which could not be
+// decoded, it is counted in `Stats.DecodeFailures` and logged with
+// `ErrCorrupt` class for integrity failures, `ErrDecode` otherwise. When the
+// cache has `corrupt_entry.delete` enabled, the entry is deleted so it does
+// not fail on every read.
+//
+//	cache {
+//	  mycache {
+//	    corrupt_entry {
+//	      # default value is false
+//	      delete = true
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) undecodable(k, mk string, err error) {
+	m.undecodableType(k, mk, gobTypeName(err), err)
+}
+
+// undecodableType method handles the undecodable entry whose value type is
+// known, see `undecodable`.
+func (m *memcacheCache) undecodableType(k, mk, typ string, err error) {
+	atomic.AddUint64(&m.counters.decodeFailures, 1)
+	m.counters.codecFailed("decode", typ, err)
+	class := ErrDecode
+	if _, ok := err.(*corruptError); ok {
+		class = ErrCorrupt
+	}
+	m.p.logError(m.opError("get", k, mk, class, err))
+	if m.deleteCorrupt {
+		if derr := notacacheMiss(m.client().Delete(mk)); derr != nil {
+			m.p.logError(m.opError("delete", k, mk, nil, derr))
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheDecodeGob(t *testing.T) {
+	var e entry
+	assert.NotNil(t, decodeGob([]byte("not a gob envelope"), &e))
+
+	buf := new(bytes.Buffer)
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(buf)
+	m := &memcacheCache{cfg: &cache.Config{Name: "corrupt"}, p: &Provider{logger: l}, counters: new(counters)}
+
+	m.undecodable("key1", "corrupt-key1", errChecksum)
+	m.undecodable("key2", "corrupt-key2", errors.New("gob: type not registered"))
+	assert.Equal(t, uint64(2), m.Stats().DecodeFailures)
+	assert.True(t, strings.Contains(buf.String(), "checksum mismatch"))
+	assert.Equal(t, ErrCorrupt, classOf(m.opError("get", "key1", "", ErrCorrupt, errChecksum)))
+}
+
+func TestMemcacheCorruptEntryDelete(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		corruptcache {
+			corrupt_entry {
+				delete = true
+			}
+		}
+	}
+`, &cache.Config{Name: "corruptcache", ProviderName: "memcache1"}).(Cache)
+
+	client := c.(*memcacheCache).p.Client()
+	assert.Nil(t, client.Set(&memcache.Item{Key: "corruptcache-key1", Value: []byte("garbage"), Expiration: 3}))
+	assert.Nil(t, c.Get("key1"))
+	assert.Equal(t, uint64(1), c.Stats().DecodeFailures)
+
+	_, err := client.Get("corruptcache-key1")
+	assert.Equal(t, memcache.ErrCacheMiss, err)
+}
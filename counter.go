@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// counterKeyPrefix is prepended to the counter key, so that counters do not
+// collide with the cache entries.
+const counterKeyPrefix = "~counter:"
+
+// Counter type is the atomic memcache counter shared across the app
+// instances. Counter does not expire and does not go below zero.
+type Counter struct {
+	m    *memcacheCache
+	name string
+}
+
+// Counter method returns the counter of given name.
+func (m *memcacheCache) Counter(name string) *Counter {
+	return &Counter{m: m, name: name}
+}
+
+// Add method adds given delta to the counter and returns the new value,
+// absent counter is initialized to zero atomically first.
+func (c *Counter) Add(delta int64) (uint64, error) {
+	return c.m.incr(counterKeyPrefix+c.name, delta, 0)
+}
+
+// Get method returns the value of the counter, absent counter is zero.
+func (c *Counter) Get() (uint64, error) {
+	k := counterKeyPrefix + c.name
+	o := c.m.begin("get", k)
+	mk := c.m.key(k)
+	item, err := c.m.client().Get(mk)
+	if err == memcache.ErrCacheMiss {
+		o.miss()
+		o.end(nil)
+		return 0, nil
+	}
+	if err != nil {
+		o.end(err)
+		return 0, c.m.opError("get", k, mk, nil, err)
+	}
+	o.hit(len(item.Value))
+	o.end(nil)
+	// memcache decr keeps the value length, it is padded with spaces
+	n, err := strconv.ParseUint(string(bytes.TrimSpace(item.Value)), 10, 64)
+	if err != nil {
+		return 0, c.m.opError("get", k, mk, ErrDecode, err)
+	}
+	return n, nil
+}
+
+// Reset method sets the counter to zero.
+func (c *Counter) Reset() error {
+	k := counterKeyPrefix + c.name
+	o := c.m.begin("put", k)
+	mk := c.m.key(k)
+	err := c.m.client().Set(&memcache.Item{Key: mk, Value: []byte("0")})
+	o.end(err)
+	if err != nil {
+		return c.m.opError("put", k, mk, nil, err)
+	}
+	return nil
+}
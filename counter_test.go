@@ -0,0 +1,57 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheCounter(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "counter", ProviderName: "memcache1"}).(Cache)
+
+	ctr := c.Counter("visits")
+	assert.Nil(t, ctr.Reset())
+
+	n, err := ctr.Add(5)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(5), n)
+	n, err = ctr.Add(-2)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), n)
+
+	// does not go below zero
+	n, err = ctr.Add(-10)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), n)
+
+	_, _ = ctr.Add(12)
+	n, err = ctr.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(12), n)
+
+	assert.Nil(t, ctr.Reset())
+	n, err = ctr.Get()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), n)
+
+	// absent counter
+	n, err = c.Counter("absent").Get()
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), n)
+	n, err = c.Counter("fresh").Add(1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), n)
+	assert.Nil(t, c.Counter("fresh").Reset())
+}
@@ -0,0 +1,185 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// GetContext method is `Get` bounded by the deadline of given context, e.g.
+// the aah request context `ctx.Req.Unwrap().Context()`, so a slow memcache
+// server cannot push the handler past its SLA. It returns the context error
+// if the context is done before the entry is read.
+//
+// The operation deadline is derived from the remaining request budget,
+// `deadline.budget_ratio` is the fraction of the remaining budget given to
+// the operation. The deadline is set on the memcache connection, so that the
+// call is cut on the wire instead of left running in the background; context
+// without deadline is bounded by the client timeout. Coalesced Get returns on
+// its own context done. Operations are not sent if the budget left is below
+// `deadline.min_budget`. Operations cut by the deadline are counted in
+// `Stats.DeadlineExceeded`.
+//
+//	cache {
+//	  mycache {
+//	    deadline {
+//	      # default value is 1
+//	      budget_ratio = 0.5
+//
+//	      # default value is 1ms
+//	      min_budget = "2ms"
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) GetContext(ctx context.Context, k string) (interface{}, error) {
+	var v interface{}
+	err := m.withDeadline(ctx, func(ctx context.Context) (err error) {
+		v, err = m.getContext(ctx, k)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// GetMultiContext method is `GetMulti` bounded by the deadline of given
+// context, see `GetContext`.
+func (m *memcacheCache) GetMultiContext(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	var result map[string]interface{}
+	err := m.withDeadline(ctx, func(ctx context.Context) error {
+		result, _ = m.getMulti(ctx, keys)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// PutContext method is `Put` bounded by the deadline of given context, see
+// `GetContext`. Put cut by the deadline may still be written.
+func (m *memcacheCache) PutContext(ctx context.Context, k string, v interface{}, d time.Duration) error {
+	return m.withDeadline(ctx, func(ctx context.Context) error {
+		return m.putContext(ctx, k, v, d)
+	})
+}
+
+// DeleteContext method is `Delete` bounded by the deadline of given context,
+// see `GetContext`. Delete cut by the deadline may still be applied.
+func (m *memcacheCache) DeleteContext(ctx context.Context, k string) error {
+	return m.withDeadline(ctx, func(ctx context.Context) error {
+		return m.deleteContext(ctx, k)
+	})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// deadlinePolicy holds the settings deriving the operation deadline from the
+// context deadline.
+type deadlinePolicy struct {
+	ratio     float64
+	minBudget time.Duration
+}
+
+func newDeadlinePolicy(m *memcacheCache) deadlinePolicy {
+	dp := deadlinePolicy{
+		ratio:     1,
+		minBudget: parseDuration(m.settingString("deadline.min_budget", ""), "1ms"),
+	}
+	if r, err := strconv.ParseFloat(m.settingString("deadline.budget_ratio", "1"), 64); err == nil && r > 0 && r <= 1 {
+		dp.ratio = r
+	}
+	return dp
+}
+
+// budget method returns the deadline of the operation for the remaining
+// budget of given context deadline, false if the budget is exhausted.
+func (dp deadlinePolicy) budget(deadline, now time.Time) (time.Time, bool) {
+	left := deadline.Sub(now)
+	if left < dp.minBudget || left <= 0 {
+		return now, false
+	}
+	return now.Add(time.Duration(float64(left) * dp.ratio)), true
+}
+
+// withDeadline method calls given func with the operation deadline derived
+// from given context, it returns the context error if the deadline expires
+// before the func returns.
+func (m *memcacheCache) withDeadline(ctx context.Context, fn func(context.Context) error) error {
+	if ctx.Done() == nil {
+		return fn(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		atomic.AddUint64(&m.counters.deadlineExceeded, 1)
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		opDeadline, ok := m.deadline.budget(deadline, time.Now())
+		if !ok {
+			atomic.AddUint64(&m.counters.deadlineExceeded, 1)
+			return context.DeadlineExceeded
+		}
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithDeadline(ctx, opDeadline)
+		defer cancel()
+	}
+
+	err := fn(ctx)
+	if cerr := ctx.Err(); cerr != nil {
+		atomic.AddUint64(&m.counters.deadlineExceeded, 1)
+		return cerr
+	}
+	return err
+}
+
+// deadlineClients holds the memcache clients of the provider with the
+// timeout shorter than the client timeout, rounded down to the power of two
+// milliseconds so that the connections are pooled. They are rebuilt once
+// the provider client is replaced.
+type deadlineClients struct {
+	mu      sync.Mutex
+	base    *memcache.Client
+	clients map[time.Duration]*memcache.Client
+}
+
+// deadlineClient method returns the memcache client whose socket timeout
+// ends the call by given deadline, the provider client if its timeout does.
+func (p *Provider) deadlineClient(deadline time.Time) *memcache.Client {
+	c := p.Client()
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = memcache.DefaultTimeout
+	}
+	left := time.Until(deadline)
+	if left >= timeout {
+		return c
+	}
+	bucket := time.Millisecond
+	for bucket*2 <= left {
+		bucket *= 2
+	}
+
+	dc := &p.deadlines
+	dc.mu.Lock()
+	defer dc.mu.Unlock()
+	if dc.base != c {
+		dc.base, dc.clients = c, make(map[time.Duration]*memcache.Client)
+	}
+	bc, found := dc.clients[bucket]
+	if !found {
+		bc = p.newClient(bucket, c.MaxIdleConns)
+		dc.clients[bucket] = bc
+	}
+	return bc
+}
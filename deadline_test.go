@@ -0,0 +1,89 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheDeadlineContext(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 10}}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "deadline"}, p: p, keyPrefix: "deadline-",
+		counters: new(counters), chunkLimit: defaultChunkSize}
+	m.deadline = newDeadlinePolicy(m)
+	assert.Equal(t, deadlinePolicy{ratio: 1, minBudget: time.Millisecond}, m.deadline)
+
+	assert.Nil(t, m.PutContext(context.Background(), "key1", "value1", time.Minute))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	v, err := m.GetContext(ctx, "key1")
+	assert.Nil(t, err)
+	assert.Nil(t, v)
+	result, err := m.GetMultiContext(ctx, []string{"key1", "key2"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(result))
+	assert.Nil(t, m.DeleteContext(ctx, "key1"))
+	n := len(p.DryRunOps())
+	assert.True(t, n >= 4)
+
+	// budget exhausted, memcache is not called
+	expired, cancel2 := context.WithTimeout(context.Background(), 500*time.Microsecond)
+	defer cancel2()
+	_, err = m.GetContext(expired, "key1")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, n, len(p.DryRunOps()))
+
+	canceled, cancel3 := context.WithCancel(context.Background())
+	cancel3()
+	assert.Equal(t, context.Canceled, m.PutContext(canceled, "key1", "value1", time.Minute))
+	assert.Equal(t, uint64(2), m.Stats().DeadlineExceeded)
+}
+
+func TestMemcacheDeadlineBudget(t *testing.T) {
+	m := &memcacheCache{counters: new(counters), deadline: deadlinePolicy{ratio: 0.5, minBudget: time.Millisecond}}
+	now := time.Now()
+	d, ok := m.deadline.budget(now.Add(100*time.Millisecond), now)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(50*time.Millisecond), d)
+	_, ok = m.deadline.budget(now.Add(-time.Second), now)
+	assert.False(t, ok)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := m.withDeadline(ctx, func(ctx context.Context) error {
+		deadline, ok := ctx.Deadline()
+		assert.True(t, ok)
+		assert.True(t, time.Until(deadline) <= 50*time.Millisecond)
+		<-ctx.Done()
+		return nil
+	})
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Equal(t, uint64(1), m.Stats().DeadlineExceeded)
+}
+
+func TestMemcacheDeadlineClient(t *testing.T) {
+	p := &Provider{name: "memcache1", servers: new(memcache.ServerList)}
+	p.client.Store(p.newClient(time.Second, 4))
+	assert.True(t, p.Client() == p.deadlineClient(time.Now().Add(2*time.Second)))
+
+	c := p.deadlineClient(time.Now().Add(300 * time.Millisecond))
+	assert.Equal(t, 256*time.Millisecond, c.Timeout)
+	assert.Equal(t, 4, c.MaxIdleConns)
+	assert.True(t, c == p.deadlineClient(time.Now().Add(400*time.Millisecond)))
+	assert.Equal(t, time.Millisecond, p.deadlineClient(time.Now()).Timeout)
+
+	p.client.Store(p.newClient(500*time.Millisecond, 4))
+	assert.False(t, c == p.deadlineClient(time.Now().Add(300*time.Millisecond)))
+}
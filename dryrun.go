@@ -0,0 +1,166 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ErrDryRun error is returned by the provider operations which require the
+// memcache servers, e.g. `Stats` and `DumpKeys`, in dry-run mode.
+var ErrDryRun = errors.New("aah/cache: provider is in dry-run mode")
+
+// DryRunOp struct is the memcache operation recorded in dry-run mode. Key is
+// the memcache key, Size is the value size in bytes and TTL is the memcache
+// expiration of the writes.
+type DryRunOp struct {
+	Op   string    `json:"op"`
+	Key  string    `json:"key"`
+	Size int       `json:"size,omitempty"`
+	TTL  int32     `json:"ttl,omitempty"`
+	Time time.Time `json:"time"`
+}
+
+// DryRunOps method returns the operations recorded in dry-run mode, nil if
+// the provider is not in dry-run mode.
+//
+// In dry-run mode the provider makes no memcache calls, reads report miss
+// and writes succeed; operations are recorded up to `dry_run.max_ops`, so the
+// key strategies and TTL policy could be validated in tests or staging
+// without memcache servers.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//	    dry_run {
+//	      # default value is false
+//	      enable = true
+//	      # default value is 10000
+//	      max_ops = 10000
+//	    }
+//	  }
+//	}
+func (p *Provider) DryRunOps() []DryRunOp {
+	if p.dryRun == nil {
+		return nil
+	}
+	p.dryRun.mu.Lock()
+	defer p.dryRun.mu.Unlock()
+	return append([]DryRunOp(nil), p.dryRun.ops...)
+}
+
+// DumpDryRun method writes the operations recorded in dry-run mode to given
+// writer as JSON lines, it returns the number of operations written.
+func (p *Provider) DumpDryRun(w io.Writer) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	ops := p.DryRunOps()
+	for i, op := range ops {
+		if err := enc.Encode(op); err != nil {
+			return i, err
+		}
+	}
+	return len(ops), bw.Flush()
+}
+
+// ResetDryRun method clears the operations recorded in dry-run mode.
+func (p *Provider) ResetDryRun() {
+	if p.dryRun == nil {
+		return
+	}
+	p.dryRun.mu.Lock()
+	defer p.dryRun.mu.Unlock()
+	p.dryRun.ops = p.dryRun.ops[:0]
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// dryRunClient records the memcache calls instead of making them.
+type dryRunClient struct {
+	mu  sync.Mutex
+	max int
+	ops []DryRunOp
+}
+
+func newDryRunClient(p *Provider) *dryRunClient {
+	cfgPrefix := "cache." + p.name + ".dry_run."
+	if !p.config().BoolDefault(cfgPrefix+"enable", false) {
+		return nil
+	}
+	return &dryRunClient{max: p.config().IntDefault(cfgPrefix+"max_ops", 10000)}
+}
+
+func (d *dryRunClient) record(op, key string, size int, ttl int32) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.max > 0 && len(d.ops) >= d.max {
+		return
+	}
+	d.ops = append(d.ops, DryRunOp{Op: op, Key: key, Size: size, TTL: ttl, Time: time.Now()})
+}
+
+func (d *dryRunClient) write(op string, item *memcache.Item) error {
+	d.record(op, item.Key, len(item.Value), item.Expiration)
+	return nil
+}
+
+func (d *dryRunClient) Get(key string) (*memcache.Item, error) {
+	d.record("get", key, 0, 0)
+	return nil, memcache.ErrCacheMiss
+}
+
+func (d *dryRunClient) metaGet(key, _ string) (string, error) {
+	d.record("get", key, 0, 0)
+	return "", memcache.ErrCacheMiss
+}
+
+func (d *dryRunClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	for _, k := range keys {
+		d.record("get", k, 0, 0)
+	}
+	return map[string]*memcache.Item{}, nil
+}
+
+func (d *dryRunClient) Set(item *memcache.Item) error     { return d.write("set", item) }
+func (d *dryRunClient) Add(item *memcache.Item) error     { return d.write("add", item) }
+func (d *dryRunClient) Replace(item *memcache.Item) error { return d.write("replace", item) }
+
+func (d *dryRunClient) CompareAndSwap(item *memcache.Item) error {
+	return d.write("cas", item)
+}
+
+func (d *dryRunClient) Delete(key string) error {
+	d.record("delete", key, 0, 0)
+	return nil
+}
+
+func (d *dryRunClient) Increment(key string, delta uint64) (uint64, error) {
+	d.record("incr", key, 0, 0)
+	return delta, nil
+}
+
+func (d *dryRunClient) Decrement(key string, delta uint64) (uint64, error) {
+	d.record("decr", key, 0, 0)
+	return 0, nil
+}
+
+func (d *dryRunClient) Touch(key string, seconds int32) error {
+	d.record("touch", key, 0, seconds)
+	return nil
+}
+
+func (d *dryRunClient) FlushAll() error {
+	d.record("flush_all", "", 0, 0)
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheDryRun(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 4}}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "dryrun"}, p: p, keyPrefix: "dryrun-",
+		counters: new(counters), chunkLimit: defaultChunkSize}
+
+	assert.Nil(t, m.Put("key1", "value1", time.Minute))
+	assert.Nil(t, m.Get("key1"))
+	assert.Nil(t, m.Delete("key1"))
+	assert.Nil(t, m.Flush())
+	assert.Nil(t, m.Put("key2", "value2", time.Minute))
+
+	ops := p.DryRunOps()
+	assert.Equal(t, 4, len(ops))
+	assert.Equal(t, "set", ops[0].Op)
+	assert.Equal(t, "dryrun-key1", ops[0].Key)
+	assert.True(t, ops[0].Size > 0)
+	assert.Equal(t, int32(60), ops[0].TTL)
+	assert.Equal(t, "get", ops[1].Op)
+	assert.Equal(t, "delete", ops[2].Op)
+	assert.Equal(t, "flush_all", ops[3].Op)
+
+	buf := new(bytes.Buffer)
+	n, err := p.DumpDryRun(buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, n)
+	assert.True(t, strings.HasPrefix(buf.String(), `{"op":"set","key":"dryrun-key1","size":`))
+	assert.Equal(t, 4, strings.Count(buf.String(), "\n"))
+
+	p.ResetDryRun()
+	assert.Equal(t, 0, len(p.DryRunOps()))
+
+	_, err = p.dialAdmin("localhost:11211")
+	assert.Equal(t, ErrDryRun, err)
+	assert.Nil(t, (&Provider{}).DryRunOps())
+}
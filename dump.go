@@ -0,0 +1,142 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// dumpBatchSize is the number of keys fetched per GetMulti during the scan.
+const dumpBatchSize = 100
+
+// maxRelativeExpiration is the longest expiration memcache accepts in
+// seconds relative to now, 30 days.
+const maxRelativeExpiration = 60 * 60 * 24 * 30
+
+// dumpRecord is one line of the dump file, value is base64 encoded by JSON.
+type dumpRecord struct {
+	Key        string `json:"k"`
+	Flags      uint32 `json:"f,omitempty"`
+	Expiration int64  `json:"e,omitempty"` // unix seconds, 0 means never
+	Value      []byte `json:"v"`
+}
+
+// Dump method writes the memcache items having given key prefix to given
+// writer as JSON lines, each line holds key, flags, absolute expiration and
+// value of the item. Items are as stored, chunks and provider envelope
+// included, so the dump restores on any memcache cluster via `Restore`.
+// Keys are listed via `DumpKeys`, it returns the number of items written.
+func (p *Provider) Dump(w io.Writer, prefix string) (int, error) {
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+	n := 0
+	err := p.scanItems(prefix, func(item *memcache.Item, exp time.Time) error {
+		rec := dumpRecord{Key: item.Key, Flags: item.Flags, Value: item.Value}
+		if !exp.IsZero() {
+			rec.Expiration = exp.Unix()
+		}
+		if err := enc.Encode(rec); err != nil {
+			return err
+		}
+		n++
+		return nil
+	})
+	if err != nil {
+		return n, err
+	}
+	return n, bw.Flush()
+}
+
+// Restore method writes the items of the dump written by `Dump` into the
+// memcache, items expired meanwhile are skipped. Existing items of the same
+// key are overwritten. It returns the number of items restored.
+func (p *Provider) Restore(r io.Reader) (int, error) {
+	dec := json.NewDecoder(bufio.NewReader(r))
+	n := 0
+	for {
+		var rec dumpRecord
+		if err := dec.Decode(&rec); err == io.EOF {
+			return n, nil
+		} else if err != nil {
+			return n, fmt.Errorf("aah/cache/%s: restore record %d %v", p.name, n+1, err)
+		}
+		exp, ok := expiration(rec.Expiration, time.Now())
+		if !ok {
+			continue
+		}
+		item := &memcache.Item{Key: rec.Key, Flags: rec.Flags, Value: rec.Value, Expiration: exp}
+		if err := p.Client().Set(item); err != nil {
+			return n, fmt.Errorf("aah/cache/%s: restore key(%s) %v", p.name, hashKey(rec.Key), err)
+		}
+		n++
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// scanItems method calls given func for the memcache items having given key
+// prefix along with their expiration, keys are fetched in batches. Keys
+// expired or evicted between the listing and fetch are skipped.
+func (p *Provider) scanItems(prefix string, fn func(*memcache.Item, time.Time) error) error {
+	batch := make([]KeyInfo, 0, dumpBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		keys := make([]string, len(batch))
+		for i, ki := range batch {
+			keys[i] = ki.Key
+		}
+		items, err := p.Client().GetMulti(keys)
+		if err != nil {
+			return fmt.Errorf("aah/cache/%s: scan %v", p.name, err)
+		}
+		for _, ki := range batch {
+			if item, found := items[ki.Key]; found {
+				if err = fn(item, ki.Expiration); err != nil {
+					return err
+				}
+			}
+		}
+		batch = batch[:0]
+		return nil
+	}
+	err := p.DumpKeys(prefix, func(ki KeyInfo) error {
+		batch = append(batch, ki)
+		if len(batch) < dumpBatchSize {
+			return nil
+		}
+		return flush()
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// expiration function returns the memcache expiration of given absolute unix
+// expiration, memcache treats the values over 30 days as unix timestamp. It
+// returns false if already expired.
+func expiration(unix int64, now time.Time) (int32, bool) {
+	if unix == 0 {
+		return 0, true
+	}
+	left := unix - now.Unix()
+	if left <= 0 {
+		return 0, false
+	}
+	if left <= maxRelativeExpiration {
+		return int32(left), true
+	}
+	return int32(unix), true
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheDumpRestore(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "dump", ProviderName: "memcache1"})
+	m := c.(*memcacheCache)
+	assert.Nil(t, c.Put("key1", "value1", time.Minute))
+	assert.Nil(t, c.Put("key2", 42, 0))
+
+	var buf bytes.Buffer
+	n, err := m.p.Dump(&buf, m.keyPrefix)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+
+	assert.Nil(t, c.Flush())
+	assert.Nil(t, c.Get("key1"))
+
+	n, err = m.p.Restore(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, "value1", c.Get("key1"))
+	assert.Equal(t, 42, c.Get("key2"))
+
+	_, err = m.p.Restore(bytes.NewBufferString("not json"))
+	assert.NotNil(t, err)
+}
+
+func TestMemcacheDumpExpiration(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+
+	exp, ok := expiration(0, now)
+	assert.True(t, ok)
+	assert.Equal(t, int32(0), exp)
+
+	exp, ok = expiration(now.Unix()+60, now)
+	assert.True(t, ok)
+	assert.Equal(t, int32(60), exp)
+
+	exp, ok = expiration(now.Unix()+maxRelativeExpiration+1, now)
+	assert.True(t, ok)
+	assert.Equal(t, int32(now.Unix()+maxRelativeExpiration+1), exp)
+
+	_, ok = expiration(now.Unix()-1, now)
+	assert.False(t, ok)
+}
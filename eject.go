@@ -0,0 +1,271 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ejected method returns the addresses of the memcache servers ejected from
+// the server list, see `eject_after_failures`.
+func (p *Provider) Ejected() []string {
+	if p.ejector == nil {
+		return nil
+	}
+	return p.ejector.ejectedAddrs()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// ejector removes the memcache server from the server list of the provider
+// after `eject_after_failures` consecutive operations failed with
+// `ErrServerUnavailable`, so the keys are spread over the remaining servers
+// instead of failing until the server is back. Ejected servers are probed
+// with `version` command every `probe_interval` and re-admitted once they
+// respond. The last server of the list is never ejected.
+//
+// Memcache client picks the server by key hash modulo the number of servers,
+// so ejection and re-admission remap most of the keys, not only the keys of
+// the ejected server; expect a burst of misses. Server list changed by
+// `Reload` re-admits all the servers.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//
+//	    # default value is 0, servers are never ejected
+//	    eject_after_failures = 5
+//
+//	    # default value is 10s
+//	    probe_interval = "10s"
+//	  }
+//	}
+type ejector struct {
+	p        *Provider
+	after    int
+	interval time.Duration
+
+	mu       sync.Mutex
+	failures map[string]int
+	ejected  map[string]time.Time
+	probing  bool
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newEjector(p *Provider) *ejector {
+	cfgPrefix := "cache." + p.name + "."
+	after := p.config().IntDefault(cfgPrefix+"eject_after_failures", 0)
+	if after <= 0 || p.dryRun != nil {
+		return nil
+	}
+	e := &ejector{
+		p:        p,
+		after:    after,
+		interval: parseDuration(p.config().StringDefault(cfgPrefix+"probe_interval", ""), "10s"),
+		failures: make(map[string]int),
+		ejected:  make(map[string]time.Time),
+		done:     make(chan struct{}),
+	}
+	if e.interval <= 0 {
+		e.interval = 10 * time.Second
+	}
+	return e
+}
+
+// record method records the operation outcome on given server, it ejects the
+// server on reaching the consecutive failures.
+func (e *ejector) record(addr string, err error) {
+	if e == nil || addr == "" {
+		return
+	}
+	if e.count(addr, err) {
+		e.eject(addr, err)
+	}
+}
+
+// count method counts the consecutive failures of given server, it reports
+// whether the server is due for ejection.
+func (e *ejector) count(addr string, err error) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if err == nil {
+		delete(e.failures, addr)
+		return false
+	}
+	if _, found := e.ejected[addr]; found {
+		return false
+	}
+	e.failures[addr]++
+	return e.failures[addr] >= e.after
+}
+
+func (e *ejector) eject(addr string, err error) {
+	p := e.p
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	e.mu.Lock()
+	if _, found := e.ejected[addr]; found || e.failures[addr] < e.after {
+		e.mu.Unlock()
+		return
+	}
+	delete(e.failures, addr)
+	e.ejected[addr] = time.Now()
+	active := e.active()
+	if len(active) == 0 {
+		// last server standing
+		delete(e.ejected, addr)
+		e.mu.Unlock()
+		return
+	}
+	startProbe := !e.probing
+	e.probing = true
+	e.mu.Unlock()
+
+	if serr := p.servers.SetServers(active...); serr != nil {
+		p.logger.Errorf("aah/cache/provider: %s unable to eject server(%s): %v", p.name, addr, serr)
+		e.mu.Lock()
+		delete(e.ejected, addr)
+		e.mu.Unlock()
+		return
+	}
+	p.logger.Warnf("aah/cache/provider: %s server(%s) ejected after %d failures: %v", p.name, addr, e.after, err)
+	p.publish(EventOnServerEjected, ServerEvent{Server: addr, Err: err})
+	if startProbe {
+		go e.probeLoop()
+	}
+}
+
+// active method returns the configured servers not ejected, in the
+// configured order. Caller holds the lock.
+func (e *ejector) active() []string {
+	var active []string
+	for _, a := range e.p.serverAddrs() {
+		if _, found := e.ejected[resolveAddr(a)]; !found {
+			active = append(active, a)
+		}
+	}
+	return active
+}
+
+// probeLoop method probes the ejected servers until all of them are
+// re-admitted or the provider is closed.
+func (e *ejector) probeLoop() {
+	t := time.NewTicker(e.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-t.C:
+		case <-e.done:
+			e.mu.Lock()
+			e.probing = false
+			e.mu.Unlock()
+			return
+		}
+		for _, addr := range e.ejectedAddrs() {
+			if err := e.p.probeServer(addr); err != nil {
+				e.p.logger.Debugf("aah/cache/provider: %s server(%s) probe failed: %v", e.p.name, addr, err)
+				continue
+			}
+			e.readmit(addr)
+		}
+		e.mu.Lock()
+		if len(e.ejected) == 0 {
+			e.probing = false
+			e.mu.Unlock()
+			return
+		}
+		e.mu.Unlock()
+	}
+}
+
+func (e *ejector) readmit(addr string) {
+	p := e.p
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	e.mu.Lock()
+	since, found := e.ejected[addr]
+	if !found {
+		e.mu.Unlock()
+		return
+	}
+	delete(e.ejected, addr)
+	active := e.active()
+	e.mu.Unlock()
+
+	if err := p.servers.SetServers(active...); err != nil {
+		p.logger.Errorf("aah/cache/provider: %s unable to re-admit server(%s): %v", p.name, addr, err)
+		e.mu.Lock()
+		e.ejected[addr] = since
+		e.mu.Unlock()
+		return
+	}
+	p.logger.Infof("aah/cache/provider: %s server(%s) re-admitted after %v", p.name, addr,
+		time.Since(since).Truncate(time.Second))
+	p.publish(EventOnServerReadmitted, ServerEvent{Server: addr})
+}
+
+// close method stops probing the ejected servers.
+func (e *ejector) close() {
+	if e == nil {
+		return
+	}
+	e.closeOnce.Do(func() { close(e.done) })
+}
+
+// reset method re-admits all the servers, e.g. the server list is changed.
+// Caller holds the reload lock.
+func (e *ejector) reset() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.failures = make(map[string]int)
+	e.ejected = make(map[string]time.Time)
+}
+
+func (e *ejector) ejectedAddrs() []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	addrs := make([]string, 0, len(e.ejected))
+	for addr := range e.ejected {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// probeServer method reports whether the memcache server responds to the
+// `version` command.
+func (p *Provider) probeServer(addr string) error {
+	ac, err := p.dialAdmin(addr)
+	if err != nil {
+		return err
+	}
+	defer ac.Close()
+	return ac.command("version", func(string) (bool, error) {
+		return false, nil
+	})
+}
+
+// resolveAddr function returns the configured server address as picked by
+// the server list, i.e. with the host resolved to IP address.
+func resolveAddr(addr string) string {
+	if strings.Contains(addr, "/") {
+		return addr
+	}
+	if a, err := net.ResolveTCPAddr("tcp", addr); err == nil {
+		return a.String()
+	}
+	return addr
+}
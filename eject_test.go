@@ -0,0 +1,142 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/cache/provider/memcache/memcachetest"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheEjectorCount(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(config.NewEmpty())
+	assert.Nil(t, newEjector(p))
+	assert.Nil(t, p.Ejected())
+
+	p.setAddresses([]string{"10.0.0.1:11211", "10.0.0.2:11211", "10.0.0.3:11211"})
+	e := &ejector{p: p, after: 3, failures: make(map[string]int), ejected: make(map[string]time.Time)}
+	p.ejector = e
+	errDown := errors.New("connection refused")
+
+	assert.False(t, e.count("10.0.0.2:11211", errDown))
+	assert.False(t, e.count("10.0.0.2:11211", errDown))
+	assert.False(t, e.count("10.0.0.2:11211", nil))
+	assert.False(t, e.count("10.0.0.2:11211", errDown))
+	assert.False(t, e.count("10.0.0.2:11211", errDown))
+	assert.True(t, e.count("10.0.0.2:11211", errDown))
+
+	e.ejected["10.0.0.2:11211"] = time.Now()
+	assert.False(t, e.count("10.0.0.2:11211", errDown))
+	assert.Equal(t, []string{"10.0.0.1:11211", "10.0.0.3:11211"}, e.active())
+	assert.Equal(t, []string{"10.0.0.2:11211"}, p.Ejected())
+
+	e.reset()
+	assert.Equal(t, 0, len(p.Ejected()))
+	assert.Equal(t, 3, len(e.active()))
+
+	var ne *ejector
+	ne.record("10.0.0.1:11211", errDown)
+	ne.reset()
+	assert.Equal(t, "/tmp/memcached.sock", resolveAddr("/tmp/memcached.sock"))
+	assert.Equal(t, "127.0.0.1:11211", resolveAddr("127.0.0.1:11211"))
+}
+
+func TestMemcacheEjectorClose(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(config.NewEmpty())
+	e := &ejector{p: p, after: 3, interval: time.Hour, probing: true, done: make(chan struct{}),
+		failures: make(map[string]int), ejected: map[string]time.Time{"10.0.0.2:11211": time.Now()}}
+	p.ejector = e
+
+	stopped := make(chan struct{})
+	go func() {
+		e.probeLoop()
+		close(stopped)
+	}()
+	assert.Nil(t, p.Close())
+	assert.Nil(t, p.Close())
+	select {
+	case <-stopped:
+	case <-time.After(time.Second):
+		t.Fatal("probe loop is running after provider close")
+	}
+	e.mu.Lock()
+	assert.False(t, e.probing)
+	e.mu.Unlock()
+
+	var ne *ejector
+	ne.close()
+}
+
+func TestMemcacheOperationServer(t *testing.T) {
+	m := newBenchCache()
+	m.p.servers = new(memcache.ServerList)
+	assert.Nil(t, m.p.servers.SetServers("127.0.0.1:11211", "127.0.0.2:11211"))
+	m.p.ejector = &ejector{p: m.p, after: 3, failures: make(map[string]int), ejected: make(map[string]time.Time)}
+
+	o := m.begin("get", "key1")
+	addr := o.server()
+	assert.NotEqual(t, "", addr)
+
+	// server list changed during the memcache call
+	assert.Nil(t, m.p.servers.SetServers("127.0.0.3:11211"))
+	o.end(&net.OpError{Op: "dial", Err: errors.New("connection refused")})
+
+	assert.Equal(t, addr, o.server())
+	assert.Equal(t, 1, m.p.ejector.failures[addr])
+	assert.Equal(t, uint64(1), m.counters.serverOps()[addr].Errors)
+	_, found := m.counters.serverOps()["127.0.0.3:11211"]
+	assert.False(t, found)
+}
+
+func TestMemcacheEjectServer(t *testing.T) {
+	srv1, err := memcachetest.NewServer()
+	assert.Nil(t, err)
+	defer srv1.Close()
+	srv2, err := memcachetest.NewServer()
+	assert.Nil(t, err)
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["`+srv1.Addr()+`", "`+srv2.Addr()+`"]
+			eject_after_failures = 2
+			probe_interval = "20ms"
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "eject", ProviderName: "memcache1"}))
+	c := mgr.Cache("eject")
+	p := mgr.Provider("memcache1").(*Provider)
+	_ = srv2.Close()
+
+	var failures int
+	for i := 0; i < 50; i++ {
+		if err := c.Put("key"+strconv.Itoa(i), i, time.Minute); err != nil {
+			failures++
+		}
+	}
+	assert.Equal(t, 2, failures)
+	assert.Equal(t, []string{srv2.Addr()}, p.Ejected())
+	assert.Nil(t, p.probeServer(srv1.Addr()))
+	assert.NotNil(t, p.probeServer(srv2.Addr()))
+
+	// still down, stays ejected
+	time.Sleep(60 * time.Millisecond)
+	assert.Equal(t, []string{srv2.Addr()}, p.Ejected())
+	assert.Nil(t, c.Put("key0", 0, time.Minute))
+}
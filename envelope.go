@@ -0,0 +1,60 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import "reflect"
+
+// Payload format bits of the memcache item flags, bits 8-11 hold the codec
+// id and bits 12-15 hold the envelope version of the codec. Items written
+// before these bits were introduced carry zero and are read as gob envelope
+// version 1.
+const (
+	flagCodecShift   = 8
+	flagVersionShift = 12
+	flagFormatMask   = uint32(0xFF) << flagCodecShift
+
+	codecLegacy uint32 = 0
+	codecGob    uint32 = 1
+
+	// envelopeVersion is the current gob envelope version written by the
+	// provider.
+	envelopeVersion uint32 = 1
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// formatFlags function returns the payload format bits of the gob envelope.
+func formatFlags() uint32 {
+	return codecGob<<flagCodecShift | envelopeVersion<<flagVersionShift
+}
+
+// knownFormat function reports whether the payload format of the item flags
+// is readable by this version of the provider. During rolling deploy newer
+// app instances may write the format unknown to older instances, such
+// entries are treated as cache miss instead of decode failure.
+func knownFormat(flags uint32) bool {
+	codec := flags >> flagCodecShift & 0xF
+	version := flags >> flagVersionShift & 0xF
+	switch codec {
+	case codecLegacy:
+		return version == 0
+	case codecGob:
+		return version >= 1 && version <= envelopeVersion
+	case codecFast:
+		return version == fastVersion
+	}
+	return false
+}
+
+// schemaHint function returns the type name of the cache value stored in
+// the envelope, it identifies the value layout for the decode side.
+func schemaHint(v interface{}) string {
+	if v == nil {
+		return ""
+	}
+	return reflect.TypeOf(v).String()
+}
@@ -0,0 +1,30 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheKnownFormat(t *testing.T) {
+	assert.True(t, knownFormat(0))
+	assert.True(t, knownFormat(flagNoTouch|1<<20))
+	assert.True(t, knownFormat(formatFlags()))
+	assert.True(t, knownFormat(formatFlags()|flagChunked))
+
+	// newer envelope version or unknown codec
+	assert.False(t, knownFormat(codecGob<<flagCodecShift|(envelopeVersion+1)<<flagVersionShift))
+	assert.False(t, knownFormat(7<<flagCodecShift|1<<flagVersionShift))
+	assert.False(t, knownFormat(codecLegacy|1<<flagVersionShift))
+}
+
+func TestMemcacheSchemaHint(t *testing.T) {
+	assert.Equal(t, "", schemaHint(nil))
+	assert.Equal(t, "string", schemaHint("value"))
+	assert.Equal(t, "memcache.KeyInfo", schemaHint(KeyInfo{}))
+	assert.Equal(t, "map[string]interface {}", schemaHint(map[string]interface{}{}))
+}
@@ -0,0 +1,99 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// errorLimiter limits the error logging to one line per interval for each
+// error class, so that memcache outage does not flood the logs. Suppressed
+// count is reported along with next logged error of the class.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//	    error_log {
+//	      # default value is 1s, 0s logs every error
+//	      interval = "1s"
+//	    }
+//	  }
+//	}
+type errorLimiter struct {
+	interval time.Duration
+	mu       sync.Mutex
+	windows  map[error]*errorWindow
+}
+
+type errorWindow struct {
+	last       time.Time
+	suppressed int
+}
+
+func newErrorLimiter(p *Provider) *errorLimiter {
+	d := parseDuration(p.config().StringDefault("cache."+p.name+".error_log.interval", ""), "1s")
+	if d <= 0 {
+		return nil
+	}
+	return &errorLimiter{interval: d, windows: make(map[error]*errorWindow)}
+}
+
+// allow method reports whether the error of given class to be logged and the
+// count of errors suppressed since last logged one.
+func (l *errorLimiter) allow(class error, now time.Time) (bool, int) {
+	if l == nil {
+		return true, 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	w, found := l.windows[class]
+	if !found {
+		l.windows[class] = &errorWindow{last: now}
+		return true, 0
+	}
+	if now.Sub(w.last) < l.interval {
+		w.suppressed++
+		return false, 0
+	}
+	suppressed := w.suppressed
+	w.last, w.suppressed = now, 0
+	return true, suppressed
+}
+
+// logError method logs the error at ERROR level subject to the error log
+// rate limit.
+func (p *Provider) logError(err error) {
+	ok, suppressed := p.errlog.allow(classOf(err), time.Now())
+	if !ok {
+		return
+	}
+	if suppressed > 0 {
+		p.logger.Errorf("%v (suppressed %d similar errors)", err, suppressed)
+		return
+	}
+	p.logger.Error(err)
+}
+
+// classOf function returns the error class of given error, nil for the
+// unclassified errors.
+func classOf(err error) error {
+	for _, c := range []error{ErrServerUnavailable, ErrEncode, ErrDecode, ErrCorrupt, ErrValueTooLarge, ErrMiss} {
+		if errors.Is(err, c) {
+			return c
+		}
+	}
+	for ; err != nil; err = errors.Unwrap(err) {
+		if c := errorClass(err); c != nil {
+			return c
+		}
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheErrorLimiter(t *testing.T) {
+	l := &errorLimiter{interval: time.Second, windows: make(map[error]*errorWindow)}
+	now := time.Now()
+
+	ok, n := l.allow(ErrServerUnavailable, now)
+	assert.True(t, ok)
+	assert.Equal(t, 0, n)
+	for i := 0; i < 5; i++ {
+		ok, _ = l.allow(ErrServerUnavailable, now.Add(100*time.Millisecond))
+		assert.False(t, ok)
+	}
+
+	// other class is limited independently
+	ok, _ = l.allow(nil, now.Add(100*time.Millisecond))
+	assert.True(t, ok)
+
+	ok, n = l.allow(ErrServerUnavailable, now.Add(time.Second))
+	assert.True(t, ok)
+	assert.Equal(t, 5, n)
+
+	ok, _ = (*errorLimiter)(nil).allow(ErrEncode, now)
+	assert.True(t, ok)
+}
+
+func TestMemcacheErrorClassOf(t *testing.T) {
+	assert.Equal(t, ErrServerUnavailable, classOf(fmt.Errorf("getmulti %w", memcache.ErrNoServers)))
+	assert.Equal(t, ErrEncode, classOf(&OpError{Err: errors.New("gob"), class: ErrEncode}))
+	assert.Nil(t, classOf(errors.New("origin down")))
+}
@@ -0,0 +1,127 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"net"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ErrNotFound error is returned by the loader funcs to report the value does
+// not exist at the origin, the provider caches such result as negative entry
+// for `negative_ttl`. It is also returned by `Fetch` for negative entries.
+var ErrNotFound = errors.New("aah/cache: not found")
+
+// Error classes of the cache operation failures, use `errors.Is` to branch
+// on them.
+//
+//	if err := c.Delete("key1"); errors.Is(err, memcache.ErrServerUnavailable) {
+//		// ...
+//	}
+var (
+	// ErrMiss error class reports the key does not exist in the memcache.
+	ErrMiss = errors.New("aah/cache: cache miss")
+
+	// ErrEncode error class reports the cache value could not be encoded.
+	ErrEncode = errors.New("aah/cache: unable to encode value")
+
+	// ErrDecode error class reports the cache value could not be decoded.
+	ErrDecode = errors.New("aah/cache: unable to decode value")
+
+	// ErrCorrupt error class reports the stored cache value failed the
+	// integrity check.
+	ErrCorrupt = errors.New("aah/cache: corrupted value")
+
+	// ErrServerUnavailable error class reports the memcache server could not
+	// be reached, i.e. no servers, connect timeout or network failure.
+	ErrServerUnavailable = errors.New("aah/cache: server unavailable")
+
+	// ErrTypeMismatch error class reports the cached value is not of the
+	// type expected by the caller, see `TypeMismatchError`.
+	ErrTypeMismatch = errors.New("aah/cache: type mismatch")
+
+	// ErrOverloaded error class reports the operation was rejected since
+	// the `max_inflight` memcache calls of the provider are in progress.
+	ErrOverloaded = errors.New("aah/cache: provider overloaded")
+)
+
+// OpError struct describes the failed cache operation. It matches its error
+// class and the underlying error with `errors.Is` and `errors.As`.
+type OpError struct {
+	Cache  string
+	Op     string
+	Key    string
+	Server string
+	Err    error
+
+	class   error
+	display string
+}
+
+// Error method returns the error message.
+func (e *OpError) Error() string {
+	s := "aah/cache/" + e.Cache + ":"
+	if e.display != "" {
+		s += " key(" + e.display + ")"
+	} else if e.Key != "" {
+		s += " key(" + e.Key + ")"
+	}
+	return s + " " + e.Err.Error()
+}
+
+// Unwrap method returns the underlying error.
+func (e *OpError) Unwrap() error {
+	return e.Err
+}
+
+// Is method reports whether the error belongs to given error class.
+func (e *OpError) Is(target error) bool {
+	return e.class != nil && e.class == target
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// opError method returns the `OpError` for the failed operation of given key
+// and its memcache key mk, class is derived from the err when it is nil.
+func (m *memcacheCache) opError(op, k, mk string, class, err error) error {
+	if class == nil {
+		class = errorClass(err)
+	}
+	e := &OpError{
+		Cache:  m.Name(),
+		Op:     op,
+		Key:    k,
+		Server: m.p.serverAddr(mk),
+		Err:    err,
+		class:  class,
+	}
+	if k != "" {
+		e.display = m.logKey(k)
+	}
+	return e
+}
+
+func errorClass(err error) error {
+	if err == memcache.ErrCacheMiss {
+		return ErrMiss
+	}
+	if err == ErrOverloaded {
+		return ErrOverloaded
+	}
+	if err == memcache.ErrNoServers {
+		return ErrServerUnavailable
+	}
+	if _, ok := err.(*memcache.ConnectTimeoutError); ok {
+		return ErrServerUnavailable
+	}
+	if _, ok := err.(net.Error); ok {
+		return ErrServerUnavailable
+	}
+	return nil
+}
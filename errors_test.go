@@ -0,0 +1,41 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheOpError(t *testing.T) {
+	m := &memcacheCache{cfg: &cache.Config{Name: "errcache"}, p: &Provider{}}
+
+	err := m.opError("delete", "key1", "errcache-key1", nil, memcache.ErrCacheMiss)
+	assert.Equal(t, "aah/cache/errcache: key(key1) memcache: cache miss", err.Error())
+	assert.True(t, errors.Is(err, ErrMiss))
+	assert.True(t, errors.Is(err, memcache.ErrCacheMiss))
+	assert.False(t, errors.Is(err, ErrServerUnavailable))
+
+	var oe *OpError
+	assert.True(t, errors.As(err, &oe))
+	assert.Equal(t, "delete", oe.Op)
+	assert.Equal(t, "key1", oe.Key)
+
+	err = m.opError("put", "key1", "", nil, memcache.ErrNoServers)
+	assert.True(t, errors.Is(err, ErrServerUnavailable))
+
+	err = m.opError("put", "key1", "", nil, &net.OpError{Op: "dial", Err: errors.New("connection refused")})
+	assert.True(t, errors.Is(err, ErrServerUnavailable))
+
+	err = m.opError("flush", "", "", nil, memcache.ErrServerError)
+	assert.Equal(t, "aah/cache/errcache: memcache: server error", err.Error())
+	assert.False(t, errors.Is(err, ErrMiss))
+	assert.False(t, errors.Is(err, ErrServerUnavailable))
+}
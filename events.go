@@ -0,0 +1,134 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"sync"
+)
+
+// Provider connectivity events published via `SetEventPublisher`, event data
+// is `ServerEvent`.
+const (
+	// EventOnProviderConnected event is published when the provider is
+	// initialized and connected to the memcache servers.
+	EventOnProviderConnected = "cache:provider:connected"
+
+	// EventOnServerDown event is published when the operation fails with
+	// `ErrServerUnavailable` on a server considered up.
+	EventOnServerDown = "cache:provider:server_down"
+
+	// EventOnServerUp event is published when the operation succeeds on a
+	// server considered down.
+	EventOnServerUp = "cache:provider:server_up"
+
+	// EventOnServerEjected event is published when the server is ejected
+	// from the server list, see `eject_after_failures`.
+	EventOnServerEjected = "cache:provider:server_ejected"
+
+	// EventOnServerReadmitted event is published when the ejected server
+	// responds to the probe and is added back to the server list.
+	EventOnServerReadmitted = "cache:provider:server_readmitted"
+)
+
+// EventPublisher interface is implemented by the aah application, i.e.
+// `aah.App()`, to publish events to the aah event store.
+type EventPublisher interface {
+	PublishEvent(eventName string, data interface{})
+}
+
+// ServerEvent struct is the data of the provider connectivity events.
+type ServerEvent struct {
+	Provider string
+	Servers  []string // all the servers for connected event
+	Server   string
+	Err      error // failure of server down event
+}
+
+// SetEventPublisher method sets the publisher of the provider connectivity
+// events, set it before adding the provider to the cache manager to receive
+// the connected event. Application could subscribe to switch into degraded
+// mode when a server goes down.
+//
+//	aah.App().SubscribeEventFunc(memcache.EventOnServerDown, func(e *aah.Event) {
+//		se := e.Data.(memcache.ServerEvent)
+//		// ...
+//	})
+func (p *Provider) SetEventPublisher(pub EventPublisher) {
+	p.events.Store(&pub)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// serverStates tracks the servers considered down by the operation outcome.
+type serverStates struct {
+	mu   sync.Mutex
+	down map[string]bool
+}
+
+func (p *Provider) publisher() EventPublisher {
+	if pub, ok := p.events.Load().(*EventPublisher); ok {
+		return *pub
+	}
+	return nil
+}
+
+func (p *Provider) publish(name string, e ServerEvent) {
+	if pub := p.publisher(); pub != nil {
+		e.Provider = p.name
+		pub.PublishEvent(name, e)
+	}
+}
+
+// trackServer method updates the state of the server of completed key
+// operation, it is a no-op without the publisher and the ejection.
+func (o *operation) trackServer(err error) {
+	p := o.m.p
+	if o.key == "" || p.publisher() == nil && p.ejector == nil {
+		return
+	}
+	if err != nil && !unavailable(err) {
+		// server responded
+		err = nil
+	}
+	if addr := o.server(); addr != "" {
+		p.ejector.record(addr, err)
+		if p.publisher() != nil {
+			p.serverState(addr, err)
+		}
+	}
+}
+
+// serverState method records the operation outcome on given server and
+// publishes the event when the server state changes.
+func (p *Provider) serverState(addr string, err error) {
+	s := &p.states
+	s.mu.Lock()
+	wasDown := s.down[addr]
+	if err != nil && !wasDown {
+		if s.down == nil {
+			s.down = make(map[string]bool)
+		}
+		s.down[addr] = true
+	} else if err == nil && wasDown {
+		delete(s.down, addr)
+	}
+	s.mu.Unlock()
+
+	switch {
+	case err != nil && !wasDown:
+		p.logger.Warnf("aah/cache/provider: %s server(%s) down: %v", p.name, addr, err)
+		p.publish(EventOnServerDown, ServerEvent{Server: addr, Err: err})
+	case err == nil && wasDown:
+		p.logger.Infof("aah/cache/provider: %s server(%s) up", p.name, addr)
+		p.publish(EventOnServerUp, ServerEvent{Server: addr})
+	}
+}
+
+func unavailable(err error) bool {
+	return errors.Is(err, ErrServerUnavailable) || errorClass(err) == ErrServerUnavailable
+}
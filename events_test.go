@@ -0,0 +1,61 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"net"
+	"testing"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+type recordPublisher struct {
+	names  []string
+	events []ServerEvent
+}
+
+func (r *recordPublisher) PublishEvent(name string, data interface{}) {
+	r.names = append(r.names, name)
+	r.events = append(r.events, data.(ServerEvent))
+}
+
+func TestMemcacheServerEvents(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+
+	// no publisher
+	p.serverState("10.0.0.1:11211", memcache.ErrNoServers)
+
+	pub := &recordPublisher{}
+	p.SetEventPublisher(pub)
+	p.serverState("10.0.0.2:11211", nil)
+	p.serverState("10.0.0.2:11211", memcache.ErrNoServers)
+	p.serverState("10.0.0.2:11211", memcache.ErrNoServers)
+	p.serverState("10.0.0.3:11211", nil)
+	p.serverState("10.0.0.2:11211", nil)
+	p.serverState("10.0.0.2:11211", nil)
+
+	assert.Equal(t, []string{EventOnServerDown, EventOnServerUp}, pub.names)
+	assert.Equal(t, "memcache1", pub.events[0].Provider)
+	assert.Equal(t, "10.0.0.2:11211", pub.events[0].Server)
+	assert.Equal(t, memcache.ErrNoServers, pub.events[0].Err)
+	assert.Nil(t, pub.events[1].Err)
+
+	p.publish(EventOnProviderConnected, ServerEvent{Servers: []string{"10.0.0.2:11211"}})
+	assert.Equal(t, EventOnProviderConnected, pub.names[2])
+	assert.Equal(t, []string{"10.0.0.2:11211"}, pub.events[2].Servers)
+}
+
+func TestMemcacheUnavailable(t *testing.T) {
+	assert.True(t, unavailable(memcache.ErrNoServers))
+	assert.True(t, unavailable(&net.OpError{Op: "dial", Err: errors.New("refused")}))
+	assert.True(t, unavailable(&OpError{Err: memcache.ErrNoServers, class: ErrServerUnavailable}))
+	assert.False(t, unavailable(memcache.ErrCacheMiss))
+	assert.False(t, unavailable(memcache.ErrServerError))
+}
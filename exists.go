@@ -0,0 +1,196 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// maxIdleMetaConns is the count of idle meta command connections kept per
+// memcache server.
+const maxIdleMetaConns = 2
+
+// Exists method checks given key exists in cache store and its not expried.
+// Value is not decoded and the entry is not touched in slide eviction mode.
+//
+// It uses memcache meta-get without value over its own pooled connections,
+// so the check costs no value transfer; it requires memcached 1.6 or later.
+// Meta-get goes through the same cache modes as the other reads, e.g. dry
+// run and load shedding.
+// If the server does not support meta commands, the provider falls back to
+// full read of the entry, i.e. the value is transferred, for the rest of its
+// lifetime. Fallback is also used with `meta_exists` disabled and for the
+// `write_only` cache.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//	    # default value is true
+//	    meta_exists = true
+//	  }
+//	}
+func (m *memcacheCache) Exists(k string) bool {
+	o := m.begin("exists", k)
+	mk := m.key(k)
+	var flags uint32
+	var err error
+	meta := atomic.LoadInt32(&m.p.metaExists) == 1 && !m.writeOnly
+	if meta {
+		var line string
+		if line, err = m.client().metaGet(mk, "f"); metaUnsupported(err) {
+			m.p.disableMetaExists(err)
+			meta = false
+		} else if err == nil {
+			flags = parseMetaFlags(line)
+		}
+	}
+	if !meta {
+		var item *memcache.Item
+		if item, err = m.getItem(mk); err == nil {
+			flags = item.Flags
+		}
+	}
+	if err == memcache.ErrCacheMiss {
+		o.miss()
+		o.end(nil)
+		return false
+	}
+	if err != nil {
+		o.end(err)
+		m.p.logError(m.opError("exists", k, mk, nil, err))
+		return false
+	}
+	if flags&flagNotFound == flagNotFound {
+		o.miss()
+		o.end(nil)
+		return false
+	}
+	o.hit(0)
+	o.end(nil)
+	return true
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// metaClient is the memcache client with meta-get over the pooled
+// connections of the provider, the base of the client chain.
+type metaClient struct {
+	*memcache.Client
+	p *Provider
+}
+
+func (mc metaClient) metaGet(key, flags string) (string, error) {
+	return mc.p.metaGet(key, flags)
+}
+
+// metaGet method issues meta-get of given key with flags and no value,
+// returns the reply line or `memcache.ErrCacheMiss` if the key does not
+// exist.
+func (p *Provider) metaGet(mk, flags string) (string, error) {
+	if !legalKey(mk) {
+		return "", memcache.ErrMalformedKey
+	}
+	addr := p.serverAddr(mk)
+	if addr == "" {
+		return "", memcache.ErrNoServers
+	}
+	ac, err := p.metaConn(addr)
+	if err != nil {
+		return "", err
+	}
+
+	var reply string
+	err = ac.command("mg "+mk+" "+flags, func(line string) (bool, error) {
+		switch {
+		case line == "EN":
+		case strings.HasPrefix(line, "HD"):
+			reply = line
+		default:
+			return false, memcache.ErrServerError
+		}
+		return false, nil
+	})
+	if err != nil {
+		_ = ac.Close()
+		return "", err
+	}
+	p.releaseMetaConn(ac)
+	if reply == "" {
+		return "", memcache.ErrCacheMiss
+	}
+	return reply, nil
+}
+
+// legalKey function reports whether the key is valid memcache key, same as
+// the memcache client validates it. Meta commands are written as is, so the
+// key must not carry the space or line break of another command.
+func legalKey(key string) bool {
+	if len(key) == 0 || len(key) > 250 {
+		return false
+	}
+	for i := 0; i < len(key); i++ {
+		if key[i] <= ' ' || key[i] == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMetaFlags function parses the client flags of meta-get response e.g.
+// "HD f1".
+func parseMetaFlags(line string) uint32 {
+	for _, f := range strings.Fields(line)[1:] {
+		if strings.HasPrefix(f, "f") {
+			n, _ := strconv.ParseUint(f[1:], 10, 32)
+			return uint32(n)
+		}
+	}
+	return 0
+}
+
+// disableMetaExists method switches `Exists` to the full read fallback.
+func (p *Provider) disableMetaExists(err error) {
+	if atomic.CompareAndSwapInt32(&p.metaExists, 1, 0) {
+		p.logger.Warnf("aah/cache/provider: %s meta commands are not supported (%v), Exists falls back to full read",
+			p.name, err)
+	}
+}
+
+// metaUnsupported function reports whether the error is the reply of the
+// memcache server not knowing the meta command.
+func metaUnsupported(err error) bool {
+	return err != nil && strings.HasPrefix(err.Error(), "ERROR")
+}
+
+func (p *Provider) metaConn(addr string) (*adminConn, error) {
+	p.metaMu.Lock()
+	if idle := p.metaIdle[addr]; len(idle) > 0 {
+		ac := idle[len(idle)-1]
+		p.metaIdle[addr] = idle[:len(idle)-1]
+		p.metaMu.Unlock()
+		return ac, nil
+	}
+	p.metaMu.Unlock()
+	return p.dialAdmin(addr)
+}
+
+func (p *Provider) releaseMetaConn(ac *adminConn) {
+	p.metaMu.Lock()
+	defer p.metaMu.Unlock()
+	if p.metaIdle == nil {
+		p.metaIdle = make(map[string][]*adminConn)
+	}
+	if len(p.metaIdle[ac.addr]) >= maxIdleMetaConns {
+		_ = ac.Close()
+		return
+	}
+	p.metaIdle[ac.addr] = append(p.metaIdle[ac.addr], ac)
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheMetaFlags(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer ln.Close()
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			switch strings.Fields(line)[1] {
+			case "found":
+				_, _ = conn.Write([]byte("HD f1\r\n"))
+			default:
+				_, _ = conn.Write([]byte("EN\r\n"))
+			}
+		}
+	}()
+
+	p := &Provider{name: "memcache1", servers: new(memcache.ServerList)}
+	assert.Nil(t, p.servers.SetServers(ln.Addr().String()))
+	p.client.Store(p.newClient(time.Second, memcache.DefaultMaxIdleConns))
+
+	line, err := p.metaGet("found", "f")
+	assert.Nil(t, err)
+	assert.Equal(t, flagNotFound, parseMetaFlags(line))
+
+	_, err = p.metaGet("missing", "f")
+	assert.Equal(t, memcache.ErrCacheMiss, err)
+	assert.Equal(t, 1, len(p.metaIdle[ln.Addr().String()]))
+
+	_, err = p.metaGet("found f\r\nflush_all", "f")
+	assert.Equal(t, memcache.ErrMalformedKey, err)
+}
+
+func TestMemcacheMetaKey(t *testing.T) {
+	assert.True(t, legalKey("bench-key1"))
+	assert.False(t, legalKey(""))
+	assert.False(t, legalKey(strings.Repeat("k", 251)))
+	assert.False(t, legalKey("key1 f"))
+	assert.False(t, legalKey("key1\r\nflush_all"))
+	assert.False(t, legalKey("key1\x7f"))
+
+	p := &Provider{}
+	_, err := p.metaGet("key1 f\r\nflush_all", "f")
+	assert.Equal(t, memcache.ErrMalformedKey, err)
+}
+
+func TestMemcacheExistsDryRun(t *testing.T) {
+	m := newBenchCache()
+	m.p.metaExists = 1
+	assert.False(t, m.Exists("key1"))
+	assert.Equal(t, 1, len(m.p.dryRun.ops))
+	assert.Equal(t, "get", m.p.dryRun.ops[0].Op)
+}
+
+func TestMemcacheMetaUnsupported(t *testing.T) {
+	assert.True(t, metaUnsupported(errors.New("ERROR")))
+	assert.False(t, metaUnsupported(errors.New("CLIENT_ERROR bad command line format")))
+	assert.False(t, metaUnsupported(nil))
+
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, metaExists: 1}
+	p.disableMetaExists(errors.New("ERROR"))
+	assert.Equal(t, int32(0), p.metaExists)
+}
+
+func TestMemcacheExists(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "existscache", ProviderName: "memcache1"}).(Cache)
+
+	assert.False(t, c.Exists("key1"))
+	assert.Nil(t, c.Put("key1", "value1", 3*time.Second))
+	assert.True(t, c.Exists("key1"))
+	assert.Nil(t, c.PutNotFound("key2"))
+	assert.False(t, c.Exists("key2"))
+
+	c.Flush()
+}
@@ -0,0 +1,77 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"expvar"
+	"sync"
+)
+
+// ExpvarName is the name of `expvar` map the cache counters are published
+// under, counters are keyed by provider name and cache name e.g.
+//
+//	{"aah_cache": {"memcache1": {"cache1": {"ops": 12, "hits": 9, "misses": 3, "errors": 0}}}}
+const ExpvarName = "aah_cache"
+
+var (
+	expvarMu   sync.Mutex
+	expvarRoot *expvar.Map
+)
+
+// cacheVars struct holds the published counters of a cache.
+type cacheVars struct {
+	m *expvar.Map
+}
+
+// newCacheVars method returns the counters of given provider and cache, it
+// reuses already published map of the same cache.
+func newCacheVars(providerName, cacheName string) *cacheVars {
+	expvarMu.Lock()
+	defer expvarMu.Unlock()
+	if expvarRoot == nil {
+		if v, ok := expvar.Get(ExpvarName).(*expvar.Map); ok {
+			expvarRoot = v
+		} else {
+			expvarRoot = expvar.NewMap(ExpvarName)
+		}
+	}
+	pv, ok := expvarRoot.Get(providerName).(*expvar.Map)
+	if !ok {
+		pv = new(expvar.Map).Init()
+		expvarRoot.Set(providerName, pv)
+	}
+	cv, ok := pv.Get(cacheName).(*expvar.Map)
+	if !ok {
+		cv = new(expvar.Map).Init()
+		for _, n := range []string{"ops", "hits", "misses", "errors"} {
+			cv.Add(n, 0)
+		}
+		pv.Set(cacheName, cv)
+	}
+	return &cacheVars{m: cv}
+}
+
+func (cv *cacheVars) record(o *operation, err error) {
+	if cv == nil {
+		return
+	}
+	cv.m.Add("ops", 1)
+	if o.hits > 0 {
+		cv.m.Add("hits", int64(o.hits))
+	}
+	if o.misses > 0 {
+		cv.m.Add("misses", int64(o.misses))
+	}
+	if err != nil {
+		cv.m.Add("errors", 1)
+	}
+}
+
+func (cv *cacheVars) add(name string, delta int64) {
+	if cv == nil {
+		return
+	}
+	cv.m.Add(name, delta)
+}
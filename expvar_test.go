@@ -0,0 +1,31 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"expvar"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpvarCacheCounters(t *testing.T) {
+	cv := newCacheVars("expvarprovider", "expvarcache")
+	cv.record(&operation{hits: 1}, nil)
+	cv.record(&operation{misses: 2}, nil)
+	cv.record(&operation{}, errors.New("connection refused"))
+
+	// same cache reuses the published counters
+	assert.Equal(t, cv.m, newCacheVars("expvarprovider", "expvarcache").m)
+
+	root := expvar.Get(ExpvarName).(*expvar.Map)
+	pv := root.Get("expvarprovider").(*expvar.Map)
+	cm := pv.Get("expvarcache").(*expvar.Map)
+	assert.Equal(t, "3", cm.Get("ops").String())
+	assert.Equal(t, "1", cm.Get("hits").String())
+	assert.Equal(t, "2", cm.Get("misses").String())
+	assert.Equal(t, "1", cm.Get("errors").String())
+}
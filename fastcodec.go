@@ -0,0 +1,563 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// codecFast is the codec id of the fast codec payload.
+const codecFast uint32 = 2
+
+// fastVersion is the current fast codec payload version.
+const fastVersion uint32 = 1
+
+var (
+	errFastShort = &corruptError{errors.New("fast codec: truncated payload")}
+
+	binaryMarshalerType   = reflect.TypeOf((*encoding.BinaryMarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// RegisterFast function registers the value type for the fast codec under
+// given name, the name identifies the type in the stored payload so it must
+// be stable and unique across the app instances. Register the types at init,
+// like `gob.Register`.
+//
+// Types implementing `encoding.BinaryMarshaler` and
+// `encoding.BinaryUnmarshaler`, e.g. generated marshalers, are encoded with
+// them. Other types are encoded by the codec compiled once per type: bool,
+// numbers, string, slices, arrays, maps, pointers and structs of them,
+// unexported struct fields are skipped. Interface, chan, func and complex
+// types are not supported.
+func RegisterFast(name string, v interface{}) error {
+	if name == "" || v == nil {
+		return errors.New("aah/cache: fast codec requires type name and value")
+	}
+	t := reflect.TypeOf(v)
+	fastMu.Lock()
+	defer fastMu.Unlock()
+	if ft, found := fastByName[name]; found && ft.t != t {
+		return fmt.Errorf("aah/cache: fast codec name %s is registered for %v", name, ft.t)
+	}
+	if ft, found := fastByType[t]; found && ft.name != name {
+		return fmt.Errorf("aah/cache: fast codec type %v is registered as %s", t, ft.name)
+	}
+	c, err := compileCodec(t, make(map[reflect.Type]*typeCodec))
+	if err != nil {
+		return fmt.Errorf("aah/cache: fast codec %s %v", name, err)
+	}
+	ft := &fastType{name: name, t: t, c: c, fingerprint: crc32.ChecksumIEEE([]byte(layout(t, nil)))}
+	fastByName[name] = ft
+	fastByType[t] = ft
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+var (
+	fastMu     sync.RWMutex
+	fastByName = make(map[string]*fastType)
+	fastByType = make(map[reflect.Type]*fastType)
+)
+
+type fastType struct {
+	name        string
+	t           reflect.Type
+	c           *typeCodec
+	fingerprint uint32
+}
+
+type (
+	encFunc func(b []byte, v reflect.Value) ([]byte, error)
+	decFunc func(d *fastDecoder, v reflect.Value) error
+)
+
+type typeCodec struct {
+	enc encFunc
+	dec decFunc
+}
+
+// parseCodec function reports whether the cache writes with the fast codec.
+// With `codec = "fast"` the values of the types registered via
+// `RegisterFast` are written with the fast codec, so the type descriptors
+// are not encoded per entry as gob does; the other values are written with
+// gob. Both are readable regardless of the setting. Fast payload carries the
+// layout fingerprint of the type, entries written with another layout of the
+// type are treated as cache miss; schema migrations do not apply to them.
+//
+//	cache {
+//	  mycache {
+//	    # gob or fast; default value is gob
+//	    codec = "fast"
+//	  }
+//	}
+func parseCodec(m *memcacheCache) (bool, error) {
+	switch codec := m.settingString("codec", "gob"); codec {
+	case "gob", "":
+		return false, nil
+	case "fast":
+		return true, nil
+	default:
+		return false, fmt.Errorf("aah/cache/%s: unsupported codec '%s'", m.Name(), codec)
+	}
+}
+
+// encodeFast function returns the fast codec payload of the entry, false if
+// the value type is not registered.
+//
+// Payload: name length, name, fingerprint, expiration, write time, compute
+// cost, key echo and the value.
+func encodeFast(e *entry) ([]byte, bool, error) {
+	if e.V == nil {
+		return nil, false, nil
+	}
+	fastMu.RLock()
+	ft, found := fastByType[reflect.TypeOf(e.V)]
+	fastMu.RUnlock()
+	if !found {
+		return nil, false, nil
+	}
+	b := make([]byte, 0, 64+len(ft.name)+sizeHint(e.V))
+	b = appendString(b, ft.name)
+	b = binary.BigEndian.AppendUint32(b, ft.fingerprint)
+	b = binary.AppendVarint(b, int64(e.D))
+	b = binary.AppendVarint(b, e.T)
+	b = binary.AppendVarint(b, e.C)
+	b = appendString(b, e.K)
+	b, err := ft.c.enc(b, reflect.ValueOf(e.V))
+	if err != nil {
+		return nil, false, err
+	}
+	return b, true, nil
+}
+
+// decodeFast function decodes the fast codec payload, it returns the type
+// name of the payload and nil entry if the type is not registered or its
+// layout differs.
+func decodeFast(value []byte) (*entry, string, error) {
+	d := &fastDecoder{b: value}
+	n, err := d.uvarint()
+	if err != nil {
+		return nil, "", err
+	}
+	name, err := d.bytes(int(n))
+	if err != nil {
+		return nil, "", err
+	}
+	fastMu.RLock()
+	ft, found := fastByName[string(name)]
+	fastMu.RUnlock()
+	fp, err := d.bytes(4)
+	if err != nil {
+		return nil, "", err
+	}
+	if !found || binary.BigEndian.Uint32(fp) != ft.fingerprint {
+		return nil, string(name), nil
+	}
+	e := new(entry)
+	d32, err := d.varint()
+	if err != nil {
+		return nil, ft.name, err
+	}
+	e.D = int32(d32)
+	if e.T, err = d.varint(); err != nil {
+		return nil, ft.name, err
+	}
+	if e.C, err = d.varint(); err != nil {
+		return nil, ft.name, err
+	}
+	if e.K, err = d.string(); err != nil {
+		return nil, ft.name, err
+	}
+	rv := reflect.New(ft.t).Elem()
+	if err = ft.c.dec(d, rv); err != nil {
+		return nil, ft.name, err
+	}
+	e.V = rv.Interface()
+	return e, ft.name, nil
+}
+
+func fastFlags() uint32 {
+	return codecFast<<flagCodecShift | fastVersion<<flagVersionShift
+}
+
+func isFast(flags uint32) bool {
+	return flags>>flagCodecShift&0xF == codecFast
+}
+
+// compileCodec function returns the codec of given type, seen holds the
+// codecs being compiled so the recursive types refer to themselves.
+func compileCodec(t reflect.Type, seen map[reflect.Type]*typeCodec) (*typeCodec, error) {
+	if c, found := seen[t]; found {
+		return c, nil
+	}
+	c := new(typeCodec)
+	seen[t] = c
+
+	if t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(binaryMarshalerType) &&
+		reflect.PtrTo(t).Implements(binaryUnmarshalerType) {
+		c.enc = encodeBinary
+		c.dec = decodeBinary
+		return c, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			if v.Bool() {
+				return append(b, 1), nil
+			}
+			return append(b, 0), nil
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			p, err := d.bytes(1)
+			if err == nil {
+				v.SetBool(p[0] == 1)
+			}
+			return err
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			return binary.AppendVarint(b, v.Int()), nil
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			n, err := d.varint()
+			if err == nil && v.OverflowInt(n) {
+				err = errFastShort
+			}
+			if err == nil {
+				v.SetInt(n)
+			}
+			return err
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			return binary.AppendUvarint(b, v.Uint()), nil
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			n, err := d.uvarint()
+			if err == nil && v.OverflowUint(n) {
+				err = errFastShort
+			}
+			if err == nil {
+				v.SetUint(n)
+			}
+			return err
+		}
+	case reflect.Float32, reflect.Float64:
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			return binary.BigEndian.AppendUint64(b, math.Float64bits(v.Float())), nil
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			p, err := d.bytes(8)
+			if err == nil {
+				v.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(p)))
+			}
+			return err
+		}
+	case reflect.String:
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			return appendString(b, v.String()), nil
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			s, err := d.string()
+			if err == nil {
+				v.SetString(s)
+			}
+			return err
+		}
+	case reflect.Slice:
+		if t.Elem().Kind() == reflect.Uint8 {
+			c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+				if v.IsNil() {
+					return append(b, 0), nil
+				}
+				b = binary.AppendUvarint(b, uint64(v.Len())+1)
+				return append(b, v.Bytes()...), nil
+			}
+			c.dec = func(d *fastDecoder, v reflect.Value) error {
+				n, err := d.length()
+				if err != nil || n < 0 {
+					return err
+				}
+				p, err := d.bytes(n)
+				if err == nil {
+					v.SetBytes(append(make([]byte, 0, n), p...))
+				}
+				return err
+			}
+			break
+		}
+		ec, err := compileCodec(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			if v.IsNil() {
+				return append(b, 0), nil
+			}
+			b = binary.AppendUvarint(b, uint64(v.Len())+1)
+			var err error
+			for i := 0; i < v.Len() && err == nil; i++ {
+				b, err = ec.enc(b, v.Index(i))
+			}
+			return b, err
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			n, err := d.length()
+			if err != nil || n < 0 {
+				return err
+			}
+			s := reflect.MakeSlice(t, n, n)
+			for i := 0; i < n && err == nil; i++ {
+				err = ec.dec(d, s.Index(i))
+			}
+			v.Set(s)
+			return err
+		}
+	case reflect.Array:
+		ec, err := compileCodec(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			var err error
+			for i := 0; i < v.Len() && err == nil; i++ {
+				b, err = ec.enc(b, v.Index(i))
+			}
+			return b, err
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			var err error
+			for i := 0; i < v.Len() && err == nil; i++ {
+				err = ec.dec(d, v.Index(i))
+			}
+			return err
+		}
+	case reflect.Map:
+		kc, err := compileCodec(t.Key(), seen)
+		if err != nil {
+			return nil, err
+		}
+		vc, err := compileCodec(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			if v.IsNil() {
+				return append(b, 0), nil
+			}
+			b = binary.AppendUvarint(b, uint64(v.Len())+1)
+			var err error
+			iter := v.MapRange()
+			for iter.Next() && err == nil {
+				if b, err = kc.enc(b, iter.Key()); err == nil {
+					b, err = vc.enc(b, iter.Value())
+				}
+			}
+			return b, err
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			n, err := d.length()
+			if err != nil || n < 0 {
+				return err
+			}
+			mv := reflect.MakeMapWithSize(t, n)
+			for i := 0; i < n; i++ {
+				mk, me := reflect.New(t.Key()).Elem(), reflect.New(t.Elem()).Elem()
+				if err = kc.dec(d, mk); err != nil {
+					return err
+				}
+				if err = vc.dec(d, me); err != nil {
+					return err
+				}
+				mv.SetMapIndex(mk, me)
+			}
+			v.Set(mv)
+			return nil
+		}
+	case reflect.Ptr:
+		ec, err := compileCodec(t.Elem(), seen)
+		if err != nil {
+			return nil, err
+		}
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			if v.IsNil() {
+				return append(b, 0), nil
+			}
+			return ec.enc(append(b, 1), v.Elem())
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			p, err := d.bytes(1)
+			if err != nil || p[0] == 0 {
+				return err
+			}
+			pv := reflect.New(t.Elem())
+			if err = ec.dec(d, pv.Elem()); err == nil {
+				v.Set(pv)
+			}
+			return err
+		}
+	case reflect.Struct:
+		var (
+			fields []int
+			codecs []*typeCodec
+		)
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if f.PkgPath != "" {
+				continue
+			}
+			fc, err := compileCodec(f.Type, seen)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %v", f.Name, err)
+			}
+			fields = append(fields, i)
+			codecs = append(codecs, fc)
+		}
+		c.enc = func(b []byte, v reflect.Value) ([]byte, error) {
+			var err error
+			for i, fi := range fields {
+				if b, err = codecs[i].enc(b, v.Field(fi)); err != nil {
+					return b, err
+				}
+			}
+			return b, nil
+		}
+		c.dec = func(d *fastDecoder, v reflect.Value) error {
+			for i, fi := range fields {
+				if err := codecs[i].dec(d, v.Field(fi)); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported type %v", t)
+	}
+	return c, nil
+}
+
+func encodeBinary(b []byte, v reflect.Value) ([]byte, error) {
+	pv := reflect.New(v.Type())
+	pv.Elem().Set(v)
+	p, err := pv.Interface().(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return b, err
+	}
+	b = binary.AppendUvarint(b, uint64(len(p)))
+	return append(b, p...), nil
+}
+
+func decodeBinary(d *fastDecoder, v reflect.Value) error {
+	n, err := d.uvarint()
+	if err != nil {
+		return err
+	}
+	p, err := d.bytes(int(n))
+	if err != nil {
+		return err
+	}
+	return v.Addr().Interface().(encoding.BinaryUnmarshaler).UnmarshalBinary(p)
+}
+
+// layout function returns the description of the type layout the codec
+// depends on, it is fingerprinted to detect the incompatible payloads.
+func layout(t reflect.Type, seen map[reflect.Type]bool) string {
+	if seen == nil {
+		seen = make(map[reflect.Type]bool)
+	}
+	if seen[t] || t.Kind() != reflect.Ptr && reflect.PtrTo(t).Implements(binaryMarshalerType) {
+		return t.String()
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Ptr:
+		return t.Kind().String() + "(" + layout(t.Elem(), seen) + ")"
+	case reflect.Map:
+		return "map(" + layout(t.Key(), seen) + "," + layout(t.Elem(), seen) + ")"
+	case reflect.Struct:
+		var sb strings.Builder
+		sb.WriteString("struct{")
+		for i := 0; i < t.NumField(); i++ {
+			if f := t.Field(i); f.PkgPath == "" {
+				sb.WriteString(f.Name + " " + layout(f.Type, seen) + ";")
+			}
+		}
+		sb.WriteString("}")
+		return sb.String()
+	}
+	return t.Kind().String()
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// fastDecoder reads the fast codec payload.
+type fastDecoder struct {
+	b []byte
+}
+
+func (d *fastDecoder) bytes(n int) ([]byte, error) {
+	if n < 0 || n > len(d.b) {
+		return nil, errFastShort
+	}
+	p := d.b[:n]
+	d.b = d.b[n:]
+	return p, nil
+}
+
+func (d *fastDecoder) uvarint() (uint64, error) {
+	n, size := binary.Uvarint(d.b)
+	if size <= 0 {
+		return 0, errFastShort
+	}
+	d.b = d.b[size:]
+	return n, nil
+}
+
+func (d *fastDecoder) varint() (int64, error) {
+	n, size := binary.Varint(d.b)
+	if size <= 0 {
+		return 0, errFastShort
+	}
+	d.b = d.b[size:]
+	return n, nil
+}
+
+func (d *fastDecoder) string() (string, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return "", err
+	}
+	p, err := d.bytes(int(n))
+	return string(p), err
+}
+
+// length method reads the length of nil-able slice or map, -1 for nil.
+func (d *fastDecoder) length() (int, error) {
+	n, err := d.uvarint()
+	if err != nil {
+		return 0, err
+	}
+	if n > uint64(len(d.b))+1 {
+		// each element takes at least a byte
+		return 0, errFastShort
+	}
+	return int(n) - 1, nil
+}
@@ -0,0 +1,116 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+type fastAddress struct {
+	City string
+	Zip  uint32
+}
+
+type fastUser struct {
+	ID       int64
+	Name     string
+	Active   bool
+	Score    float64
+	Tags     []string
+	Avatar   []byte
+	Attrs    map[string]int
+	Home     *fastAddress
+	Work     *fastAddress
+	Created  time.Time
+	Codes    [3]int8
+	password string
+}
+
+type fastNode struct {
+	Value int
+	Next  *fastNode
+}
+
+func TestMemcacheFastCodec(t *testing.T) {
+	assert.Nil(t, RegisterFast("test.fastUser", fastUser{}))
+	assert.Nil(t, RegisterFast("test.fastUser", fastUser{}))
+	assert.Nil(t, RegisterFast("test.fastNode", &fastNode{}))
+
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "fast"}, p: p, keyPrefix: "fast-",
+		counters: new(counters), chunkLimit: defaultChunkSize, fastCodec: true}
+
+	created := time.Date(2018, 7, 10, 15, 56, 16, 0, time.UTC)
+	u := fastUser{ID: 42, Name: "jeeva", Active: true, Score: 9.5, Tags: []string{"a", "b"},
+		Avatar: []byte{1, 2}, Attrs: map[string]int{"x": 1}, Home: &fastAddress{City: "Chennai", Zip: 600001},
+		Created: created, Codes: [3]int8{-1, 0, 1}, password: "secret"}
+	item, err := m.encodeEntry("user", &entry{D: 60, V: u, T: 1, C: 2})
+	assert.Nil(t, err)
+	assert.True(t, isFast(item.Flags))
+	assert.True(t, knownFormat(item.Flags))
+
+	e, found := m.decodeItem("user", item)
+	assert.True(t, found)
+	assert.Equal(t, int32(60), e.D)
+	assert.Equal(t, int64(1), e.T)
+	assert.Equal(t, int64(2), e.C)
+	v := e.V.(fastUser)
+	assert.Equal(t, u.Name, v.Name)
+	assert.Equal(t, u.Tags, v.Tags)
+	assert.Equal(t, u.Avatar, v.Avatar)
+	assert.Equal(t, u.Attrs, v.Attrs)
+	assert.Equal(t, *u.Home, *v.Home)
+	assert.Nil(t, v.Work)
+	assert.True(t, created.Equal(v.Created))
+	assert.Equal(t, u.Codes, v.Codes)
+	assert.Equal(t, "", v.password)
+
+	// recursive type
+	item, err = m.encodeEntry("list", &entry{V: &fastNode{Value: 1, Next: &fastNode{Value: 2}}})
+	assert.Nil(t, err)
+	e, found = m.decodeItem("list", item)
+	assert.True(t, found)
+	assert.Equal(t, 2, e.V.(*fastNode).Next.Value)
+
+	// layout change is a miss, truncated payload is undecodable
+	value := item.Value
+	item.Value = append([]byte(nil), value...)
+	item.Value[len("test.fastNode")+1]++
+	_, found = m.decodeItem("list", item)
+	assert.False(t, found)
+	_, _, err = decodeFast(value[:len(value)-1])
+	assert.NotNil(t, err)
+
+	// unregistered types are written with gob, readable by gob setting
+	item, err = m.encodeEntry("plain", &entry{V: "plain"})
+	assert.Nil(t, err)
+	assert.False(t, isFast(item.Flags))
+	m.fastCodec = false
+	item, err = m.encodeEntry("user", &entry{D: 60, V: u})
+	assert.Nil(t, err)
+	assert.False(t, isFast(item.Flags))
+}
+
+func TestMemcacheFastCodecRegister(t *testing.T) {
+	assert.NotNil(t, RegisterFast("", fastAddress{}))
+	assert.NotNil(t, RegisterFast("test.iface", struct{ V interface{} }{}))
+	assert.NotNil(t, RegisterFast("test.chan", make(chan int)))
+
+	assert.Nil(t, RegisterFast("test.fastAddress", fastAddress{}))
+	err := RegisterFast("test.fastAddress", &fastAddress{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is registered for")
+	err = RegisterFast("test.address", fastAddress{})
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "is registered as")
+}
@@ -0,0 +1,237 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"math/rand"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ErrFault error is the default error injected by the `FaultPolicy`, it is
+// a network timeout error, so it is classified as `ErrServerUnavailable`.
+var ErrFault error = faultError{}
+
+// FaultPolicy struct describes the memcache failures simulated by the
+// provider, to verify the fallback and circuit-breaker behavior of the app in
+// tests or staging.
+type FaultPolicy struct {
+	// ErrorRate is the fraction of the operations failed, from 0 to 1.
+	ErrorRate float64
+
+	// Err is the error injected, default is `ErrFault`.
+	Err error
+
+	// Latency is added to the delayed operations, plus random Jitter up to
+	// given duration.
+	Latency time.Duration
+	Jitter  time.Duration
+
+	// LatencyRate is the fraction of the operations delayed, 0 means all.
+	LatencyRate float64
+
+	// Ops limits the faults to given memcache operations i.e. get, set,
+	// add, replace, cas, delete, incr, decr, touch and flush_all. Empty
+	// means all.
+	Ops []string
+}
+
+// SetFaultPolicy method enables the fault injection for the caches of the
+// provider, nil disables it. Policy could be configured too, it is applied
+// on provider init.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//	    fault {
+//	      # default value is false
+//	      enable = true
+//	      error_rate = 0.05
+//	      latency = "20ms"
+//	      jitter = "30ms"
+//	      latency_rate = 0.5
+//	      ops = ["get", "set"]
+//	    }
+//	  }
+//	}
+func (p *Provider) SetFaultPolicy(policy *FaultPolicy) {
+	if policy == nil {
+		p.fault.Store((*faultPolicy)(nil))
+		return
+	}
+	fp := &faultPolicy{FaultPolicy: *policy, rnd: rand.New(rand.NewSource(time.Now().UnixNano()))}
+	if fp.Err == nil {
+		fp.Err = ErrFault
+	}
+	if len(fp.Ops) > 0 {
+		fp.ops = make(map[string]bool, len(fp.Ops))
+		for _, op := range fp.Ops {
+			fp.ops[op] = true
+		}
+	}
+	p.fault.Store(fp)
+	p.logger.Warnf("aah/cache/provider: %s fault injection enabled, error rate %v, latency %v",
+		p.name, fp.ErrorRate, fp.Latency)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type faultError struct{}
+
+func (faultError) Error() string   { return "aah/cache: injected fault" }
+func (faultError) Timeout() bool   { return true }
+func (faultError) Temporary() bool { return true }
+
+type faultPolicy struct {
+	FaultPolicy
+	ops map[string]bool
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+// configureFault method applies the fault policy of the provider config.
+func (p *Provider) configureFault() {
+	cfgPrefix := "cache." + p.name + ".fault."
+	cfg := p.config()
+	if !cfg.BoolDefault(cfgPrefix+"enable", false) {
+		return
+	}
+	policy := &FaultPolicy{
+		Latency: parseDuration(cfg.StringDefault(cfgPrefix+"latency", ""), "0s"),
+		Jitter:  parseDuration(cfg.StringDefault(cfgPrefix+"jitter", ""), "0s"),
+	}
+	policy.ErrorRate, _ = strconv.ParseFloat(cfg.StringDefault(cfgPrefix+"error_rate", "0"), 64)
+	policy.LatencyRate, _ = strconv.ParseFloat(cfg.StringDefault(cfgPrefix+"latency_rate", "0"), 64)
+	policy.Ops, _ = cfg.StringList(cfgPrefix + "ops")
+	p.SetFaultPolicy(policy)
+}
+
+func (p *Provider) faultPolicy() *faultPolicy {
+	fp, _ := p.fault.Load().(*faultPolicy)
+	return fp
+}
+
+func (fp *faultPolicy) float() float64 {
+	fp.mu.Lock()
+	defer fp.mu.Unlock()
+	return fp.rnd.Float64()
+}
+
+// inject method applies the policy to given operation, it returns the error
+// to fail the operation with.
+func (fp *faultPolicy) inject(op string) error {
+	if fp.ops != nil && !fp.ops[op] {
+		return nil
+	}
+	if fp.Latency > 0 || fp.Jitter > 0 {
+		if fp.LatencyRate <= 0 || fp.float() < fp.LatencyRate {
+			d := fp.Latency
+			if fp.Jitter > 0 {
+				d += time.Duration(fp.float() * float64(fp.Jitter))
+			}
+			time.Sleep(d)
+		}
+	}
+	if fp.ErrorRate > 0 && fp.float() < fp.ErrorRate {
+		return fp.Err
+	}
+	return nil
+}
+
+// faultClient injects the faults of the policy into the memcache calls.
+type faultClient struct {
+	client
+	fp *faultPolicy
+}
+
+func (f faultClient) Get(key string) (*memcache.Item, error) {
+	if err := f.fp.inject("get"); err != nil {
+		return nil, err
+	}
+	return f.client.Get(key)
+}
+
+func (f faultClient) metaGet(key, flags string) (string, error) {
+	if err := f.fp.inject("get"); err != nil {
+		return "", err
+	}
+	return f.client.metaGet(key, flags)
+}
+
+func (f faultClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	if err := f.fp.inject("get"); err != nil {
+		return nil, err
+	}
+	return f.client.GetMulti(keys)
+}
+
+func (f faultClient) Set(item *memcache.Item) error {
+	if err := f.fp.inject("set"); err != nil {
+		return err
+	}
+	return f.client.Set(item)
+}
+
+func (f faultClient) Add(item *memcache.Item) error {
+	if err := f.fp.inject("add"); err != nil {
+		return err
+	}
+	return f.client.Add(item)
+}
+
+func (f faultClient) Replace(item *memcache.Item) error {
+	if err := f.fp.inject("replace"); err != nil {
+		return err
+	}
+	return f.client.Replace(item)
+}
+
+func (f faultClient) CompareAndSwap(item *memcache.Item) error {
+	if err := f.fp.inject("cas"); err != nil {
+		return err
+	}
+	return f.client.CompareAndSwap(item)
+}
+
+func (f faultClient) Delete(key string) error {
+	if err := f.fp.inject("delete"); err != nil {
+		return err
+	}
+	return f.client.Delete(key)
+}
+
+func (f faultClient) Increment(key string, delta uint64) (uint64, error) {
+	if err := f.fp.inject("incr"); err != nil {
+		return 0, err
+	}
+	return f.client.Increment(key, delta)
+}
+
+func (f faultClient) Decrement(key string, delta uint64) (uint64, error) {
+	if err := f.fp.inject("decr"); err != nil {
+		return 0, err
+	}
+	return f.client.Decrement(key, delta)
+}
+
+func (f faultClient) Touch(key string, seconds int32) error {
+	if err := f.fp.inject("touch"); err != nil {
+		return err
+	}
+	return f.client.Touch(key, seconds)
+}
+
+func (f faultClient) FlushAll() error {
+	if err := f.fp.inject("flush_all"); err != nil {
+		return err
+	}
+	return f.client.FlushAll()
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheFaultPolicy(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	m := &memcacheCache{p: p}
+	_, ok := m.client().(faultClient)
+	assert.False(t, ok)
+
+	p.SetFaultPolicy(&FaultPolicy{ErrorRate: 1, Ops: []string{"get"}})
+	fc, ok := m.client().(faultClient)
+	assert.True(t, ok)
+	fc.client = &dryRunClient{}
+
+	_, err := fc.Get("key1")
+	assert.Equal(t, ErrFault, err)
+	assert.True(t, unavailable(err))
+	_, err = fc.GetMulti([]string{"key1"})
+	assert.Equal(t, ErrFault, err)
+	assert.Nil(t, fc.Set(&memcache.Item{Key: "key1"}))
+
+	errDown := errors.New("down")
+	p.SetFaultPolicy(&FaultPolicy{ErrorRate: 1, Err: errDown})
+	fc = m.client().(faultClient)
+	fc.client = &dryRunClient{}
+	assert.Equal(t, errDown, fc.Delete("key1"))
+	assert.Equal(t, errDown, fc.FlushAll())
+
+	p.SetFaultPolicy(&FaultPolicy{Latency: 20 * time.Millisecond, Jitter: 5 * time.Millisecond})
+	fc = m.client().(faultClient)
+	fc.client = &dryRunClient{}
+	start := time.Now()
+	assert.Nil(t, fc.Touch("key1", 10))
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+
+	p.SetFaultPolicy(nil)
+	_, ok = m.client().(faultClient)
+	assert.False(t, ok)
+}
+
+func TestMemcacheFaultRate(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.SetFaultPolicy(&FaultPolicy{ErrorRate: 0.5})
+	fp := p.faultPolicy()
+	failed := 0
+	for i := 0; i < 1000; i++ {
+		if fp.inject("set") != nil {
+			failed++
+		}
+	}
+	assert.True(t, failed > 350 && failed < 650)
+}
@@ -0,0 +1,93 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"aahframe.work/cache"
+)
+
+// xfetchBeta is the XFetch tuning parameter, value greater than 1 favors
+// earlier recomputation. 1 is the recommended default.
+const xfetchBeta = 1.0
+
+// Fetch method returns the cached value for given key if it exists otherwise
+// it calls loader func, puts the computed value into cache store with given
+// expiration and returns it.
+//
+// Method implements XFetch probabilistic early expiration, the entry carries
+// its write timestamp and computation cost, so one caller recomputes a hot
+// key ahead of its expiry instead of all the callers missing at once. If the
+// early recomputation fails, the still valid cached value is returned.
+//
+// Loader func returning `ErrNotFound` is cached as negative entry, Fetch
+// returns `ErrNotFound` without calling loader until negative entry expires.
+//
+// With `lease` enabled, only one caller across the app instances calls the
+// loader func, other callers return the stale value or wait for the lease
+// holder to store the value.
+func (m *memcacheCache) Fetch(k string, d time.Duration, fn LoaderFunc) (interface{}, error) {
+	e, found := m.getEntry(k)
+	if found && e.notFound {
+		return nil, ErrNotFound
+	}
+	if found && !e.shouldRecompute(m.cfg.EvictionMode, time.Now()) {
+		return e.V, nil
+	}
+
+	load := func() (interface{}, error) {
+		start := time.Now()
+		v, err := fn()
+		if err == ErrNotFound {
+			if err = m.PutNotFound(k); err != nil {
+				m.p.logError(err)
+			}
+			return nil, ErrNotFound
+		}
+		if err != nil {
+			if found {
+				return e.V, nil
+			}
+			return nil, err
+		}
+
+		ne, err := m.newEntry(k, v, d)
+		if err != nil {
+			m.p.logError(err)
+			return v, nil
+		}
+		ne.C = int64(time.Since(start))
+		if err = m.storeEntry(m.client().Set, k, ne); err != nil {
+			m.p.logError(err)
+		}
+		return v, nil
+	}
+	if m.lease.enable {
+		var stale *entry
+		if found {
+			stale = e
+		}
+		return m.flight.Do("fetch:"+k, func() (interface{}, error) {
+			return m.leased(k, stale, load)
+		})
+	}
+	return m.flight.Do("fetch:"+k, load)
+}
+
+// shouldRecompute method reports XFetch decision for the entry,
+// recompute if `now - C * beta * ln(rand()) >= expiry`.
+func (e *entry) shouldRecompute(mode cache.EvictionMode, now time.Time) bool {
+	// Sliding entries are extended on every read and entries without
+	// metadata or expiry cannot be reasoned about.
+	if mode == cache.EvictionModeSlide || e.T == 0 || e.C <= 0 || e.D <= 0 {
+		return false
+	}
+	expiry := e.T + int64(e.D)*int64(time.Second)
+	gap := float64(e.C) * xfetchBeta * -math.Log(1-rand.Float64())
+	return float64(now.UnixNano())+gap >= float64(expiry)
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheFetch(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "fetchcache", ProviderName: "memcache1"}).(Cache)
+
+	calls := 0
+	loader := func() (interface{}, error) {
+		calls++
+		return "computed value", nil
+	}
+	for i := 0; i < 3; i++ {
+		v, err := c.Fetch("key1", 3*time.Second, loader)
+		assert.Nil(t, err)
+		assert.Equal(t, "computed value", v)
+	}
+	assert.Equal(t, 1, calls)
+
+	v, err := c.Fetch("key2", 3*time.Second, func() (interface{}, error) {
+		return nil, errors.New("origin down")
+	})
+	assert.Nil(t, v)
+	assert.Equal(t, errors.New("origin down"), err)
+
+	c.Flush()
+}
+
+func TestEntryShouldRecompute(t *testing.T) {
+	now := time.Now()
+	e := &entry{D: 60, T: now.UnixNano(), C: int64(time.Millisecond)}
+	assert.False(t, e.shouldRecompute(cache.EvictionModeTime, now))
+	assert.True(t, e.shouldRecompute(cache.EvictionModeTime, now.Add(61*time.Second)))
+	assert.False(t, e.shouldRecompute(cache.EvictionModeSlide, now.Add(61*time.Second)))
+
+	e = &entry{D: 60}
+	assert.False(t, e.shouldRecompute(cache.EvictionModeTime, now.Add(61*time.Second)))
+}
@@ -0,0 +1,83 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// GetInto method decodes the cached value of given key into dst, which must
+// be a non-nil pointer. It returns false on miss and `ErrNotFound` for the
+// negative entry.
+//
+// Values whose type is not registered with gob are stored with their
+// concrete gob encoding, GetInto decodes them straight into dst, so the
+// type need not be registered; `Get` returns nil for them. Registered values
+// are assigned to dst, in the interop mode JSON values are decoded into dst.
+// With `strict_types` enabled the stored type must match dst, otherwise
+// `ErrTypeMismatch` is returned.
+func (m *memcacheCache) GetInto(k string, dst interface{}) (bool, error) {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return false, fmt.Errorf("aah/cache/%s: destination must be a non-nil pointer, got %T", m.Name(), dst)
+	}
+	e, found := m.getEntry(k)
+	if !found {
+		return false, nil
+	}
+	if e.notFound {
+		return false, ErrNotFound
+	}
+	if err := m.verifyType(k, e, rv.Elem().Type()); err != nil {
+		return false, err
+	}
+	m.onRead(k, e)
+
+	var err error
+	switch {
+	case e.B != nil:
+		r := acquireReader(e.B)
+		err = gob.NewDecoder(r).Decode(dst)
+		releaseReader(r)
+	case m.interop != interopNone:
+		var b []byte
+		if b, err = json.Marshal(e.V); err == nil {
+			err = json.Unmarshal(b, dst)
+		}
+	default:
+		err = assign(rv.Elem(), e.V)
+	}
+	if err != nil {
+		return false, m.opError("get", k, m.key(k), ErrDecode, err)
+	}
+	return true, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported functions
+//______________________________________________________________________________
+
+// isUnregistered function reports whether the gob encode error is caused by
+// the value type not registered for interface.
+func isUnregistered(err error) bool {
+	return strings.Contains(err.Error(), "type not registered for interface")
+}
+
+// encodeConcrete function encodes the entry with the concrete gob encoding
+// of its value in `B`.
+func encodeConcrete(buf *bytes.Buffer, e *entry) error {
+	var vb bytes.Buffer
+	if err := gob.NewEncoder(&vb).Encode(e.V); err != nil {
+		return err
+	}
+	ce := *e
+	ce.V, ce.B = nil, vb.Bytes()
+	return gob.NewEncoder(buf).Encode(&ce)
+}
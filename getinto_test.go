@@ -0,0 +1,75 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"encoding/gob"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+// unregistered type is never registered with gob
+type unregistered struct {
+	ID   int
+	Tags []string
+}
+
+func TestMemcacheGetInto(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "getinto", ProviderName: "memcache1"}).(Cache)
+
+	assert.Nil(t, c.Put("key1", unregistered{ID: 1, Tags: []string{"a"}}, time.Minute))
+	assert.Nil(t, c.Get("key1"))
+
+	var v unregistered
+	found, err := c.GetInto("key1", &v)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, unregistered{ID: 1, Tags: []string{"a"}}, v)
+
+	assert.Nil(t, c.Put("key2", "text", time.Minute))
+	var s string
+	found, err = c.GetInto("key2", &s)
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "text", s)
+
+	found, err = c.GetInto("absent", &s)
+	assert.Nil(t, err)
+	assert.False(t, found)
+	_, err = c.GetInto("key2", s)
+	assert.NotNil(t, err)
+
+	_ = c.DeleteMulti([]string{"key1", "key2"})
+}
+
+func TestMemcacheEncodeConcrete(t *testing.T) {
+	gob.Register(entry{})
+	m := &memcacheCache{cfg: &cache.Config{Name: "getinto"}, p: &Provider{}}
+
+	item, err := m.encodeEntry("key1", &entry{D: 60, V: unregistered{ID: 7}})
+	assert.Nil(t, err)
+
+	var e entry
+	assert.Nil(t, decodeGob(item.Value, &e))
+	assert.Nil(t, e.V)
+	assert.NotNil(t, e.B)
+	assert.Equal(t, int32(60), e.D)
+	assert.Equal(t, "memcache.unregistered", e.S)
+
+	var v unregistered
+	assert.Nil(t, gob.NewDecoder(bytes.NewReader(e.B)).Decode(&v))
+	assert.Equal(t, 7, v.ID)
+}
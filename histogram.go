@@ -0,0 +1,152 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"math/bits"
+	"sync/atomic"
+	"time"
+)
+
+// LatencyStats struct holds the latency percentiles of an operation,
+// values are approximate within the histogram precision (~12.5%).
+type LatencyStats struct {
+	Count uint64
+	P50   time.Duration
+	P95   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// SizeStats struct holds the percentiles of the encoded value sizes in
+// bytes, values are approximate within the histogram precision (~12.5%).
+type SizeStats struct {
+	Count uint64
+	P50   int
+	P95   int
+	P99   int
+	Max   int
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// histogram
+//______________________________________________________________________________
+
+const (
+	// histSubBits is number of significant bits kept per power of two.
+	histSubBits    = 3
+	histSubBuckets = 1 << histSubBits
+	histBuckets    = histSubBuckets + (64-histSubBits)*histSubBuckets
+)
+
+// histogram is lock-free HDR-style log-linear histogram of microsecond
+// values, or of bytes via `add`, every power of two range is split into 8
+// linear sub-buckets.
+type histogram struct {
+	counts [histBuckets]uint64
+	total  uint64
+	max    uint64
+}
+
+func (h *histogram) record(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	h.add(uint64(d / time.Microsecond))
+}
+
+func (h *histogram) add(v uint64) {
+	atomic.AddUint64(&h.counts[histIndex(v)], 1)
+	atomic.AddUint64(&h.total, 1)
+	for {
+		max := atomic.LoadUint64(&h.max)
+		if v <= max || atomic.CompareAndSwapUint64(&h.max, max, v) {
+			break
+		}
+	}
+}
+
+func (h *histogram) snapshot() LatencyStats {
+	q := h.quantiles()
+	return LatencyStats{
+		Count: q.count,
+		P50:   time.Duration(q.p50) * time.Microsecond,
+		P95:   time.Duration(q.p95) * time.Microsecond,
+		P99:   time.Duration(q.p99) * time.Microsecond,
+		Max:   time.Duration(q.max) * time.Microsecond,
+	}
+}
+
+func (h *histogram) sizes() SizeStats {
+	q := h.quantiles()
+	return SizeStats{Count: q.count, P50: int(q.p50), P95: int(q.p95), P99: int(q.p99), Max: int(q.max)}
+}
+
+type quantiles struct {
+	count, p50, p95, p99, max uint64
+}
+
+func (h *histogram) quantiles() quantiles {
+	var counts [histBuckets]uint64
+	var total uint64
+	for i := range counts {
+		counts[i] = atomic.LoadUint64(&h.counts[i])
+		total += counts[i]
+	}
+	q := quantiles{count: total, max: atomic.LoadUint64(&h.max)}
+	if total == 0 {
+		return q
+	}
+	q.p50 = percentile(&counts, total, 0.50)
+	q.p95 = percentile(&counts, total, 0.95)
+	q.p99 = percentile(&counts, total, 0.99)
+	if q.p99 > q.max {
+		q.p99 = q.max
+	}
+	if q.p95 > q.max {
+		q.p95 = q.max
+	}
+	if q.p50 > q.max {
+		q.p50 = q.max
+	}
+	return q
+}
+
+// percentile function returns upper bound of the bucket holding given
+// quantile.
+func percentile(counts *[histBuckets]uint64, total uint64, q float64) uint64 {
+	rank := uint64(q*float64(total) + 0.5)
+	if rank == 0 {
+		rank = 1
+	}
+	var cum uint64
+	for i, c := range counts {
+		cum += c
+		if cum >= rank {
+			return histUpperBound(i)
+		}
+	}
+	return 0
+}
+
+func histIndex(v uint64) int {
+	if v < histSubBuckets {
+		return int(v)
+	}
+	msb := bits.Len64(v) - 1
+	shift := uint(msb - histSubBits)
+	sub := int((v >> shift) & (histSubBuckets - 1))
+	return histSubBuckets + (msb-histSubBits)*histSubBuckets + sub
+}
+
+func histUpperBound(i int) uint64 {
+	if i < histSubBuckets {
+		return uint64(i)
+	}
+	i -= histSubBuckets
+	shift := uint(i / histSubBuckets)
+	sub := uint64(i % histSubBuckets)
+	return ((histSubBuckets+sub+1)<<shift - 1)
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHistogramPercentiles(t *testing.T) {
+	h := new(histogram)
+	assert.Equal(t, LatencyStats{}, h.snapshot())
+
+	for i := 1; i <= 1000; i++ {
+		h.record(time.Duration(i) * 100 * time.Microsecond)
+	}
+	ls := h.snapshot()
+	assert.Equal(t, uint64(1000), ls.Count)
+	assert.Equal(t, 100*time.Millisecond, ls.Max)
+	assertWithin(t, 50*time.Millisecond, ls.P50)
+	assertWithin(t, 95*time.Millisecond, ls.P95)
+	assertWithin(t, 99*time.Millisecond, ls.P99)
+}
+
+func TestHistogramIndexBounds(t *testing.T) {
+	for _, v := range []uint64{0, 1, 7, 8, 9, 15, 16, 1000, 123456789} {
+		i := histIndex(v)
+		assert.True(t, v <= histUpperBound(i))
+		if i > 0 {
+			assert.True(t, v > histUpperBound(i-1))
+		}
+	}
+}
+
+func assertWithin(t *testing.T, expected, actual time.Duration) {
+	t.Helper()
+	diff := float64(actual-expected) / float64(expected)
+	assert.True(t, diff > -0.125 && diff < 0.125, "expected %v got %v", expected, actual)
+}
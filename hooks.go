@@ -0,0 +1,111 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"sync"
+	"time"
+)
+
+// Hooks struct holds the funcs invoked after cache operations, nil funcs
+// are skipped. Hooks are called synchronously on the caller goroutine, so
+// they should be quick; use goroutine for expensive work.
+type Hooks struct {
+	// OnHit is called for Get and GetMulti having at least one hit.
+	OnHit func(e HookEvent)
+
+	// OnMiss is called for Get and GetMulti having at least one miss.
+	OnMiss func(e HookEvent)
+
+	// OnPut is called after successful write of the entry.
+	OnPut func(e HookEvent)
+
+	// OnError is called when the operation fails.
+	OnError func(e HookEvent, err error)
+}
+
+// HookEvent struct holds the details of completed cache operation. Key is
+// empty for multi-key operations, Hits and Misses hold their counts.
+type HookEvent struct {
+	Cache    string
+	Op       string
+	Key      string
+	Hits     int
+	Misses   int
+	Bytes    int
+	Duration time.Duration
+}
+
+// AddHooks method registers the hooks for all the caches created by the
+// provider.
+func (p *Provider) AddHooks(h Hooks) {
+	p.hooks.add(h)
+}
+
+// AddHooks method registers the hooks for this cache.
+func (m *memcacheCache) AddHooks(h Hooks) {
+	m.root().hooks.add(h)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// hookList
+//______________________________________________________________________________
+
+type hookList struct {
+	mu    sync.RWMutex
+	hooks []Hooks
+}
+
+func (hl *hookList) add(h Hooks) {
+	hl.mu.Lock()
+	hl.hooks = append(hl.hooks, h)
+	hl.mu.Unlock()
+}
+
+func (hl *hookList) list() []Hooks {
+	hl.mu.RLock()
+	defer hl.mu.RUnlock()
+	return hl.hooks
+}
+
+// fireHooks method invokes the provider and cache hooks for the completed
+// operation.
+func (o *operation) fireHooks(err error) {
+	ph, ch := o.m.p.hooks.list(), o.m.root().hooks.list()
+	if len(ph)+len(ch) == 0 {
+		return
+	}
+
+	e := HookEvent{
+		Cache:    o.m.Name(),
+		Op:       o.name,
+		Key:      o.key,
+		Hits:     o.hits,
+		Misses:   o.misses,
+		Bytes:    o.bytes,
+		Duration: o.duration,
+	}
+	for _, hooks := range [][]Hooks{ph, ch} {
+		for _, h := range hooks {
+			switch {
+			case err != nil:
+				if h.OnError != nil {
+					h.OnError(e, err)
+				}
+			case o.name == "put":
+				if h.OnPut != nil {
+					h.OnPut(e)
+				}
+			default:
+				if o.hits > 0 && h.OnHit != nil {
+					h.OnHit(e)
+				}
+				if o.misses > 0 && h.OnMiss != nil {
+					h.OnMiss(e)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,45 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheHooks(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "hookscache", ProviderName: "memcache1"}))
+	c := mgr.Cache("hookscache").(Cache)
+
+	var events []string
+	mgr.Provider("memcache1").(*Provider).AddHooks(Hooks{
+		OnPut: func(e HookEvent) { events = append(events, "provider:put:"+e.Key) },
+	})
+	c.AddHooks(Hooks{
+		OnHit:  func(e HookEvent) { events = append(events, "hit:"+e.Key) },
+		OnMiss: func(e HookEvent) { events = append(events, "miss:"+e.Key) },
+		OnPut:  func(e HookEvent) { events = append(events, "put:"+e.Key) },
+	})
+
+	assert.Nil(t, c.Put("key1", "value1", 3*time.Second))
+	assert.Equal(t, "value1", c.Get("key1"))
+	assert.Nil(t, c.Get("key2"))
+	_ = c.GetMulti([]string{"key1", "key2"})
+
+	assert.Equal(t, []string{"provider:put:key1", "put:key1", "hit:key1", "miss:key2", "hit:", "miss:"}, events)
+
+	c.Flush()
+}
@@ -0,0 +1,188 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// HotKey struct holds the estimated access count of a key within the
+// reporting interval.
+type HotKey struct {
+	Key   string
+	Count uint64
+}
+
+// HotKeys method returns the top-N hottest keys of last completed reporting
+// interval, nil if `hot_keys` is not enabled.
+//
+// Key reads are sampled into count-min sketch and top-N keys are reported at
+// INFO level every interval, keys are logged in the `key_redaction` form.
+//
+//	cache {
+//	  mycache {
+//	    hot_keys {
+//	      enable = true
+//	      # default value is 10
+//	      top = 10
+//	      # default value is 1m
+//	      interval = "1m"
+//	      # sample 1 in N reads; default value is 1, i.e. every read
+//	      sample = 1
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) HotKeys() []HotKey {
+	if m.hot == nil {
+		return nil
+	}
+	return m.hot.report()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// hotKeys tracker
+//______________________________________________________________________________
+
+const (
+	sketchDepth = 4
+	sketchWidth = 2048
+)
+
+type hotKeys struct {
+	top    int
+	sample int
+
+	mu     sync.Mutex
+	sketch [sketchDepth][sketchWidth]uint32
+	cands  map[string]uint64
+	last   []HotKey
+
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+func newHotKeys(m *memcacheCache) *hotKeys {
+	hk := &hotKeys{
+		top:    m.settingInt("hot_keys.top", 10),
+		sample: m.settingInt("hot_keys.sample", 1),
+		cands:  make(map[string]uint64),
+		done:   make(chan struct{}),
+	}
+	if hk.top < 1 {
+		hk.top = 10
+	}
+	if hk.sample < 1 {
+		hk.sample = 1
+	}
+	interval := parseDuration(m.settingString("hot_keys.interval", ""), "1m")
+	go func() {
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-t.C:
+				if top := hk.rotate(); len(top) > 0 {
+					m.p.logger.Infof("aah/cache/%s: hot keys %s", m.Name(), formatHotKeys(top, m.logKey))
+				}
+			case <-hk.done:
+				return
+			}
+		}
+	}()
+	return hk
+}
+
+// stop method stops the periodic report of the hot keys.
+func (hk *hotKeys) stop() {
+	if hk == nil || hk.done == nil {
+		return
+	}
+	hk.stopOnce.Do(func() { close(hk.done) })
+}
+
+// observe method records the read of given key, sampled by `sample`.
+func (hk *hotKeys) observe(k string) {
+	if hk == nil || (hk.sample > 1 && rand.Intn(hk.sample) != 0) {
+		return
+	}
+
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+	est := hk.add(k)
+	if _, found := hk.cands[k]; found || len(hk.cands) < hk.top {
+		hk.cands[k] = est
+		return
+	}
+	minKey, minCount := "", ^uint64(0)
+	for ck, c := range hk.cands {
+		if c < minCount {
+			minKey, minCount = ck, c
+		}
+	}
+	if est > minCount {
+		delete(hk.cands, minKey)
+		hk.cands[k] = est
+	}
+}
+
+// add method increments the sketch counters of given key and returns its
+// estimated count.
+func (hk *hotKeys) add(k string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(k))
+	sum := h.Sum64()
+	h1, h2 := uint32(sum), uint32(sum>>32)
+	est := ^uint32(0)
+	for i := 0; i < sketchDepth; i++ {
+		idx := (h1 + uint32(i)*h2) % sketchWidth
+		hk.sketch[i][idx]++
+		if c := hk.sketch[i][idx]; c < est {
+			est = c
+		}
+	}
+	return uint64(est) * uint64(hk.sample)
+}
+
+// rotate method completes the current interval and returns its top keys.
+func (hk *hotKeys) rotate() []HotKey {
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+	top := make([]HotKey, 0, len(hk.cands))
+	for k, c := range hk.cands {
+		top = append(top, HotKey{Key: k, Count: c})
+	}
+	sort.Slice(top, func(i, j int) bool {
+		if top[i].Count == top[j].Count {
+			return top[i].Key < top[j].Key
+		}
+		return top[i].Count > top[j].Count
+	})
+	hk.last = top
+	hk.cands = make(map[string]uint64)
+	hk.sketch = [sketchDepth][sketchWidth]uint32{}
+	return top
+}
+
+func (hk *hotKeys) report() []HotKey {
+	hk.mu.Lock()
+	defer hk.mu.Unlock()
+	return append([]HotKey(nil), hk.last...)
+}
+
+// formatHotKeys function returns the log form of the hot keys, keys are
+// mapped by given func, e.g. the redaction of the cache.
+func formatHotKeys(top []HotKey, keyFn func(string) string) string {
+	parts := make([]string, len(top))
+	for i, hk := range top {
+		parts[i] = fmt.Sprintf("%s=%d", keyFn(hk.Key), hk.Count)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,65 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHotKeysTopN(t *testing.T) {
+	hk := &hotKeys{top: 3, sample: 1, cands: make(map[string]uint64)}
+	for i := 0; i < 100; i++ {
+		hk.observe("product:1")
+		if i%2 == 0 {
+			hk.observe("product:2")
+		}
+		if i%4 == 0 {
+			hk.observe("product:3")
+		}
+		hk.observe(fmt.Sprintf("cold:%d", i))
+	}
+
+	top := hk.rotate()
+	assert.Equal(t, 3, len(top))
+	assert.Equal(t, HotKey{Key: "product:1", Count: 100}, top[0])
+	assert.Equal(t, "product:2", top[1].Key)
+	assert.Equal(t, "product:3", top[2].Key)
+	assert.Equal(t, top, hk.report())
+	identity := func(k string) string { return k }
+	assert.Equal(t, "product:1=100, product:2=50, product:3=25", formatHotKeys(top, identity))
+	m := &memcacheCache{redactor: keyRedactor{mode: logKeyHash}}
+	assert.Equal(t, m.logKey("product:1")+"=100, "+m.logKey("product:2")+"=50, "+m.logKey("product:3")+"=25",
+		formatHotKeys(top, m.logKey))
+	assert.False(t, strings.Contains(formatHotKeys(top, m.logKey), "product:1"))
+
+	assert.Equal(t, 0, len(hk.rotate()))
+
+	var disabled *hotKeys
+	disabled.observe("key1")
+}
+
+func TestHotKeysStop(t *testing.T) {
+	m := newBenchCache()
+	m.hot = newHotKeys(m)
+	tenant := m.newTenant("acme")
+	assert.Nil(t, tenant.Close())
+	select {
+	case <-m.hot.done:
+		t.Fatal("tenant close stopped the shared hot key reporter")
+	default:
+	}
+
+	assert.Nil(t, m.Close())
+	assert.Nil(t, m.Close())
+	_, open := <-m.hot.done
+	assert.False(t, open)
+
+	var disabled *hotKeys
+	disabled.stop()
+}
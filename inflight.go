@@ -0,0 +1,195 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Inflight method returns the number of the memcache calls of the provider
+// caches in progress, 0 if `max_inflight` is not set.
+func (p *Provider) Inflight() int {
+	if p.inflight == nil {
+		return 0
+	}
+	return len(p.inflight.slots)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// inflightLimiter bounds the concurrent memcache calls of the provider
+// caches, protecting the connection pool and the memcache servers during
+// traffic spikes. Calls over the limit wait up to `max_inflight_wait` for a
+// free slot, then fail with `ErrOverloaded`; rejections are counted in
+// `Stats.Overloaded`.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//
+//	    # default value is 0, unlimited
+//	    max_inflight = 256
+//
+//	    # default value is 0s, fail fast
+//	    max_inflight_wait = "5ms"
+//	  }
+//	}
+type inflightLimiter struct {
+	slots chan struct{}
+	wait  time.Duration
+}
+
+func newInflightLimiter(p *Provider) *inflightLimiter {
+	cfgPrefix := "cache." + p.name + "."
+	n := p.config().IntDefault(cfgPrefix+"max_inflight", 0)
+	if n <= 0 {
+		return nil
+	}
+	return &inflightLimiter{
+		slots: make(chan struct{}, n),
+		wait:  parseDuration(p.config().StringDefault(cfgPrefix+"max_inflight_wait", ""), "0s"),
+	}
+}
+
+// acquire method takes a slot, it returns `ErrOverloaded` if none is freed
+// within the wait.
+func (l *inflightLimiter) acquire() error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	default:
+	}
+	if l.wait <= 0 {
+		return ErrOverloaded
+	}
+	t := time.NewTimer(l.wait)
+	defer t.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-t.C:
+		return ErrOverloaded
+	}
+}
+
+func (l *inflightLimiter) release() {
+	<-l.slots
+}
+
+// inflightClient holds a slot of the limiter during the memcache calls.
+type inflightClient struct {
+	client
+	l *inflightLimiter
+	m *memcacheCache
+}
+
+func (ic inflightClient) acquire() error {
+	if err := ic.l.acquire(); err != nil {
+		atomic.AddUint64(&ic.m.counters.overloaded, 1)
+		return err
+	}
+	return nil
+}
+
+func (ic inflightClient) Get(key string) (*memcache.Item, error) {
+	if err := ic.acquire(); err != nil {
+		return nil, err
+	}
+	defer ic.l.release()
+	return ic.client.Get(key)
+}
+
+func (ic inflightClient) metaGet(key, flags string) (string, error) {
+	if err := ic.acquire(); err != nil {
+		return "", err
+	}
+	defer ic.l.release()
+	return ic.client.metaGet(key, flags)
+}
+
+func (ic inflightClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	if err := ic.acquire(); err != nil {
+		return nil, err
+	}
+	defer ic.l.release()
+	return ic.client.GetMulti(keys)
+}
+
+func (ic inflightClient) Set(item *memcache.Item) error {
+	if err := ic.acquire(); err != nil {
+		return err
+	}
+	defer ic.l.release()
+	return ic.client.Set(item)
+}
+
+func (ic inflightClient) Add(item *memcache.Item) error {
+	if err := ic.acquire(); err != nil {
+		return err
+	}
+	defer ic.l.release()
+	return ic.client.Add(item)
+}
+
+func (ic inflightClient) Replace(item *memcache.Item) error {
+	if err := ic.acquire(); err != nil {
+		return err
+	}
+	defer ic.l.release()
+	return ic.client.Replace(item)
+}
+
+func (ic inflightClient) CompareAndSwap(item *memcache.Item) error {
+	if err := ic.acquire(); err != nil {
+		return err
+	}
+	defer ic.l.release()
+	return ic.client.CompareAndSwap(item)
+}
+
+func (ic inflightClient) Delete(key string) error {
+	if err := ic.acquire(); err != nil {
+		return err
+	}
+	defer ic.l.release()
+	return ic.client.Delete(key)
+}
+
+func (ic inflightClient) Increment(key string, delta uint64) (uint64, error) {
+	if err := ic.acquire(); err != nil {
+		return 0, err
+	}
+	defer ic.l.release()
+	return ic.client.Increment(key, delta)
+}
+
+func (ic inflightClient) Decrement(key string, delta uint64) (uint64, error) {
+	if err := ic.acquire(); err != nil {
+		return 0, err
+	}
+	defer ic.l.release()
+	return ic.client.Decrement(key, delta)
+}
+
+func (ic inflightClient) Touch(key string, seconds int32) error {
+	if err := ic.acquire(); err != nil {
+		return err
+	}
+	defer ic.l.release()
+	return ic.client.Touch(key, seconds)
+}
+
+func (ic inflightClient) FlushAll() error {
+	if err := ic.acquire(); err != nil {
+		return err
+	}
+	defer ic.l.release()
+	return ic.client.FlushAll()
+}
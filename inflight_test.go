@@ -0,0 +1,70 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+type blockingClient struct {
+	dryRunClient
+	release chan struct{}
+}
+
+func (b *blockingClient) Get(key string) (*memcache.Item, error) {
+	<-b.release
+	return nil, memcache.ErrCacheMiss
+}
+
+func TestMemcacheInflightLimit(t *testing.T) {
+	p := &Provider{name: "memcache1"}
+	p.appCfg.Store(config.NewEmpty())
+	assert.Nil(t, newInflightLimiter(p))
+	assert.Equal(t, 0, p.Inflight())
+
+	p.inflight = &inflightLimiter{slots: make(chan struct{}, 2)}
+	m := &memcacheCache{cfg: &cache.Config{Name: "inflight"}, p: p, counters: new(counters)}
+	ic, ok := m.client().(inflightClient)
+	assert.True(t, ok)
+	bc := &blockingClient{release: make(chan struct{})}
+	ic.client = bc
+
+	done := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := ic.Get("key1")
+			done <- err
+		}()
+	}
+	for p.Inflight() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	_, err := ic.Get("key2")
+	assert.Equal(t, ErrOverloaded, err)
+	assert.Equal(t, ErrOverloaded, ic.Delete("key2"))
+	assert.True(t, errors.Is(m.opError("get", "key2", "key2", nil, err), ErrOverloaded))
+	assert.Equal(t, uint64(2), m.Stats().Overloaded)
+
+	p.inflight.wait = 50 * time.Millisecond
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		bc.release <- struct{}{}
+	}()
+	assert.Nil(t, ic.Set(&memcache.Item{Key: "key3"}))
+	assert.Equal(t, memcache.ErrCacheMiss, <-done)
+
+	close(bc.release)
+	assert.Equal(t, memcache.ErrCacheMiss, <-done)
+	assert.Equal(t, 0, p.Inflight())
+	assert.Equal(t, uint64(2), m.Stats().Overloaded)
+}
@@ -0,0 +1,298 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Interop modes of the cache payload.
+const (
+	interopNone = iota
+	interopJSON
+	interopPylibmc
+	interopSpymemcached
+)
+
+// pylibmc flag conventions.
+const (
+	pylibmcBytes uint32 = 0
+	pylibmcInt   uint32 = 1 << 1
+	pylibmcLong  uint32 = 1 << 2
+	pylibmcZlib  uint32 = 1 << 3
+	pylibmcBool  uint32 = 1 << 4
+	pylibmcText  uint32 = 1 << 5
+)
+
+// spymemcached `SerializingTranscoder` flag conventions.
+const (
+	spySerialized uint32 = 1
+	spyCompressed uint32 = 2
+	spyBoolean    uint32 = 1 << 8
+	spyInt        uint32 = 2 << 8
+	spyLong       uint32 = 3 << 8
+	spyDate       uint32 = 4 << 8
+	spyByte       uint32 = 5 << 8
+	spyFloat      uint32 = 6 << 8
+	spyDouble     uint32 = 7 << 8
+	spyByteArray  uint32 = 8 << 8
+	spyTypes      uint32 = 0xFF00
+)
+
+var (
+	errInteropFlags  = errors.New("unsupported interop payload flags")
+	errInteropStream = errors.New("stream is not supported in interop mode")
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// parseInterop function returns the interop mode of the cache. In the interop
+// mode values are written without gob envelope, so that PHP, Python and Java
+// clients can read and write the same keys.
+//
+//	cache {
+//	  mycache {
+//	    # json - every value is plain JSON with flags 0
+//	    # pylibmc - str, int and bool as pylibmc/python-memcached does, others JSON
+//	    # spymemcached - String, primitives and byte[] as SerializingTranscoder
+//	    #   does, others JSON
+//	    # default value is none
+//	    interop = "json"
+//	  }
+//	}
+//
+// Interop entries carry no expiration or write timestamp, slide eviction,
+// soft TTL, negative caching and chunking do not apply to them. Values are
+// returned as the JSON decoded types, structs come back as
+// `map[string]interface{}`.
+func parseInterop(m *memcacheCache) (int, error) {
+	switch mode := m.settingString("interop", "none"); mode {
+	case "none", "":
+		return interopNone, nil
+	case "json":
+		return interopJSON, nil
+	case "pylibmc":
+		return interopPylibmc, nil
+	case "spymemcached":
+		return interopSpymemcached, nil
+	default:
+		return interopNone, fmt.Errorf("aah/cache/%s: unsupported interop mode '%s'", m.Name(), mode)
+	}
+}
+
+// encodeInterop method marshals the entry value per interop mode conventions.
+func (m *memcacheCache) encodeInterop(k string, e *entry) (*memcache.Item, error) {
+	var (
+		value []byte
+		flags uint32
+		err   error
+	)
+	switch m.interop {
+	case interopPylibmc:
+		value, flags, err = encodePylibmc(e.V)
+	case interopSpymemcached:
+		value, flags, err = encodeSpymemcached(e.V)
+	default:
+		value, err = json.Marshal(e.V)
+	}
+	if err != nil {
+		return nil, m.encodeFailed(k, e.V, err)
+	}
+	return &memcache.Item{
+		Key:        m.key(k),
+		Value:      value,
+		Flags:      e.flags&FlagUserMask | flags,
+		Expiration: expirationOf(e.D),
+	}, nil
+}
+
+// decodeInterop method unmarshals the item value per interop mode conventions.
+func (m *memcacheCache) decodeInterop(v *memcache.Item) (*entry, error) {
+	var (
+		value interface{}
+		err   error
+	)
+	flags := v.Flags &^ FlagUserMask
+	switch m.interop {
+	case interopPylibmc:
+		value, err = decodePylibmc(v.Value, flags)
+	case interopSpymemcached:
+		value, err = decodeSpymemcached(v.Value, flags)
+	default:
+		if flags != 0 {
+			return nil, errInteropFlags
+		}
+		value = decodeJSON(v.Value, func(b []byte) interface{} { return string(b) })
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &entry{V: value, flags: v.Flags & FlagUserMask}, nil
+}
+
+// decodeJSON function returns the JSON decoded value, payload which is not
+// JSON is returned via fallback.
+func decodeJSON(b []byte, fallback func([]byte) interface{}) interface{} {
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return fallback(b)
+	}
+	return v
+}
+
+func encodePylibmc(v interface{}) ([]byte, uint32, error) {
+	switch t := v.(type) {
+	case []byte:
+		return append([]byte(nil), t...), pylibmcBytes, nil
+	case string:
+		return []byte(t), pylibmcText, nil
+	case bool:
+		if t {
+			return []byte("1"), pylibmcBool, nil
+		}
+		return []byte("0"), pylibmcBool, nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return []byte(fmt.Sprint(t)), pylibmcInt, nil
+	}
+	b, err := json.Marshal(v)
+	return b, pylibmcBytes, err
+}
+
+func decodePylibmc(b []byte, flags uint32) (interface{}, error) {
+	if flags&pylibmcZlib == pylibmcZlib {
+		r, err := zlib.NewReader(bytes.NewReader(b))
+		if err != nil {
+			return nil, err
+		}
+		if b, err = ioutil.ReadAll(r); err != nil {
+			return nil, err
+		}
+		flags &^= pylibmcZlib
+	}
+	switch flags {
+	case pylibmcBytes:
+		return decodeJSON(b, func(b []byte) interface{} { return b }), nil
+	case pylibmcText:
+		return string(b), nil
+	case pylibmcInt, pylibmcLong:
+		return strconv.ParseInt(string(b), 10, 64)
+	case pylibmcBool:
+		return string(b) == "1", nil
+	}
+	// pickle and unknown types cannot be read from Go
+	return nil, errInteropFlags
+}
+
+func encodeSpymemcached(v interface{}) ([]byte, uint32, error) {
+	switch t := v.(type) {
+	case []byte:
+		return append([]byte(nil), t...), spyByteArray, nil
+	case string:
+		return []byte(t), 0, nil
+	case bool:
+		if t {
+			return []byte("1"), spyBoolean, nil
+		}
+		return []byte("0"), spyBoolean, nil
+	case int8:
+		return []byte{byte(t)}, spyByte, nil
+	case int32:
+		return putUint32(uint32(t)), spyInt, nil
+	case int:
+		return putUint64(uint64(t)), spyLong, nil
+	case int64:
+		return putUint64(uint64(t)), spyLong, nil
+	case float32:
+		return putUint32(math.Float32bits(t)), spyFloat, nil
+	case float64:
+		return putUint64(math.Float64bits(t)), spyDouble, nil
+	case time.Time:
+		return putUint64(uint64(t.UnixNano() / int64(time.Millisecond))), spyDate, nil
+	}
+	b, err := json.Marshal(v)
+	return b, 0, err
+}
+
+func decodeSpymemcached(b []byte, flags uint32) (interface{}, error) {
+	if flags&spyCompressed == spyCompressed || flags&spySerialized == spySerialized {
+		// gzip compressed and java serialized objects cannot be read from Go
+		return nil, errInteropFlags
+	}
+	switch flags & spyTypes {
+	case 0:
+		return decodeJSON(b, func(b []byte) interface{} { return string(b) }), nil
+	case spyByteArray:
+		return b, nil
+	case spyBoolean:
+		return string(b) == "1", nil
+	case spyByte:
+		if len(b) != 1 {
+			return nil, errInteropFlags
+		}
+		return int8(b[0]), nil
+	case spyInt:
+		if len(b) > 4 {
+			return nil, errInteropFlags
+		}
+		return int32(getUint64(b)), nil
+	case spyLong:
+		if len(b) > 8 {
+			return nil, errInteropFlags
+		}
+		return int64(getUint64(b)), nil
+	case spyFloat:
+		if len(b) != 4 {
+			return nil, errInteropFlags
+		}
+		return math.Float32frombits(binary.BigEndian.Uint32(b)), nil
+	case spyDouble:
+		if len(b) != 8 {
+			return nil, errInteropFlags
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(b)), nil
+	case spyDate:
+		if len(b) > 8 {
+			return nil, errInteropFlags
+		}
+		ms := int64(getUint64(b))
+		return time.Unix(0, ms*int64(time.Millisecond)), nil
+	}
+	return nil, errInteropFlags
+}
+
+func putUint32(v uint32) []byte {
+	b := make([]byte, 4)
+	binary.BigEndian.PutUint32(b, v)
+	return b
+}
+
+func putUint64(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
+
+// getUint64 function decodes the big-endian integer, spymemcached drops the
+// leading zero bytes of the encoded numbers.
+func getUint64(b []byte) uint64 {
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
@@ -0,0 +1,85 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"compress/zlib"
+	"testing"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheInteropJSON(t *testing.T) {
+	m := &memcacheCache{interop: interopJSON}
+
+	item, err := m.encodeInterop("k", &entry{D: 60, V: map[string]string{"key": "k1"}, flags: flagSlide | 1<<16})
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1<<16), item.Flags)
+	assert.Equal(t, int32(60), item.Expiration)
+	assert.Equal(t, `{"key":"k1"}`, string(item.Value))
+
+	e, err := m.decodeInterop(&memcache.Item{Value: []byte(`{"a":1}`)})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": float64(1)}, e.V)
+
+	e, err = m.decodeInterop(&memcache.Item{Value: []byte("plain text")})
+	assert.Nil(t, err)
+	assert.Equal(t, "plain text", e.V)
+
+	_, err = m.decodeInterop(&memcache.Item{Value: []byte("x"), Flags: 1})
+	assert.Equal(t, errInteropFlags, err)
+}
+
+func TestMemcacheInteropPylibmc(t *testing.T) {
+	m := &memcacheCache{interop: interopPylibmc}
+
+	for _, v := range []interface{}{"text", []byte("bytes"), true, false, int64(42)} {
+		item, err := m.encodeInterop("k", &entry{V: v})
+		assert.Nil(t, err)
+		e, err := m.decodeInterop(item)
+		assert.Nil(t, err)
+		assert.Equal(t, v, e.V)
+	}
+
+	item, _ := m.encodeInterop("k", &entry{V: 42})
+	assert.Equal(t, pylibmcInt, item.Flags)
+	assert.Equal(t, "42", string(item.Value))
+
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write([]byte("compressed"))
+	_ = w.Close()
+	v, err := decodePylibmc(buf.Bytes(), pylibmcText|pylibmcZlib)
+	assert.Nil(t, err)
+	assert.Equal(t, "compressed", v)
+
+	_, err = decodePylibmc([]byte("pickle"), 1)
+	assert.Equal(t, errInteropFlags, err)
+}
+
+func TestMemcacheInteropSpymemcached(t *testing.T) {
+	m := &memcacheCache{interop: interopSpymemcached}
+
+	now := time.Unix(1500000000, 0)
+	for _, v := range []interface{}{"text", []byte("bytes"), true, int8(-3),
+		int32(-7), int64(1 << 40), float32(1.5), float64(2.25), now} {
+		item, err := m.encodeInterop("k", &entry{V: v})
+		assert.Nil(t, err)
+		e, err := m.decodeInterop(item)
+		assert.Nil(t, err)
+		assert.Equal(t, v, e.V)
+	}
+
+	// leading zero bytes are dropped by spymemcached
+	v, err := decodeSpymemcached([]byte{0x01, 0x00}, spyInt)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(256), v)
+
+	_, err = decodeSpymemcached([]byte("java object"), spySerialized)
+	assert.Equal(t, errInteropFlags, err)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Item struct is the memcache item of the cache key as stored, without the
+// provider codec. Value bytes and flags are owned by the app; flags below
+// `FlagUserMask` are interpreted by the provider `Get`, so items written with
+// `SetItem` are meant to be read with `GetItem`.
+type Item struct {
+	Key   string
+	Value []byte
+	Flags uint32
+
+	// TTL is the remaining time to live of the item, `NoExpiration` means
+	// it does not expire. Get reports it only with `meta_commands` enabled,
+	// zero value means unknown.
+	TTL time.Duration
+
+	// CAS is the compare-and-swap token of the item read by `GetItem`,
+	// `SetItem` of the item with token fails with `memcache.ErrCASConflict`
+	// if the item was modified meanwhile.
+	CAS CASToken
+}
+
+// CASToken type is the opaque compare-and-swap token of the memcache item,
+// zero value means no token.
+type CASToken struct {
+	item *memcache.Item
+}
+
+// IsZero method reports whether the token is absent.
+func (t CASToken) IsZero() bool {
+	return t.item == nil
+}
+
+// GetItem method returns the memcache item of given cache key as stored,
+// error of class `ErrMiss` is returned if it does not exist.
+func (m *memcacheCache) GetItem(k string) (*Item, error) {
+	o := m.begin("get", k)
+	mk := m.key(k)
+	v, err := m.getItem(mk)
+	if err == memcache.ErrCacheMiss {
+		o.miss()
+		o.end(nil)
+		return nil, m.opError("get", k, mk, nil, err)
+	}
+	if err != nil {
+		o.end(err)
+		return nil, m.opError("get", k, mk, nil, err)
+	}
+	o.hit(len(v.Value))
+	o.end(nil)
+
+	item := &Item{Key: k, Value: v.Value, Flags: v.Flags, CAS: CASToken{item: v}}
+	if m.p.metaCommands {
+		if line, err := m.client().metaGet(mk, "t"); err == nil {
+			item.TTL = parseMetaTTL(line)
+		}
+	}
+	return item, nil
+}
+
+// SetItem method stores the memcache item of the cache key as is, the item
+// with CAS token is stored via compare-and-swap. TTL of zero or
+// `NoExpiration` stores the item without expiration.
+func (m *memcacheCache) SetItem(item *Item) error {
+	o := m.begin("put", item.Key)
+	mk := m.key(item.Key)
+	var exp int32
+	if item.TTL > 0 {
+		exp = expirationOf(int32(item.TTL / time.Second))
+	}
+	o.written(len(item.Value))
+	var err error
+	if item.CAS.IsZero() {
+		err = m.client().Set(&memcache.Item{Key: mk, Value: item.Value, Flags: item.Flags, Expiration: exp})
+	} else {
+		ci := *item.CAS.item
+		ci.Key, ci.Value, ci.Flags, ci.Expiration = mk, item.Value, item.Flags, exp
+		err = m.client().CompareAndSwap(&ci)
+	}
+	o.end(err)
+	if err != nil {
+		return m.opError("put", item.Key, mk, nil, err)
+	}
+	m.counters.written(len(mk), len(item.Value))
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// parseMetaTTL function parses the TTL flag of meta-get response e.g.
+// "HD t59", -1 means no expiration.
+func parseMetaTTL(line string) time.Duration {
+	for _, f := range strings.Fields(line)[1:] {
+		if !strings.HasPrefix(f, "t") {
+			continue
+		}
+		n, err := strconv.ParseInt(f[1:], 10, 64)
+		if err != nil {
+			return 0
+		}
+		if n < 0 {
+			return NoExpiration
+		}
+		return time.Duration(n) * time.Second
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheRawItem(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "item", ProviderName: "memcache1"}).(Cache)
+
+	assert.Nil(t, c.SetItem(&Item{Key: "key1", Value: []byte("own encoding"), Flags: 1 << 20, TTL: time.Minute}))
+	item, err := c.GetItem("key1")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("own encoding"), item.Value)
+	assert.Equal(t, uint32(1<<20), item.Flags)
+	assert.False(t, item.CAS.IsZero())
+
+	// CAS conflict after concurrent modification
+	other, _ := c.GetItem("key1")
+	other.Value = []byte("modified")
+	assert.Nil(t, c.SetItem(other))
+	item.Value = []byte("stale")
+	assert.True(t, errors.Is(c.SetItem(item), memcache.ErrCASConflict))
+
+	_, err = c.GetItem("absent")
+	assert.True(t, errors.Is(err, ErrMiss))
+
+	assert.Nil(t, c.Delete("key1"))
+}
+
+func TestMemcacheParseMetaTTL(t *testing.T) {
+	assert.Equal(t, 59*time.Second, parseMetaTTL("HD t59"))
+	assert.Equal(t, NoExpiration, parseMetaTTL("HD t-1"))
+	assert.Equal(t, time.Duration(0), parseMetaTTL("HD f1"))
+}
@@ -0,0 +1,88 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"time"
+)
+
+// leaseKeyPrefix is prepended to the lease key, so that leases do not
+// collide with the cache entries and locks.
+const leaseKeyPrefix = "~lease:"
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// leasePolicy struct holds the lease settings of the cache. With lease
+// enabled, `Fetch` miss or early recompute hands the lease to one caller
+// across the app instances, only the lease holder calls the loader func.
+// Other callers return the stale value if present, otherwise they wait for
+// the lease holder to store the value. If the value does not show up within
+// `lease.wait`, caller calls the loader func itself.
+//
+//	cache {
+//	  mycache {
+//	    lease {
+//	      # default value is false
+//	      enable = true
+//
+//	      # lease expires after ttl, in case holder dies; default value is 10s
+//	      ttl = "10s"
+//
+//	      # default value is 1s
+//	      wait = "1s"
+//
+//	      # default value is 50ms
+//	      retry_interval = "50ms"
+//	    }
+//	  }
+//	}
+type leasePolicy struct {
+	enable   bool
+	ttl      time.Duration
+	wait     time.Duration
+	interval time.Duration
+}
+
+func newLeasePolicy(m *memcacheCache) leasePolicy {
+	return leasePolicy{
+		enable:   m.settingBool("lease.enable", false),
+		ttl:      parseDuration(m.settingString("lease.ttl", ""), "10s"),
+		wait:     parseDuration(m.settingString("lease.wait", ""), "1s"),
+		interval: parseDuration(m.settingString("lease.retry_interval", ""), "50ms"),
+	}
+}
+
+// leased method calls given load func if the caller obtains the lease of
+// given key, stale entry is returned otherwise.
+func (m *memcacheCache) leased(k string, stale *entry, load LoaderFunc) (interface{}, error) {
+	l, err := m.tryLock(leaseKeyPrefix, k, m.lease.ttl)
+	if err == nil {
+		defer func() { _ = l.Unlock() }()
+		return load()
+	}
+	if !errors.Is(err, ErrLocked) {
+		// lease is an optimization, server error must not fail the fetch
+		m.p.logError(err)
+		return load()
+	}
+	if stale != nil {
+		return stale.V, nil
+	}
+
+	deadline := time.Now().Add(m.lease.wait)
+	for time.Now().Add(m.lease.interval).Before(deadline) {
+		time.Sleep(m.lease.interval)
+		if e, found := m.getEntry(k); found {
+			if e.notFound {
+				return nil, ErrNotFound
+			}
+			return e.V, nil
+		}
+	}
+	return load()
+}
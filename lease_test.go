@@ -0,0 +1,73 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheFetchLease(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		lease {
+			lease {
+				enable = true
+				wait = "300ms"
+				retry_interval = "20ms"
+			}
+		}
+	}
+`, &cache.Config{Name: "lease", ProviderName: "memcache1"})
+	m := c.(*memcacheCache)
+	assert.True(t, m.lease.enable)
+
+	// lease held by another instance, holder stores the value meanwhile
+	l, err := m.tryLock(leaseKeyPrefix, "key1", time.Second)
+	assert.Nil(t, err)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_ = m.Put("key1", "holder", 3*time.Second)
+		_ = l.Unlock()
+	}()
+	calls := 0
+	v, err := m.Fetch("key1", 3*time.Second, func() (interface{}, error) {
+		calls++
+		return "waiter", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "holder", v)
+	assert.Equal(t, 0, calls)
+
+	// holder never stores, waiter loads after lease wait
+	l, err = m.tryLock(leaseKeyPrefix, "key2", time.Second)
+	assert.Nil(t, err)
+	v, err = m.Fetch("key2", 3*time.Second, func() (interface{}, error) {
+		calls++
+		return "waiter", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "waiter", v)
+	assert.Equal(t, 1, calls)
+	assert.Nil(t, l.Unlock())
+
+	// lease holder releases the lease after load
+	v, err = m.Fetch("key3", 3*time.Second, func() (interface{}, error) {
+		return "loaded", nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, "loaded", v)
+	_, err = m.tryLock(leaseKeyPrefix, "key3", time.Second)
+	assert.Nil(t, err)
+
+	_ = m.DeleteMulti([]string{"key1", "key2", "key3"})
+}
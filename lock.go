@@ -0,0 +1,156 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// lockKeyPrefix is prepended to the lock key, so that locks do not collide
+// with the cache entries.
+const lockKeyPrefix = "~lock:"
+
+var (
+	// ErrLocked error is returned by `Lock` and `WithLock` when the lock is
+	// held by another owner.
+	ErrLocked = errors.New("aah/cache: lock is held by another owner")
+
+	// ErrLockNotHeld error is returned by `Unlock` when the lock expired or
+	// acquired by another owner meanwhile.
+	ErrLockNotHeld = errors.New("aah/cache: lock is not held")
+)
+
+// Unlocker interface releases the lock acquired via `Lock`.
+type Unlocker interface {
+	// Unlock method releases the lock, it returns `ErrLockNotHeld` if the
+	// lock is no longer owned by the caller.
+	Unlock() error
+}
+
+// Lock method acquires the cross-instance lock of given key for the given
+// TTL, lock is released automatically by memcache after the TTL. Lock is
+// stored via `Add` with unique owner token, `Unlock` releases it only if
+// the token matches, using CAS.
+//
+// If the lock is held, it is retried every `lock.retry_interval` until
+// `lock.wait` elapses, then `ErrLocked` is returned.
+//
+//	cache {
+//	  mycache {
+//	    lock {
+//	      # default value is 0s, no wait
+//	      wait = "2s"
+//
+//	      # default value is 50ms
+//	      retry_interval = "50ms"
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) Lock(k string, ttl time.Duration) (Unlocker, error) {
+	wait := parseDuration(m.settingString("lock.wait", ""), "0s")
+	interval := parseDuration(m.settingString("lock.retry_interval", ""), "50ms")
+	deadline := time.Now().Add(wait)
+	for {
+		l, err := m.tryLock(lockKeyPrefix, k, ttl)
+		if err == nil {
+			return l, nil
+		}
+		if !errors.Is(err, ErrLocked) || !time.Now().Add(interval).Before(deadline) {
+			return nil, err
+		}
+		time.Sleep(interval)
+	}
+}
+
+// WithLock method calls given func while holding the lock of given key. It
+// returns the error of `Lock`, func or `Unlock` in that order.
+func (m *memcacheCache) WithLock(k string, ttl time.Duration, fn func() error) error {
+	l, err := m.Lock(k, ttl)
+	if err != nil {
+		return err
+	}
+	ferr := fn()
+	uerr := l.Unlock()
+	if ferr != nil {
+		return ferr
+	}
+	return uerr
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type lock struct {
+	m     *memcacheCache
+	k     string
+	mk    string
+	token []byte
+}
+
+// tryLock method acquires the lock item of given key prefix once, it returns
+// `ErrLocked` if the lock item exists.
+func (m *memcacheCache) tryLock(prefix, k string, ttl time.Duration) (*lock, error) {
+	token, err := lockToken()
+	if err != nil {
+		return nil, m.opError("lock", k, "", nil, err)
+	}
+	l := &lock{m: m, k: k, mk: m.key(prefix + k), token: token}
+	exp := int32(ttl.Seconds())
+	if exp < 1 {
+		exp = 1
+	}
+	o := m.begin("lock", k)
+	err = m.client().Add(&memcache.Item{Key: l.mk, Value: token, Expiration: expirationOf(exp)})
+	o.end(notStored(err))
+	if err == memcache.ErrNotStored {
+		err = ErrLocked
+	}
+	if err != nil {
+		return nil, m.opError("lock", k, l.mk, nil, err)
+	}
+	return l, nil
+}
+
+// Unlock method expires the lock item via CAS, so lock re-acquired by
+// another owner after TTL is never released.
+func (l *lock) Unlock() error {
+	o := l.m.begin("unlock", l.k)
+	item, err := l.m.client().Get(l.mk)
+	if err == nil && !bytes.Equal(item.Value, l.token) {
+		err = ErrLockNotHeld
+	}
+	if err == nil {
+		// negative expiration expires the item immediately
+		item.Expiration = -1
+		err = l.m.client().CompareAndSwap(item)
+	}
+	switch err {
+	case memcache.ErrCacheMiss, memcache.ErrCASConflict, memcache.ErrNotStored:
+		err = ErrLockNotHeld
+	}
+	o.end(err)
+	if err != nil {
+		return l.m.opError("unlock", l.k, l.mk, nil, err)
+	}
+	return nil
+}
+
+// lockToken function returns random owner token of the lock.
+func lockToken() ([]byte, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+	token := make([]byte, hex.EncodedLen(len(b)))
+	hex.Encode(token, b)
+	return token, nil
+}
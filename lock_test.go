@@ -0,0 +1,57 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheLock(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "lock", ProviderName: "memcache1"}).(Cache)
+
+	l, err := c.Lock("rebuild", 5*time.Second)
+	assert.Nil(t, err)
+
+	_, err = c.Lock("rebuild", 5*time.Second)
+	assert.True(t, errors.Is(err, ErrLocked))
+	assert.True(t, errors.Is(c.WithLock("rebuild", time.Second, func() error { return nil }), ErrLocked))
+
+	// lock key does not collide with the cache entry
+	assert.False(t, c.Exists("rebuild"))
+
+	assert.Nil(t, l.Unlock())
+	assert.True(t, errors.Is(l.Unlock(), ErrLockNotHeld))
+
+	called := false
+	assert.Nil(t, c.WithLock("rebuild", 5*time.Second, func() error {
+		called = true
+		return nil
+	}))
+	assert.True(t, called)
+
+	ferr := errors.New("job failed")
+	assert.Equal(t, ferr, c.WithLock("rebuild", 5*time.Second, func() error { return ferr }))
+}
+
+func TestMemcacheLockToken(t *testing.T) {
+	t1, err := lockToken()
+	assert.Nil(t, err)
+	t2, err := lockToken()
+	assert.Nil(t, err)
+	assert.Len(t, t1, 32)
+	assert.NotEqual(t, t1, t2)
+}
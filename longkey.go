@@ -0,0 +1,58 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+)
+
+// maxKeyLength is the memcache limit of the key length in bytes.
+const maxKeyLength = 250
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// fitKey method returns the memcache key of given key and reports whether it
+// is hashed. Effective key (prefix included) longer than 250 bytes is
+// replaced with its SHA-256 digest in URL safe base64, key prefix of the
+// cache is retained.
+//
+// When the cache has `key_echo` enabled, original key of the hashed key is
+// stored in the entry and verified on read, so that the mismatch is logged
+// and treated as cache miss.
+//
+//	cache {
+//	  mycache {
+//	    # default value is false
+//	    key_echo = true
+//	  }
+//	}
+func (m *memcacheCache) fitKey(k string) (string, bool) {
+	k = m.tenant + k
+	var mk string
+	if m.gens != nil {
+		mk = m.gens.key(k)
+	} else {
+		mk = m.keyPrefix + k
+	}
+	if len(mk) <= maxKeyLength {
+		return mk, false
+	}
+	sum := sha256.Sum256([]byte(mk))
+	return m.keyPrefix + "#" + base64.RawURLEncoding.EncodeToString(sum[:]), true
+}
+
+// echoMismatch method reports whether the entry read for given key carries
+// different original key.
+func (m *memcacheCache) echoMismatch(k string, e *entry) bool {
+	if e.K == "" || e.K == k {
+		return false
+	}
+	m.p.logger.Warnf("aah/cache/%s: key(%s) hashed key collides with key(%s), treated as cache miss",
+		m.Name(), m.logKey(k), m.logKey(e.K))
+	return true
+}
@@ -0,0 +1,67 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheFitKey(t *testing.T) {
+	m := &memcacheCache{cfg: &cache.Config{Name: "longkey"}, keyPrefix: "longkey-"}
+
+	mk, hashed := m.fitKey("key1")
+	assert.Equal(t, "longkey-key1", mk)
+	assert.False(t, hashed)
+
+	k := strings.Repeat("k", maxKeyLength-len(m.keyPrefix))
+	mk, hashed = m.fitKey(k)
+	assert.Equal(t, "longkey-"+k, mk)
+	assert.False(t, hashed)
+
+	mk, hashed = m.fitKey(k + "k")
+	assert.True(t, hashed)
+	assert.True(t, strings.HasPrefix(mk, "longkey-#"))
+	assert.Equal(t, 52, len(mk))
+	assert.Equal(t, mk, m.key(k+"k"))
+}
+
+func TestMemcacheKeyEchoMismatch(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(ioutil.Discard)
+	m := &memcacheCache{cfg: &cache.Config{Name: "longkey"}, p: &Provider{logger: l}}
+
+	assert.False(t, m.echoMismatch("key1", &entry{}))
+	assert.False(t, m.echoMismatch("key1", &entry{K: "key1"}))
+	assert.True(t, m.echoMismatch("key1", &entry{K: "key2"}))
+}
+
+func TestMemcacheLongKey(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		longkey {
+			key_echo = true
+		}
+	}
+`, &cache.Config{Name: "longkey", ProviderName: "memcache1"})
+
+	k := strings.Repeat("user:1234:", 30)
+	assert.Nil(t, c.Put(k, "value1", 3*time.Second))
+	assert.Equal(t, "value1", c.Get(k))
+	assert.True(t, c.Exists(k))
+	assert.Nil(t, c.Delete(k))
+	assert.Nil(t, c.Get(k))
+}
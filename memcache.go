@@ -5,11 +5,15 @@
 package memcache // import "aahframe.work/cache/provider/memcache"
 
 import (
-	"bytes"
+	"context"
 	"encoding/gob"
+	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"aahframe.work/cache"
@@ -24,11 +28,45 @@ import (
 
 // Provider struct represents the Redis cache provider.
 type Provider struct {
-	name   string
-	logger log.Loggerer
-	cfg    *cache.Config
-	appCfg *config.Config
-	client *memcache.Client
+	name    string
+	logger  log.Loggerer
+	appCfg  atomic.Value // *config.Config
+	client  atomic.Value // *memcache.Client
+	servers *memcache.ServerList
+	tracer  atomic.Value
+	statsd  *statsdSink
+	hooks   hookList
+	errlog  *errorLimiter
+	ns      string
+	schemas schemaRegistry
+
+	metaCommands bool
+	metaExists   int32 // 1 if Exists uses meta-get
+	metaMu       sync.Mutex
+	metaIdle     map[string][]*adminConn
+
+	middlewares []Middleware
+
+	cachesMu sync.RWMutex
+	caches   map[string]*memcacheCache
+	warmups  map[string][]warmupEntry
+
+	secondary *Provider
+	repairer  *repairer
+
+	reloadMu  sync.Mutex
+	addrMu    sync.RWMutex
+	addresses []string
+	retry     atomic.Value // *RetryPolicy
+	events    atomic.Value // *EventPublisher
+	states    serverStates
+	dryRun    *dryRunClient
+	fault     atomic.Value // *faultPolicy
+	chaos     *chaosLatency
+	inflight  *inflightLimiter
+	health    *health
+	ejector   *ejector
+	deadlines deadlineClients
 }
 
 var _ cache.Provider = (*Provider)(nil)
@@ -36,157 +74,977 @@ var _ cache.Provider = (*Provider)(nil)
 // Init method initializes the Redis cache provider.
 func (p *Provider) Init(providerName string, appCfg *config.Config, logger log.Loggerer) error {
 	p.name = providerName
-	p.appCfg = appCfg
+	p.appCfg.Store(appCfg)
 	p.logger = logger.WithField("cache_provider", providerName)
 
 	cfgPrefix := "cache." + p.name + "."
-	if strings.ToLower(p.appCfg.StringDefault(cfgPrefix+"provider", "")) != "memcache" {
+	if strings.ToLower(p.config().StringDefault(cfgPrefix+"provider", "")) != "memcache" {
 		return fmt.Errorf("aah/cache: not a vaild provider name, expected 'memcache'")
 	}
 
-	addresses, found := p.appCfg.StringList(cfgPrefix + "addresses")
+	addresses, found := p.config().StringList(cfgPrefix + "addresses")
 	if !found {
 		addresses = []string{"0.0.0.0:11211"}
 	}
 
-	p.client = memcache.New(addresses...)
-	p.client.MaxIdleConns = p.appCfg.IntDefault(cfgPrefix+"max_idle_conns", memcache.DefaultMaxIdleConns)
-	p.client.Timeout = parseDuration(p.appCfg.StringDefault(cfgPrefix+"timeout", "5s"), "5s")
+	p.setAddresses(addresses)
+	p.servers = new(memcache.ServerList)
+	if p.dryRun = newDryRunClient(p); p.dryRun == nil {
+		// same as memcache.New, invalid address leaves the server list empty
+		// and the connection check reports no servers
+		if err := p.servers.SetServers(addresses...); err != nil {
+			p.logger.Errorf("aah/cache/%s: %s", p.name, err)
+		}
+	}
+	p.client.Store(p.newClient(
+		parseDuration(p.config().StringDefault(cfgPrefix+"timeout", "5s"), "5s"),
+		p.config().IntDefault(cfgPrefix+"max_idle_conns", memcache.DefaultMaxIdleConns)))
+
+	var err error
+	if p.statsd, err = newStatsdSink(p); err != nil {
+		return err
+	}
+	p.errlog = newErrorLimiter(p)
+	p.inflight = newInflightLimiter(p)
+	p.health = newHealth(p)
+	p.ejector = newEjector(p)
+	if p.chaos, err = newChaosLatency(p); err != nil {
+		return err
+	}
+	p.ns = p.namespace()
+	p.metaCommands = p.config().BoolDefault(cfgPrefix+"meta_commands", false)
+	if p.config().BoolDefault(cfgPrefix+"meta_exists", true) {
+		p.metaExists = 1
+	}
+	p.configureFault()
 
 	gob.Register(entry{})
 
+	if p.dryRun != nil {
+		p.metaCommands = false
+		p.metaExists = 0
+		p.logger.Warnf("aah/cache/provider: %s is in dry-run mode, no memcache calls are made", p.name)
+		return nil
+	}
+
 	// Check server connection
-	if _, err := p.client.Get(p.name + "-testkey"); err != nil && err != memcache.ErrCacheMiss {
+	if _, err := p.Client().Get(p.name + "-testkey"); err != nil && err != memcache.ErrCacheMiss {
 		return fmt.Errorf("aah/cache/%s: %s", p.name, err)
 	}
 
 	p.logger.Infof("aah/cache/provider: %s connected successfully with %s", p.name, strings.Join(addresses, ", "))
+	p.publish(EventOnProviderConnected, ServerEvent{Servers: addresses})
 
 	return nil
 }
 
 // Create method creates new Redis cache with given options.
 func (p *Provider) Create(cfg *cache.Config) (cache.Cache, error) {
-	p.cfg = cfg
+	m, err := p.newCache(cfg)
+	if err != nil {
+		return nil, err
+	}
+	p.addCache(m)
+	m.warmup()
+	return m.applyMiddlewares()
+}
+
+// Close method closes the caches created by the provider, see
+// `Cache.Close`, and stops probing the ejected servers.
+func (p *Provider) Close() error {
+	p.ejector.close()
+	p.cachesMu.RLock()
+	defer p.cachesMu.RUnlock()
+	for _, m := range p.caches {
+		_ = m.Close()
+	}
+	return nil
+}
+
+// newCache method returns the memcache cache of given config configured from
+// the cache settings.
+func (p *Provider) newCache(cfg *cache.Config) (*memcacheCache, error) {
 	m := &memcacheCache{
-		keyPrefix: p.cfg.Name + "-",
-		p:         p,
+		cfg:      cfg,
+		p:        p,
+		counters: new(counters),
+		vars:     newCacheVars(p.name, cfg.Name),
+	}
+	m.keyPrefix = m.prefix()
+	m.softTTL = parseDuration(m.settingString("soft_ttl", ""), "0s")
+	m.negativeTTL = parseDuration(m.settingString("negative_ttl", ""), "30s")
+	m.slowThreshold = parseDuration(m.settingString("slow_op_threshold", ""), "0s")
+	m.keyEcho = m.settingBool("key_echo", false)
+	m.rawValues = m.settingBool("raw_values", false)
+	m.strictTypes = m.settingBool("strict_types", false)
+	m.deleteCorrupt = m.settingBool("corrupt_entry.delete", false)
+	m.readOnly = m.settingBool("read_only", false)
+	m.writeOnly = m.settingBool("write_only", false)
+	if m.readOnly && m.writeOnly {
+		return nil, fmt.Errorf("aah/cache/%s: read_only and write_only are mutually exclusive", cfg.Name)
+	}
+	var err error
+	if m.redactor, err = newKeyRedactor(m); err != nil {
+		return nil, err
+	}
+	if m.ttl, err = newTTLPolicy(m); err != nil {
+		return nil, err
+	}
+	if m.size, err = newSizeLimit(m); err != nil {
+		return nil, err
+	}
+	if m.interop, err = parseInterop(m); err != nil {
+		return nil, err
+	}
+	if m.fastCodec, err = parseCodec(m); err != nil {
+		return nil, err
+	}
+	m.lease = newLeasePolicy(m)
+	m.deadline = newDeadlinePolicy(m)
+	m.labels = newProfileLabels(m)
+	m.lowPriority = m.settingString("priority", "normal") == "low"
+	m.chunkLimit = defaultChunkSize
+	m.deleteBatch = m.settingInt("bulk_delete.batch_size", defaultDeleteBatchSize)
+	m.deleteConcurrency = m.settingInt("bulk_delete.concurrency", defaultDeleteConcurrency)
+	if v := m.settingString("chunk_size", ""); v != "" {
+		if m.chunkLimit, err = parseSize(v); err != nil || m.chunkLimit < 1 {
+			return nil, fmt.Errorf("aah/cache/%s: invalid chunk_size '%s'", cfg.Name, v)
+		}
+	}
+	switch level := m.settingString("miss_log", "none"); level {
+	case "none":
+	case "debug":
+		m.logMisses = true
+	default:
+		return nil, fmt.Errorf("aah/cache/%s: unsupported miss_log '%s'", cfg.Name, level)
+	}
+	if m.settingBool("prefix_invalidation.enable", false) {
+		m.gens = newGenerations(m)
+	}
+	if m.settingBool("key_tracking.enable", false) {
+		m.registry = newKeyRegistry(m.settingInt("key_tracking.max_keys", 100000))
+	}
+	if m.settingBool("hot_keys.enable", false) {
+		m.hot = newHotKeys(m)
+	}
+	if m.settingBool("write_behind.enable", false) {
+		m.wq = newWriteQueue(m)
+	}
+	if m.settingBool("slide_touch.async", true) {
+		m.toucher = newToucher(m)
 	}
 	return m, nil
 }
 
 // Client method returns underlying memcache client. So that aah user could perform
-// cache provider specific features.
+// cache provider specific features. Client is replaced when the timeout or
+// pool size is reloaded, do not hold on to it.
 func (p *Provider) Client() *memcache.Client {
-	return p.client
+	c, _ := p.client.Load().(*memcache.Client)
+	return c
+}
+
+func (p *Provider) addCache(m *memcacheCache) {
+	p.cachesMu.Lock()
+	defer p.cachesMu.Unlock()
+	if p.caches == nil {
+		p.caches = make(map[string]*memcacheCache)
+	}
+	p.caches[m.Name()] = m
+}
+
+// cache method returns the cache of given name created by the provider.
+func (p *Provider) cache(name string) (*memcacheCache, bool) {
+	p.cachesMu.RLock()
+	defer p.cachesMu.RUnlock()
+	m, found := p.caches[name]
+	return m, found
+}
+
+// cacheNames method returns the sorted names of the caches created by the
+// provider.
+func (p *Provider) cacheNames() []string {
+	p.cachesMu.RLock()
+	defer p.cachesMu.RUnlock()
+	names := make([]string, 0, len(p.caches))
+	for name := range p.caches {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Cache interface
+//______________________________________________________________________________
+
+// Cache interface extends `cache.Cache` with memcache provider specific
+// features. Cache created by the memcache provider implements it, so aah
+// user could obtain it via type assertion.
+//
+//	mc := aah.App().CacheManager().Cache("mycache").(memcache.Cache)
+type Cache interface {
+	cache.Cache
+
+	// Fetch method returns the cached value for given key, on miss it calls
+	// given loader func and puts the result into cache store. Hot keys are
+	// recomputed probabilistically ahead of their expiry.
+	Fetch(k string, d time.Duration, fn LoaderFunc) (interface{}, error)
+
+	// SetLoader method registers the loader func of the cache, it is used to
+	// refresh the entries in the background.
+	SetLoader(fn KeyLoaderFunc)
+
+	// RefreshAhead method registers the key or key pattern with loader func,
+	// matching entries are refreshed periodically before they expire.
+	RefreshAhead(pattern string, interval, d time.Duration, fn KeyLoaderFunc) error
+
+	// CancelRefreshAhead method stops refreshing the key or key pattern.
+	CancelRefreshAhead(pattern string)
+
+	// Close method stops the background refresh ahead workers and the hot key
+	// reporter of the cache.
+	Close() error
+
+	// GetMulti method returns the cached entries for given keys, keys not
+	// found in the cache store are loaded via registered batch loader.
+	GetMulti(keys []string) map[string]interface{}
+
+	// SetBatchLoader method registers the batch loader func for `GetMulti`
+	// misses, loaded entries are stored with given expiration.
+	SetBatchLoader(d time.Duration, fn BatchLoaderFunc)
+
+	// PutNotFound method caches the "not found" result for given key with
+	// the cache negative TTL.
+	PutNotFound(k string) error
+
+	// PutAsync method adds the cache entry without blocking the caller, given
+	// func is called with the result of the write.
+	PutAsync(k string, v interface{}, d time.Duration, fn func(error))
+
+	// InvalidatePrefix method invalidates all the cache entries whose key
+	// starts with given prefix.
+	InvalidatePrefix(prefix string) error
+
+	// Keys method returns the keys written by this cache instance matching
+	// given pattern, it requires `key_tracking` enabled.
+	Keys(pattern string) ([]string, error)
+
+	// DeleteMulti method deletes the cache entries of given keys.
+	DeleteMulti(keys []string) error
+
+	// PurgeSubject method deletes all the entries tagged with given subject
+	// identifier via `WithSubject`.
+	PurgeSubject(subjectID string) error
+
+	// DeleteByPattern method deletes the tracked keys matching given
+	// pattern, it requires `key_tracking` enabled.
+	DeleteByPattern(pattern string) (int, error)
+
+	// Metrics method returns the approximate footprint of the cache written
+	// by this app instance.
+	Metrics() Metrics
+
+	// Stats method returns the snapshot of the cache hit/miss/error counters.
+	Stats() Stats
+
+	// HotKeys method returns the top-N hottest keys of last completed
+	// reporting interval, it requires `hot_keys` enabled.
+	HotKeys() []HotKey
+
+	// AddHooks method registers the operation hooks for this cache.
+	AddHooks(h Hooks)
+
+	// Sync method blocks until all the queued asynchronous writes are
+	// written to memcache.
+	Sync()
+
+	// PutWith method adds the cache entry with specified expiration and
+	// per-entry options.
+	PutWith(k string, v interface{}, d time.Duration, opts ...PutOption) error
+
+	// PutReader method stores the bytes of given reader as the cache entry,
+	// bytes are streamed in chunks.
+	PutReader(k string, r io.Reader, d time.Duration) error
+
+	// GetReader method returns the reader of the cache entry written by
+	// `PutReader`.
+	GetReader(k string) (io.ReadCloser, error)
+
+	// Lock method acquires the cross-instance lock of given key for given
+	// TTL.
+	Lock(k string, ttl time.Duration) (Unlocker, error)
+
+	// WithLock method calls given func while holding the lock of given key.
+	WithLock(k string, ttl time.Duration, fn func() error) error
+
+	// RateLimiter method returns the rate limiter of given name backed by
+	// memcache counters.
+	RateLimiter(name string) *RateLimiter
+
+	// Semaphore method returns the semaphore of given name with given number
+	// of slots.
+	Semaphore(name string, size int) *Semaphore
+
+	// Counter method returns the atomic counter of given name.
+	Counter(name string) *Counter
+
+	// Query method returns the cached query result of given key into dst,
+	// on miss given func result is cached.
+	Query(k string, d time.Duration, dst interface{}, fn LoaderFunc) error
+
+	// Lookup method returns the cached value of given key, reporting the
+	// hit and the failure separately.
+	Lookup(k string) (interface{}, bool, error)
+
+	// GetInto method decodes the cached value of given key into dst.
+	GetInto(k string, dst interface{}) (bool, error)
+
+	// GetItem method returns the memcache item of given key as stored,
+	// without the provider codec.
+	GetItem(k string) (*Item, error)
+
+	// SetItem method stores the memcache item as is.
+	SetItem(item *Item) error
+
+	// Gets method returns the cached value of given key with its CAS token.
+	Gets(k string) (interface{}, CASToken, error)
+
+	// PutCAS method stores the value of given key if it has not been
+	// modified since the token was read.
+	PutCAS(k string, v interface{}, d time.Duration, token CASToken) error
+
+	// TryUpdate method updates the value of given key with given func using
+	// the Gets/CAS loop, it returns the number of conflicts.
+	TryUpdate(k string, fn UpdateFunc, maxRetries int, backoff ...BackoffFunc) (int, error)
+
+	// ForTenant method returns the cache of given tenant, its keys are
+	// isolated from the other tenants.
+	ForTenant(id string) (Cache, error)
+
+	// ForContext method returns the cache of the tenant carried by given
+	// context.
+	ForContext(ctx context.Context) (Cache, error)
+
+	// Tenant method returns the tenant ID of the cache.
+	Tenant() string
+
+	// GetContext method returns the cached entry for given key bounded by
+	// the deadline of given context.
+	GetContext(ctx context.Context, k string) (interface{}, error)
+
+	// GetMultiContext method returns the cached entries for given keys
+	// bounded by the deadline of given context.
+	GetMultiContext(ctx context.Context, keys []string) (map[string]interface{}, error)
+
+	// PutContext method adds the cache entry bounded by the deadline of
+	// given context.
+	PutContext(ctx context.Context, k string, v interface{}, d time.Duration) error
+
+	// DeleteContext method deletes the cache entry bounded by the deadline
+	// of given context.
+	DeleteContext(ctx context.Context, k string) error
+
+	// Pipeline method returns the new pipeline executing the queued
+	// operations with minimal round trips.
+	Pipeline() *Pipeline
+
+	// GetMultiDetailed method returns the cached entries for given keys
+	// along with the timing of the multiget of each memcache server.
+	GetMultiDetailed(keys []string) MultiResult
+}
+
+// LoaderFunc type is used to compute the value for a cache key on miss.
+type LoaderFunc func() (interface{}, error)
+
+// KeyLoaderFunc type is used to compute the value for given cache key.
+type KeyLoaderFunc func(k string) (interface{}, error)
+
+// BatchLoaderFunc type is used to compute the values for given cache keys.
+// Keys absent in the returned map are treated as not found and cached as
+// negative entries.
+type BatchLoaderFunc func(keys []string) (map[string]interface{}, error)
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // memcacheCache struct implements `cache.Cache` interface.
 //______________________________________________________________________________
 
 type memcacheCache struct {
-	keyPrefix string
-	p         *Provider
+	cfg           *cache.Config
+	keyPrefix     string
+	p             *Provider
+	flight        flightGroup
+	getFlight     flightGroup
+	softTTL       time.Duration
+	negativeTTL   time.Duration
+	slowThreshold time.Duration
+	logMisses     bool
+	redactor      keyRedactor
+	keyEcho       bool
+	ttl           ttlPolicy
+	toucher       *toucher
+	size          sizeLimit
+	chunkLimit    int
+	rawValues     bool
+	fastCodec     bool
+	strictTypes   bool
+	interop       int
+	lease         leasePolicy
+	deadline      deadlinePolicy
+	labels        profileLabels
+	lowPriority   bool
+	deleteCorrupt bool
+	readOnly      bool
+	writeOnly     bool
+	gens          *generations
+	registry      *keyRegistry
+	hot           *hotKeys
+	hooks         hookList
+	counters      *counters
+	vars          *cacheVars
+	wq            *writeQueue
+	asyncMu       sync.Mutex
+	async         *writeQueue
+
+	loaderMu   sync.RWMutex
+	loader     KeyLoaderFunc
+	batchTTL   time.Duration
+	batchFn    BatchLoaderFunc
+	refreshing sync.Map
+
+	refreshMu  sync.RWMutex
+	refreshers map[string]*refresher
+
+	deleteBatch       int
+	deleteConcurrency int
+	closed            bool
+
+	parent  *memcacheCache // cache of the tenant cache
+	tenant  string
+	tenants sync.Map
 }
 
 var _ cache.Cache = (*memcacheCache)(nil)
+var _ Cache = (*memcacheCache)(nil)
 
 // Name method returns the cache store name.
 func (m *memcacheCache) Name() string {
-	return m.p.cfg.Name
+	return m.cfg.Name
 }
 
 // Get method returns the cached entry for given key if it exists otherwise nil.
 // Method uses `gob.Decoder` to unmarshal cache value from bytes.
+//
+// Concurrent Gets for the same key are coalesced into single memcache
+// request, callers receive the same value.
 func (m *memcacheCache) Get(k string) interface{} {
-	k = m.keyPrefix + k
-	v, err := m.p.client.Get(k)
-	if err != nil {
-		// if notacacheMiss(err) != nil {
-		m.p.logger.Errorf("aah/cache/%s: key(%s) %v", m.Name(), k[len(m.keyPrefix):], err)
-		// }
-		return nil
-	}
+	v, _ := m.getContext(context.Background(), k)
+	return v
+}
 
-	var e entry
-	err = gob.NewDecoder(bytes.NewBuffer(v.Value)).Decode(&e)
-	if err != nil {
-		m.p.logger.Errorf("aah/cache/%s: %v", m.Name(), err)
-		return nil
+// Lookup method returns the cached value of given key, found reports the hit
+// and error the failed memcache call, so that the miss and the failure are
+// not both nil as with Get. It returns `ErrNotFound` for the negative entry
+// and `Nil` value for the cached nil. Value which could not be decoded is
+// logged and counted in `Stats.DecodeFailures`, it is reported as miss.
+//
+//	v, found, err := mc.Lookup("user:1")
+//	switch {
+//	case err != nil:
+//		// memcache failure
+//	case !found:
+//		// miss
+//	}
+func (m *memcacheCache) Lookup(k string) (interface{}, bool, error) {
+	e, found, err := m.lookupEntry(k)
+	if err != nil || !found {
+		return nil, false, err
 	}
-	if m.p.cfg.EvictionMode == cache.EvictionModeSlide {
-		if err = m.p.client.Touch(k, e.D); err != nil {
-			m.p.logger.Errorf("aah/cache/%s: key(%s) %v", m.Name(), k[len(m.keyPrefix):], err)
-		}
+	if e.notFound {
+		m.onRead(k, e)
+		return nil, false, ErrNotFound
 	}
-
-	return e.V
+	m.served(k, e)
+	return e.V, true, nil
 }
 
 // GetOrPut method returns the cached entry for the given key if it exists otherwise
 // it puts the new entry into cache store and returns the value.
+//
+// Hit costs single read. On miss entry is stored using memcache `add`
+// command, first writer wins across the app instances and the other callers
+// receive the winner's value. Concurrent GetOrPut calls for the same key
+// within the process are coalesced, all callers receive the same value.
 func (m *memcacheCache) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
-	ev := m.Get(k)
-	if ev == nil {
-		if err := m.Put(k, v, d); err != nil {
-			return nil, err
+	return m.flight.Do("getorput:"+k, func() (interface{}, error) {
+		e, found := m.getEntry(k)
+		for i := 0; i < 2; i++ {
+			if found {
+				if !e.notFound {
+					m.onRead(k, e)
+					return e.V, nil
+				}
+				// negative entry gets replaced by the given value
+				if err := m.Put(k, v, d); err != nil {
+					return nil, err
+				}
+				return v, nil
+			}
+			err := m.store(m.client().Add, k, v, d)
+			if err == nil {
+				return v, nil
+			}
+			if !errors.Is(err, memcache.ErrNotStored) {
+				return nil, err
+			}
+			// another writer won in-between, read its entry
+			e, found = m.getEntry(k)
 		}
 		return v, nil
-	}
-	return ev, nil
+	})
 }
 
 // Put method adds the cache entry with specified expiration. Returns error
 // if cache entry exists. Method uses `gob.Encoder` to marshal cache value into bytes.
+//
+// When the cache has write-behind mode enabled, entry is encoded immediately
+// and written to memcache asynchronously.
 func (m *memcacheCache) Put(k string, v interface{}, d time.Duration) error {
-	e := entry{D: int32(d.Seconds()), V: v}
-	buf := acquireBuffer()
-	enc := gob.NewEncoder(buf)
-	if err := enc.Encode(e); err != nil {
-		return fmt.Errorf("aah/cache/%s: %v", m.Name(), err)
+	return m.putContext(context.Background(), k, v, d)
+}
+
+// Delete method deletes the cache entry from cache store.
+func (m *memcacheCache) Delete(k string) error {
+	return m.deleteContext(context.Background(), k)
+}
+
+// Flush methods flushes(deletes) all the cache entries from cache.
+//
+// When the cache has `key_tracking` enabled, only the tracked keys of this
+// cache are deleted, other caches sharing the memcache servers are not
+// affected. Key registry is local to the app instance, so the flush is per
+// instance: entries written by the other app instances are not deleted, use
+// `prefix_invalidation` to invalidate the cache across the instances.
+// Otherwise all the entries of memcache servers are flushed.
+func (m *memcacheCache) Flush() error {
+	if m.tenant != "" {
+		return m.flushTenant()
+	}
+	if m.registry != nil {
+		keys := m.registry.keys()
+		if m.registry.reset() {
+			m.p.logger.Warnf("aah/cache/%s: key registry reached max_keys, flush is partial", m.Name())
+		}
+		err := m.deleteMulti(keys)
+		m.audit("flush", "", len(keys), err)
+		return err
+	}
+	if err := m.client().FlushAll(); err != nil {
+		err = m.opError("flush", "", "", nil, err)
+		m.audit("flushall", "", -1, err)
+		return err
 	}
+	m.audit("flushall", "", -1, nil)
+	return nil
+}
 
-	err := m.p.client.Set(&memcache.Item{
-		Key:        m.keyPrefix + k,
-		Value:      buf.Bytes(),
-		Expiration: e.D,
+// getContext method returns the coalesced Get of given key, the duplicate
+// caller returns on its own context done. It reads the key by itself if the
+// original caller ran out of its deadline first.
+func (m *memcacheCache) getContext(ctx context.Context, k string) (interface{}, error) {
+	v, err := m.getFlight.DoContext(ctx, k, func() (interface{}, error) {
+		return m.get(ctx, k), ctx.Err()
 	})
-	releaseBuffer(buf)
-	return err
+	if err != nil && ctx.Err() == nil {
+		return m.get(ctx, k), nil
+	}
+	return v, err
 }
 
-// Delete method deletes the cache entry from cache store.
-func (m *memcacheCache) Delete(k string) error {
-	if err := m.p.client.Delete(m.keyPrefix + k); notacacheMiss(err) != nil {
-		return fmt.Errorf("aah/cache/%s: key(%s) %v", m.Name(), k, err)
+func (m *memcacheCache) get(ctx context.Context, k string) interface{} {
+	e, found, err := m.lookupEntryContext(ctx, k)
+	if err != nil {
+		m.p.logError(err)
+	}
+	if !found {
+		return nil
 	}
+	m.served(k, e)
+	return e.V
+}
+
+func (m *memcacheCache) putContext(ctx context.Context, k string, v interface{}, d time.Duration) error {
+	if m.wq != nil {
+		e, err := m.newEntry(k, v, d)
+		if err != nil {
+			return err
+		}
+		return m.putBehind(k, e, nil)
+	}
+	return m.storeContext(ctx, m.clientContext(ctx).Set, k, v, d)
+}
+
+func (m *memcacheCache) deleteContext(ctx context.Context, k string) error {
+	m.onDeleted(k)
+	o := m.beginContext(ctx, "delete", k)
+	mk := m.key(k)
+	m.wq.cancel(mk)
+	m.asyncQueue().cancel(mk)
+	err := notacacheMiss(m.clientContext(ctx).Delete(mk))
+	o.end(err)
+	if err != nil {
+		return m.opError("delete", k, mk, nil, err)
+	}
+	m.counters.deleted()
 	return nil
 }
 
-// Exists method checks given key exists in cache store and its not expried.
-func (m *memcacheCache) Exists(k string) bool {
-	if v := m.Get(k); v != nil {
-		return true
+// served method is called with the entry returned to the Get caller, the
+// entry past soft TTL is revalidated in the background.
+func (m *memcacheCache) served(k string, e *entry) {
+	if m.softTTL > 0 && !e.notFound && e.T > 0 && time.Since(time.Unix(0, e.T)) > m.softTTL {
+		m.revalidate(k, time.Duration(e.D)*time.Second)
 	}
-	return false
+	m.onRead(k, e)
 }
 
-// Flush methods flushes(deletes) all the cache entries from cache.
-func (m *memcacheCache) Flush() error {
-	if err := m.p.client.FlushAll(); err != nil {
-		return fmt.Errorf("aah/cache/%s: %v", m.Name(), err)
+// getEntry method returns the cache entry of given key. Cache miss is
+// counted in the metrics and not logged by default, real errors are logged
+// at ERROR level.
+//
+//	cache {
+//	  mycache {
+//	    # supported values are none, debug; default value is none
+//	    miss_log = "debug"
+//	  }
+//	}
+func (m *memcacheCache) getEntry(k string) (*entry, bool) {
+	e, found, err := m.lookupEntry(k)
+	if err != nil {
+		m.p.logError(err)
+	}
+	return e, found
+}
+
+// lookupEntry method returns the cache entry of given key, error is returned
+// for the failed memcache call.
+func (m *memcacheCache) lookupEntry(k string) (*entry, bool, error) {
+	return m.lookupEntryContext(context.Background(), k)
+}
+
+func (m *memcacheCache) lookupEntryContext(ctx context.Context, k string) (*entry, bool, error) {
+	m.hot.observe(k)
+	o := m.beginContext(ctx, "get", k)
+	mk := m.key(k)
+	v, err := m.getItemContext(ctx, mk)
+	if err != nil {
+		if err == memcache.ErrCacheMiss {
+			if m.p.secondary != nil && !m.writeOnly {
+				if e, found := m.readSecondary(k, mk); found {
+					o.hit(0)
+					o.end(nil)
+					return e, true, nil
+				}
+			}
+			o.miss()
+			o.end(nil)
+			if m.logMisses {
+				m.p.logger.Debugf("aah/cache/%s: key(%s) cache miss", m.Name(), m.logKey(k))
+			}
+			return nil, false, nil
+		}
+		o.end(err)
+		return nil, false, m.opError("get", k, mk, nil, err)
+	}
+	o.hit(len(v.Value))
+	o.end(nil)
+	e, found := m.decodeItem(k, v)
+	return e, found, nil
+}
+
+// decodeItem method decodes the memcache item into cache entry, in the slide
+// eviction mode it extends the expiration of the entry.
+func (m *memcacheCache) decodeItem(k string, v *memcache.Item) (*entry, bool) {
+	if m.interop != interopNone {
+		e, err := m.decodeInterop(v)
+		if err != nil {
+			m.undecodable(k, v.Key, err)
+			return nil, false
+		}
+		return e, true
+	}
+	if v.Flags&flagNotFound == flagNotFound {
+		return &entry{notFound: true}, true
+	}
+
+	value := v.Value
+	if v.Flags&flagChunked == flagChunked {
+		var err error
+		if value, err = m.loadChunks(v); err != nil {
+			if err != memcache.ErrCacheMiss {
+				m.undecodable(k, v.Key, err)
+			}
+			return nil, false
+		}
+	}
+
+	if v.Flags&flagStream == flagStream {
+		return &entry{V: value, flags: v.Flags &^ flagChunked}, true
+	}
+
+	var e *entry
+	if isRaw(v) {
+		var ok bool
+		if e, ok = decodeRaw(value, v.Flags&^flagChunked); !ok {
+			m.undecodable(k, v.Key, errRawHeader)
+			return nil, false
+		}
+	} else if isFast(v.Flags) {
+		if !knownFormat(v.Flags) {
+			m.p.logger.Debugf("aah/cache/%s: key(%s) unknown payload format %#x, treated as cache miss",
+				m.Name(), m.logKey(k), v.Flags&flagFormatMask)
+			return nil, false
+		}
+		var (
+			typ string
+			err error
+		)
+		if e, typ, err = decodeFast(value); err != nil {
+			m.undecodableType(k, v.Key, typ, err)
+			return nil, false
+		}
+		if e == nil {
+			m.counters.codecFailed("decode", typ, errFastMismatch)
+			m.p.logger.Debugf("aah/cache/%s: key(%s) fast codec type %s is not registered or its layout differs, treated as cache miss",
+				m.Name(), m.logKey(k), typ)
+			return nil, false
+		}
+		if m.echoMismatch(k, e) {
+			return nil, false
+		}
+		e.flags = v.Flags &^ (flagChunked | flagFormatMask)
+	} else {
+		if !knownFormat(v.Flags) {
+			m.p.logger.Debugf("aah/cache/%s: key(%s) unknown payload format %#x, treated as cache miss",
+				m.Name(), m.logKey(k), v.Flags&flagFormatMask)
+			return nil, false
+		}
+		e = new(entry)
+		if err := decodeGob(value, e); err != nil {
+			m.undecodable(k, v.Key, err)
+			return nil, false
+		}
+		if m.echoMismatch(k, e) {
+			return nil, false
+		}
+		if e.B == nil && !m.p.schemas.migrate(e) {
+			m.counters.codecFailed("decode", e.S, fmt.Errorf("schema version %d cannot be migrated", e.SV))
+			m.p.logger.Debugf("aah/cache/%s: key(%s) schema %s version %d cannot be migrated, treated as cache miss",
+				m.Name(), m.logKey(k), e.S, e.SV)
+			return nil, false
+		}
+		e.flags = v.Flags &^ (flagChunked | flagFormatMask)
+		decodeNil(e)
+	}
+	if m.touchOnRead(e.flags) {
+		m.touch(k, v.Key, e.D)
+		if v.Flags&flagChunked == flagChunked {
+			m.touchChunks(k, v, e.D)
+		}
+	}
+
+	return e, true
+}
+
+func (m *memcacheCache) store(fn func(*memcache.Item) error, k string, v interface{}, d time.Duration) error {
+	return m.storeContext(context.Background(), fn, k, v, d)
+}
+
+func (m *memcacheCache) storeContext(ctx context.Context, fn func(*memcache.Item) error, k string, v interface{}, d time.Duration) error {
+	e, err := m.newEntry(k, v, d)
+	if err != nil {
+		return err
+	}
+	return m.storeEntryWith(ctx, fn, k, e, true)
+}
+
+func (m *memcacheCache) storeEntry(fn func(*memcache.Item) error, k string, e *entry) error {
+	return m.storeEntryWith(context.Background(), fn, k, e, true)
+}
+
+// storeEntryWith method stores the entry via given func, value over the
+// chunk size is rejected with `ErrValueTooLarge` unless chunk is true.
+func (m *memcacheCache) storeEntryWith(ctx context.Context, fn func(*memcache.Item) error, k string, e *entry, chunk bool) error {
+	item, err := m.encodeEntry(k, e)
+	if err != nil {
+		return err
+	}
+	if skip, err := m.checkSize(k, item); skip || err != nil {
+		return err
+	}
+	if !chunk && len(item.Value) > m.chunkSize() {
+		return m.opError("put", k, item.Key, ErrValueTooLarge,
+			fmt.Errorf("value of %d bytes exceeds chunk size %d", len(item.Value), m.chunkSize()))
+	}
+	o := m.beginContext(ctx, "put", k)
+	o.written(len(item.Value))
+	if len(item.Value) > m.chunkSize() {
+		err = m.storeChunked(fn, k, item)
+	} else {
+		err = fn(item)
+	}
+	if err == nil {
+		m.counters.written(len(item.Key), len(item.Value))
+		m.onStored(k, e.D)
+	}
+	o.end(notStored(err))
+	if err != nil {
+		return m.opError("put", k, item.Key, nil, err)
 	}
 	return nil
 }
 
+// encodeEntry method marshals the cache entry into memcache item, item owns
+// its value bytes.
+func (m *memcacheCache) encodeEntry(k string, e *entry) (*memcache.Item, error) {
+	if e.V == Nil {
+		if m.interop != interopNone {
+			return nil, m.encodeFailed(k, e.V, errors.New("cached nil is not supported in the interop mode"))
+		}
+		e = encodeNil(e)
+	}
+	if m.interop != interopNone {
+		return m.encodeInterop(k, e)
+	}
+	mk, hashed := m.fitKey(k)
+	if m.rawValues {
+		if value, flags, ok := m.encodeRaw(e); ok {
+			return &memcache.Item{Key: mk, Value: value, Flags: e.flags | flags, Expiration: expirationOf(e.D)}, nil
+		}
+	}
+	if hashed && m.keyEcho {
+		e.K = k
+	}
+	if m.fastCodec {
+		value, ok, err := encodeFast(e)
+		if err != nil {
+			return nil, m.encodeFailed(k, e.V, err)
+		}
+		if ok {
+			return &memcache.Item{Key: mk, Value: value, Flags: e.flags | fastFlags(), Expiration: expirationOf(e.D)}, nil
+		}
+	}
+	e.S = schemaHint(e.V)
+	e.SV = m.p.schemas.version(e.S)
+	buf := acquireBuffer(sizeHint(e.V))
+	defer releaseBuffer(buf)
+	enc := gob.NewEncoder(buf)
+	err := enc.Encode(e)
+	if err != nil && isUnregistered(err) {
+		buf.Reset()
+		err = encodeConcrete(buf, e)
+	}
+	if err != nil {
+		return nil, m.encodeFailed(k, e.V, err)
+	}
+
+	return &memcache.Item{
+		Key:        mk,
+		Value:      append([]byte(nil), buf.Bytes()...),
+		Flags:      e.flags | formatFlags(),
+		Expiration: expirationOf(e.D),
+	}, nil
+}
+
+// onStored method is called after successful write of the entry.
+func (m *memcacheCache) onStored(k string, d int32) {
+	m.trackRefresh(k, false)
+	if m.registry != nil {
+		m.registry.add(k, time.Duration(d)*time.Second)
+	}
+}
+
+// onRead method is called after the entry hit.
+func (m *memcacheCache) onRead(k string, e *entry) {
+	m.trackRefresh(k, true)
+	if m.registry != nil && m.touchOnRead(e.flags) {
+		m.registry.add(k, time.Duration(e.D)*time.Second)
+	}
+}
+
+// onDeleted method is called on delete of the entry.
+func (m *memcacheCache) onDeleted(k string) {
+	m.untrackRefresh(k)
+	if m.registry != nil {
+		m.registry.remove(k)
+	}
+}
+
+// flushesAll method reports whether Flush of the cache flushes all the
+// entries of the memcache servers, not only the entries of the cache.
+func (m *memcacheCache) flushesAll() bool {
+	return m.tenant == "" && m.registry == nil
+}
+
+// key method returns the memcache key for given cache key.
+func (m *memcacheCache) key(k string) string {
+	mk, _ := m.fitKey(k)
+	return mk
+}
+
+// settingKey method returns the config key for given cache setting. Cache level
+// setting `cache.<cache_name>.<key>` takes precedence over provider level
+// setting `cache.<provider_name>.<key>`.
+func (m *memcacheCache) settingKey(key string) string {
+	if k := "cache." + m.cfg.Name + "." + key; m.p.config().IsExists(k) {
+		return k
+	}
+	return "cache." + m.p.name + "." + key
+}
+
+func (m *memcacheCache) settingString(key, def string) string {
+	return m.p.config().StringDefault(m.settingKey(key), def)
+}
+
+func (m *memcacheCache) settingInt(key string, def int) int {
+	return m.p.config().IntDefault(m.settingKey(key), def)
+}
+
+func (m *memcacheCache) settingBool(key string, def bool) bool {
+	return m.p.config().BoolDefault(m.settingKey(key), def)
+}
+
 //‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
 // Helper methods
 //______________________________________________________________________________
 
+// entry struct is the envelope stored in memcache for every cache value.
+//
+//	D - expiration in seconds
+//	V - cache value
+//	T - write timestamp in unix nanoseconds
+//	C - computation cost of the value in nanoseconds, if known
+//	K - original key of the hashed memcache key, if `key_echo` is enabled
+//	S - schema hint, type name of the cache value
+//	SV - schema version of the value type, see `Provider.RegisterSchema`
+//	B - gob encoding of the cache value whose type is not registered with
+//	    gob, V is nil then; it is decoded by `GetInto`
 type entry struct {
-	D int32
-	V interface{}
+	D  int32
+	V  interface{}
+	T  int64
+	C  int64
+	K  string
+	S  string
+	SV int
+	B  []byte
+
+	notFound bool
+	flags    uint32
+}
+
+func newEntry(v interface{}, d time.Duration) *entry {
+	return &entry{D: int32(d.Seconds()), V: v, T: time.Now().UnixNano()}
 }
 
 func parseDuration(v, f string) time.Duration {
@@ -197,22 +1055,18 @@ func parseDuration(v, f string) time.Duration {
 	return d
 }
 
-var bufPool = sync.Pool{New: func() interface{} { return new(bytes.Buffer) }}
-
-func acquireBuffer() *bytes.Buffer {
-	return bufPool.Get().(*bytes.Buffer)
-}
-
-func releaseBuffer(b *bytes.Buffer) {
-	if b != nil {
-		b.Reset()
-		bufPool.Put(b)
+// notStored function treats `add` of existing key as outcome rather than
+// error of the operation.
+func notStored(err error) error {
+	if err == memcache.ErrNotStored {
+		return nil
 	}
+	return err
 }
 
 func notacacheMiss(err error) error {
 	if err == memcache.ErrCacheMiss {
 		return nil
 	}
-	return nil
+	return err
 }
@@ -5,14 +5,17 @@
 package memcache
 
 import (
+	"bytes"
 	"encoding/gob"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"strings"
 	"testing"
 	"time"
 
 	"aahframe.work/cache"
+	"aahframe.work/cache/provider/memcache/memcachetest"
 	"aahframe.work/config"
 	"aahframe.work/log"
 	"github.com/stretchr/testify/assert"
@@ -73,7 +76,8 @@ func TestMemcache(t *testing.T) {
 	}
 
 	err := c.Put("pre-test-key1", sample{Name: "Jeeva", Present: true, Value: "memcache provider"}, 3*time.Second)
-	assert.Equal(t, errors.New("aah/cache/cache1: gob: type not registered for interface: memcache.sample"), err)
+	assert.Equal(t, "aah/cache/cache1: key(pre-test-key1) gob: type not registered for interface: memcache.sample", err.Error())
+	assert.True(t, errors.Is(err, ErrEncode))
 	_, _ = c.GetOrPut("pre-test-key1", sample{Name: "Jeeva", Present: true, Value: "memcache provider"}, 3*time.Second)
 
 	gob.Register(map[string]interface{}{})
@@ -179,6 +183,92 @@ func TestRedisSlideEvictionMode(t *testing.T) {
 	assert.Equal(t, "addgetcache", c.Name())
 }
 
+func TestMemcacheGetOrPutFirstWriterWins(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "getorputcache", ProviderName: "memcache1"})
+
+	v, err := c.GetOrPut("key1", "first", 3*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", v)
+
+	v, err = c.GetOrPut("key1", "second", 3*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "first", v)
+	assert.Equal(t, "first", c.Get("key1"))
+
+	assert.Nil(t, c.Delete("key1"))
+}
+
+func TestMemcacheGetOrPutRoundTrips(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "roundtrips", ProviderName: "memcache1"}).(Cache)
+
+	// miss: one read and one write
+	v, err := c.GetOrPut("key1", "value1", 3*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "value1", v)
+	assert.Equal(t, uint64(1), c.Stats().Latency["get"].Count)
+	assert.Equal(t, uint64(1), c.Stats().Latency["put"].Count)
+
+	// hit: exactly one read
+	v, err = c.GetOrPut("key1", "value2", 3*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "value1", v)
+	assert.Equal(t, uint64(2), c.Stats().Latency["get"].Count)
+	assert.Equal(t, uint64(1), c.Stats().Latency["put"].Count)
+
+	assert.Nil(t, c.Delete("key1"))
+}
+
+func TestMemcacheMissLog(t *testing.T) {
+	mgr := cache.NewManager()
+	mgr.AddProvider("memcache1", new(Provider))
+
+	cfg, _ := config.ParseString(`
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		misscache {
+			miss_log = "debug"
+		}
+		invalidmiss {
+			miss_log = "error"
+		}
+	}
+`)
+	buf := new(bytes.Buffer)
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(buf)
+	assert.Nil(t, mgr.InitProviders(cfg, l))
+
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "quietcache", ProviderName: "memcache1"}))
+	assert.Nil(t, mgr.Cache("quietcache").Get("nokey"))
+	assert.Equal(t, "", buf.String())
+	assert.Equal(t, uint64(1), mgr.Cache("quietcache").(Cache).Stats().Misses)
+
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "misscache", ProviderName: "memcache1"}))
+	assert.Nil(t, mgr.Cache("misscache").Get("nokey"))
+	assert.True(t, strings.Contains(buf.String(), "DEBUG"))
+	assert.True(t, strings.Contains(buf.String(), "aah/cache/misscache: key(nokey) cache miss"))
+
+	err := mgr.CreateCache(&cache.Config{Name: "invalidmiss", ProviderName: "memcache1"})
+	assert.Equal(t, "aah/cache/invalidmiss: unsupported miss_log 'error'", err.Error())
+}
+
 func TestMemcacheInvalidProviderName(t *testing.T) {
 	mgr := cache.NewManager()
 	mgr.AddProvider("memcache1", new(Provider))
@@ -218,6 +308,97 @@ func TestParseTimeDuration(t *testing.T) {
 	assert.Equal(t, float64(1), d.Minutes())
 }
 
+func TestMemcacheLookup(t *testing.T) {
+	srv, err := memcachetest.NewServer()
+	assert.Nil(t, err)
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["`+srv.Addr()+`"]
+		}
+	}
+`, &cache.Config{Name: "lookup", ProviderName: "memcache1"}).(Cache)
+
+	assert.Nil(t, c.Put("key1", "value1", time.Minute))
+	v, found, err := c.Lookup("key1")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "value1", v)
+
+	v, found, err = c.Lookup("absent")
+	assert.Nil(t, err)
+	assert.False(t, found)
+	assert.Nil(t, v)
+
+	assert.Nil(t, c.Put("key2", Nil, time.Minute))
+	v, found, err = c.Lookup("key2")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, Nil, v)
+	assert.Equal(t, Nil, c.Get("key2"))
+
+	assert.Nil(t, c.PutNotFound("key3"))
+	_, found, err = c.Lookup("key3")
+	assert.Equal(t, ErrNotFound, err)
+	assert.False(t, found)
+
+	_ = srv.Close()
+	_, found, err = c.Lookup("key1")
+	assert.NotNil(t, err)
+	assert.False(t, found)
+}
+
+func TestMemcacheLookupDryRun(t *testing.T) {
+	m := newBenchCache()
+	v, found, err := m.Lookup("key1")
+	assert.Nil(t, err)
+	assert.False(t, found)
+	assert.Nil(t, v)
+	assert.Equal(t, 1, len(m.p.DryRunOps()))
+}
+
+func BenchmarkMemcacheGetDryRunMiss(b *testing.B) {
+	m := newBenchCache()
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.Get("bench")
+	}
+}
+
+func BenchmarkMemcacheDecodeItem(b *testing.B) {
+	m := newBenchCache()
+	item, _ := m.encodeEntry("bench", newEntry("sample value of the benchmark", time.Minute))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.decodeItem("bench", item)
+	}
+}
+
+func BenchmarkMemcacheDecodeItemFast(b *testing.B) {
+	_ = RegisterFast("test.fastAddress", fastAddress{})
+	m := newBenchCache()
+	m.fastCodec = true
+	item, _ := m.encodeEntry("bench", newEntry(fastAddress{City: "Chennai", Zip: 600001}, time.Minute))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		m.decodeItem("bench", item)
+	}
+}
+
+// newBenchCache returns the dry-run cache, its reads miss.
+func newBenchCache() *memcacheCache {
+	l, _ := log.New(config.NewEmpty())
+	l.SetWriter(ioutil.Discard)
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 1}}
+	p.appCfg.Store(config.NewEmpty())
+	return &memcacheCache{cfg: &cache.Config{Name: "bench"}, p: p, keyPrefix: "bench-",
+		counters: new(counters), chunkLimit: defaultChunkSize}
+}
+
 func createCacheMgr(t *testing.T, name, appCfgStr string) *cache.Manager {
 	mgr := cache.NewManager()
 	mgr.AddProvider(name, new(Provider))
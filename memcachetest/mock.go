@@ -0,0 +1,361 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcachetest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+)
+
+// ErrUnexpectedCall is returned by the mock cache for the calls not matching
+// any expectation.
+var ErrUnexpectedCall = errors.New("memcachetest: unexpected call")
+
+// AnyValue matches any value of the expectation.
+var AnyValue interface{} = anyValue{}
+
+// AnyDuration matches any expiration of the expectation.
+const AnyDuration = time.Duration(math.MinInt64)
+
+// Mock operation names.
+const (
+	OpGet      = "get"
+	OpGetOrPut = "getorput"
+	OpPut      = "put"
+	OpDelete   = "delete"
+	OpExists   = "exists"
+	OpFlush    = "flush"
+)
+
+var (
+	_ cache.Provider = (*MockProvider)(nil)
+	_ cache.Cache    = (*Mock)(nil)
+)
+
+// MockProvider struct is the `cache.Provider` returning the mock caches, so
+// the cache interactions of the app code could be asserted in the unit test.
+//
+//	mp := memcachetest.NewMockProvider()
+//	mc := mp.Mock("mycache")
+//	mc.ExpectGet("user:1").Returns(user)
+//	mc.ExpectPut("user:2", memcachetest.AnyValue, time.Hour)
+//
+//	// app code under test, e.g.
+//	// cm.AddProvider("memcache", mp)
+//
+//	if err := mp.ExpectationsWereMet(); err != nil {
+//		t.Error(err)
+//	}
+type MockProvider struct {
+	mu     sync.Mutex
+	name   string
+	caches map[string]*Mock
+}
+
+// NewMockProvider method returns the new mock provider.
+func NewMockProvider() *MockProvider {
+	return &MockProvider{caches: make(map[string]*Mock)}
+}
+
+// Init method records the provider name, mock provider does not use the
+// configuration.
+func (mp *MockProvider) Init(providerName string, _ *config.Config, _ log.Loggerer) error {
+	mp.mu.Lock()
+	mp.name = providerName
+	mp.mu.Unlock()
+	return nil
+}
+
+// Create method returns the mock cache of given cache name.
+func (mp *MockProvider) Create(cfg *cache.Config) (cache.Cache, error) {
+	return mp.Mock(cfg.Name), nil
+}
+
+// Mock method returns the mock cache of given name, it is created on first
+// call. Set the expectations prior to the app code creating the cache.
+func (mp *MockProvider) Mock(name string) *Mock {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	m, found := mp.caches[name]
+	if !found {
+		m = NewMock(name)
+		mp.caches[name] = m
+	}
+	return m
+}
+
+// ExpectationsWereMet method returns error if any of the mock caches has
+// unmet expectations or unexpected calls.
+func (mp *MockProvider) ExpectationsWereMet() error {
+	mp.mu.Lock()
+	defer mp.mu.Unlock()
+	var errs []string
+	for _, m := range mp.caches {
+		if err := m.ExpectationsWereMet(); err != nil {
+			errs = append(errs, err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// Call struct holds the call recorded by the mock cache.
+type Call struct {
+	Op       string
+	Key      string
+	Value    interface{}
+	Duration time.Duration
+}
+
+// Expectation struct holds the expected call of the mock cache and its
+// results.
+type Expectation struct {
+	op       string
+	key      string
+	value    interface{}
+	d        time.Duration
+	times    int
+	calls    int
+	returns  interface{}
+	err      error
+	returned bool
+}
+
+// Returns method sets the value returned by `Get`, `GetOrPut` or the
+// bool returned by `Exists` for the expected call.
+func (e *Expectation) Returns(v interface{}) *Expectation {
+	e.returns = v
+	e.returned = true
+	return e
+}
+
+// ReturnsError method sets the error returned for the expected call.
+func (e *Expectation) ReturnsError(err error) *Expectation {
+	e.err = err
+	return e
+}
+
+// Times method sets the number of calls the expectation matches, default
+// is 1.
+func (e *Expectation) Times(n int) *Expectation {
+	e.times = n
+	return e
+}
+
+func (e *Expectation) String() string {
+	s := e.op
+	if e.op != OpFlush {
+		s += fmt.Sprintf("(%s)", e.key)
+	}
+	return s
+}
+
+// Mock struct is the `cache.Cache` asserting the calls against its
+// expectations in the order they are set, unless
+// `MatchExpectationsInOrder(false)`. All calls are recorded.
+type Mock struct {
+	mu           sync.Mutex
+	name         string
+	ordered      bool
+	expectations []*Expectation
+	calls        []Call
+	errs         []error
+}
+
+// NewMock method returns the new mock cache of given name.
+func NewMock(name string) *Mock {
+	return &Mock{name: name, ordered: true}
+}
+
+// MatchExpectationsInOrder method sets whether the calls must match the
+// expectations in the order they are set, default is true.
+func (m *Mock) MatchExpectationsInOrder(b bool) {
+	m.mu.Lock()
+	m.ordered = b
+	m.mu.Unlock()
+}
+
+// ExpectGet method expects `Get` call of given key, it returns nil unless
+// `Returns` is set.
+func (m *Mock) ExpectGet(k string) *Expectation {
+	return m.expect(&Expectation{op: OpGet, key: k})
+}
+
+// ExpectGetOrPut method expects `GetOrPut` call of given key, value and
+// expiration. It returns the given value unless `Returns` is set.
+func (m *Mock) ExpectGetOrPut(k string, v interface{}, d time.Duration) *Expectation {
+	return m.expect(&Expectation{op: OpGetOrPut, key: k, value: v, d: d})
+}
+
+// ExpectPut method expects `Put` call of given key, value and expiration.
+func (m *Mock) ExpectPut(k string, v interface{}, d time.Duration) *Expectation {
+	return m.expect(&Expectation{op: OpPut, key: k, value: v, d: d})
+}
+
+// ExpectDelete method expects `Delete` call of given key.
+func (m *Mock) ExpectDelete(k string) *Expectation {
+	return m.expect(&Expectation{op: OpDelete, key: k})
+}
+
+// ExpectExists method expects `Exists` call of given key, it returns false
+// unless `Returns(true)` is set.
+func (m *Mock) ExpectExists(k string) *Expectation {
+	return m.expect(&Expectation{op: OpExists, key: k})
+}
+
+// ExpectFlush method expects `Flush` call.
+func (m *Mock) ExpectFlush() *Expectation {
+	return m.expect(&Expectation{op: OpFlush})
+}
+
+// Calls method returns the calls recorded by the mock cache in the call
+// order, unexpected calls included.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// ExpectationsWereMet method returns error if any call did not match the
+// expectations or any expectation was not called.
+func (m *Mock) ExpectationsWereMet() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.errs) > 0 {
+		return m.errs[0]
+	}
+	for _, e := range m.expectations {
+		if e.calls < e.times {
+			return fmt.Errorf("memcachetest/%s: expected %s not called", m.name, e)
+		}
+	}
+	return nil
+}
+
+// Name method returns the mock cache name.
+func (m *Mock) Name() string {
+	return m.name
+}
+
+// Get method returns the value of matching `ExpectGet`.
+func (m *Mock) Get(k string) interface{} {
+	e, _ := m.call(Call{Op: OpGet, Key: k})
+	if e == nil {
+		return nil
+	}
+	return e.returns
+}
+
+// GetOrPut method returns the value of matching `ExpectGetOrPut`.
+func (m *Mock) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
+	e, err := m.call(Call{Op: OpGetOrPut, Key: k, Value: v, Duration: d})
+	if err != nil {
+		return nil, err
+	}
+	if e.returned {
+		return e.returns, e.err
+	}
+	return v, e.err
+}
+
+// Put method returns the error of matching `ExpectPut`.
+func (m *Mock) Put(k string, v interface{}, d time.Duration) error {
+	e, err := m.call(Call{Op: OpPut, Key: k, Value: v, Duration: d})
+	if err != nil {
+		return err
+	}
+	return e.err
+}
+
+// Delete method returns the error of matching `ExpectDelete`.
+func (m *Mock) Delete(k string) error {
+	e, err := m.call(Call{Op: OpDelete, Key: k})
+	if err != nil {
+		return err
+	}
+	return e.err
+}
+
+// Exists method returns the bool of matching `ExpectExists`.
+func (m *Mock) Exists(k string) bool {
+	e, _ := m.call(Call{Op: OpExists, Key: k})
+	if e == nil {
+		return false
+	}
+	b, _ := e.returns.(bool)
+	return b
+}
+
+// Flush method returns the error of matching `ExpectFlush`.
+func (m *Mock) Flush() error {
+	e, err := m.call(Call{Op: OpFlush})
+	if err != nil {
+		return err
+	}
+	return e.err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type anyValue struct{}
+
+func (m *Mock) expect(e *Expectation) *Expectation {
+	e.times = 1
+	m.mu.Lock()
+	m.expectations = append(m.expectations, e)
+	m.mu.Unlock()
+	return e
+}
+
+// call method records the call and returns the matching expectation, error
+// wrapping `ErrUnexpectedCall` if none matches.
+func (m *Mock) call(c Call) (*Expectation, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, c)
+	for _, e := range m.expectations {
+		if e.calls >= e.times {
+			continue
+		}
+		if e.matches(c) {
+			e.calls++
+			return e, nil
+		}
+		if m.ordered {
+			err := fmt.Errorf("memcachetest/%s: %s(%s) %w, next expected %s", m.name, c.Op, c.Key, ErrUnexpectedCall, e)
+			m.errs = append(m.errs, err)
+			return nil, err
+		}
+	}
+	err := fmt.Errorf("memcachetest/%s: %s(%s) %w", m.name, c.Op, c.Key, ErrUnexpectedCall)
+	m.errs = append(m.errs, err)
+	return nil, err
+}
+
+func (e *Expectation) matches(c Call) bool {
+	if e.op != c.Op || e.key != c.Key {
+		return false
+	}
+	if e.op != OpPut && e.op != OpGetOrPut {
+		return true
+	}
+	if e.d != AnyDuration && e.d != c.Duration {
+		return false
+	}
+	return e.value == AnyValue || reflect.DeepEqual(e.value, c.Value)
+}
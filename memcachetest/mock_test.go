@@ -0,0 +1,69 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcachetest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMockExpectations(t *testing.T) {
+	mp := NewMockProvider()
+	mc := mp.Mock("mycache")
+	mc.ExpectGet("k1").Returns("v1")
+	mc.ExpectPut("k2", []byte("v2"), time.Minute)
+	mc.ExpectExists("k2").Returns(true)
+	mc.ExpectGetOrPut("k3", AnyValue, AnyDuration)
+	mc.ExpectDelete("k1").ReturnsError(errors.New("delete failed"))
+	mc.ExpectFlush()
+
+	assert.Nil(t, mp.Init("mock", nil, nil))
+	c, err := mp.Create(&cache.Config{Name: "mycache"})
+	assert.Nil(t, err)
+	assert.Equal(t, "mycache", c.Name())
+
+	assert.Equal(t, "v1", c.Get("k1"))
+	assert.Nil(t, c.Put("k2", []byte("v2"), time.Minute))
+	assert.True(t, c.Exists("k2"))
+	v, err := c.GetOrPut("k3", 3, time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, v)
+	assert.Equal(t, "delete failed", c.Delete("k1").Error())
+	assert.Nil(t, c.Flush())
+	assert.Nil(t, mp.ExpectationsWereMet())
+
+	calls := mc.Calls()
+	assert.Equal(t, 6, len(calls))
+	assert.Equal(t, Call{Op: OpPut, Key: "k2", Value: []byte("v2"), Duration: time.Minute}, calls[1])
+}
+
+func TestMockUnexpected(t *testing.T) {
+	mc := NewMock("mycache")
+	mc.ExpectGet("k1")
+	mc.ExpectPut("k1", 1, time.Minute)
+
+	err := mc.Put("k1", 1, time.Minute)
+	assert.True(t, errors.Is(err, ErrUnexpectedCall))
+	assert.Equal(t, "memcachetest/mycache: put(k1) memcachetest: unexpected call, next expected get(k1)",
+		mc.ExpectationsWereMet().Error())
+
+	mc = NewMock("mycache")
+	mc.MatchExpectationsInOrder(false)
+	mc.ExpectGet("k1").Times(2)
+	mc.ExpectPut("k1", 1, time.Minute)
+	assert.Nil(t, mc.Put("k1", 1, time.Minute))
+	assert.Nil(t, mc.Get("k1"))
+	assert.Equal(t, "memcachetest/mycache: expected get(k1) not called", mc.ExpectationsWereMet().Error())
+	assert.Nil(t, mc.Get("k1"))
+	assert.Nil(t, mc.ExpectationsWereMet())
+
+	assert.True(t, errors.Is(mc.Put("k1", 2, time.Minute), ErrUnexpectedCall))
+	assert.False(t, mc.Exists("k2"))
+	assert.NotNil(t, mc.ExpectationsWereMet())
+}
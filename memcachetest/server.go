@@ -0,0 +1,513 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package memcachetest provides in-memory memcache server speaking the
+// memcached text protocol, so the tests of the memcache provider and the
+// apps using it do not require memcached daemon.
+//
+//	s, err := memcachetest.NewServer()
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer s.Close()
+//
+//	// cache.memcache1.addresses = [s.Addr()]
+//
+// Server supports storage, retrieval, delete, incr/decr, touch, flush_all,
+// stats, version, `mg` meta-get and `lru_crawler metadump` commands with
+// expiration, flags and CAS semantics of memcached.
+//
+// `MockProvider` and `Mock` are the `cache.Provider` and `cache.Cache` with
+// expectations, to assert the cache interactions of the app code without
+// any server.
+package memcachetest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Version is the version reported by the server.
+const Version = "1.6.21-memcachetest"
+
+// maxRelativeExpiration is the longest expiration memcached treats as
+// relative to now in seconds, 30 days.
+const maxRelativeExpiration = 60 * 60 * 24 * 30
+
+// Server struct is in-memory memcache server listening on the loopback
+// interface.
+type Server struct {
+	// Now func returns the current time of the server, it could be replaced
+	// before the use to control the expiration in tests.
+	Now func() time.Time
+
+	ln    net.Listener
+	wg    sync.WaitGroup
+	mu    sync.Mutex
+	items map[string]*item
+	cas   uint64
+	conns map[net.Conn]bool
+	stats map[string]uint64
+}
+
+type item struct {
+	value []byte
+	flags uint32
+	exp   time.Time // zero means never
+	cas   uint64
+	la    time.Time
+}
+
+// NewServer function starts the server on random port of 127.0.0.1.
+func NewServer() (*Server, error) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, fmt.Errorf("memcachetest: %v", err)
+	}
+	s := &Server{
+		Now:   time.Now,
+		ln:    ln,
+		items: make(map[string]*item),
+		conns: make(map[net.Conn]bool),
+		stats: make(map[string]uint64),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s, nil
+}
+
+// Addr method returns the address of the server, e.g. `127.0.0.1:41233`.
+func (s *Server) Addr() string {
+	return s.ln.Addr().String()
+}
+
+// Close method stops the server and closes the client connections.
+func (s *Server) Close() error {
+	err := s.ln.Close()
+	s.mu.Lock()
+	for c := range s.conns {
+		_ = c.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+	return err
+}
+
+// Len method returns the number of items not expired.
+func (s *Server) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for k := range s.items {
+		if s.lookup(k) != nil {
+			n++
+		}
+	}
+	return n
+}
+
+// Keys method returns the sorted keys of the items not expired.
+func (s *Server) Keys() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.items))
+	for k := range s.items {
+		if s.lookup(k) != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Flush method deletes all the items.
+func (s *Server) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.items = make(map[string]*item)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = true
+		s.stats["total_connections"]++
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.handle(conn)
+			s.mu.Lock()
+			delete(s.conns, conn)
+			s.mu.Unlock()
+			_ = conn.Close()
+		}()
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return
+		}
+		f := strings.Fields(strings.TrimRight(line, "\r\n"))
+		if len(f) == 0 {
+			continue
+		}
+		if f[0] == "quit" {
+			return
+		}
+		resp, ok := s.command(f, rw.Reader)
+		_, _ = rw.WriteString(resp)
+		if err = rw.Flush(); err != nil || !ok {
+			// !ok: data block is not in the expected format, connection
+			// is out of sync
+			return
+		}
+	}
+}
+
+// command method executes the command and returns the response, false if
+// the connection should be closed.
+func (s *Server) command(f []string, r *bufio.Reader) (string, bool) {
+	s.mu.Lock()
+	s.stats["cmd_"+f[0]]++
+	s.mu.Unlock()
+
+	switch f[0] {
+	case "get", "gets":
+		return s.get(f[1:], f[0] == "gets"), true
+	case "set", "add", "replace", "append", "prepend", "cas":
+		return s.store(f, r)
+	case "delete":
+		return s.delete(f[1:]), true
+	case "incr", "decr":
+		return s.incr(f[1:], f[0] == "decr"), true
+	case "touch":
+		return s.touch(f[1:]), true
+	case "flush_all":
+		s.Flush()
+		return reply(f, "OK"), true
+	case "version":
+		return "VERSION " + Version + "\r\n", true
+	case "stats":
+		return s.statsReply(), true
+	case "mg":
+		return s.metaGet(f[1:]), true
+	case "lru_crawler":
+		if len(f) == 3 && f[1] == "metadump" {
+			return s.metadump(), true
+		}
+	}
+	return "ERROR\r\n", true
+}
+
+// lookup method returns the item of given key, expired item is deleted.
+// It must be called with the lock held.
+func (s *Server) lookup(k string) *item {
+	it, found := s.items[k]
+	if !found {
+		return nil
+	}
+	if !it.exp.IsZero() && !s.Now().Before(it.exp) {
+		delete(s.items, k)
+		return nil
+	}
+	return it
+}
+
+// expiration method returns the expiration time of given memcache
+// exptime, zero time for never; negative exptime is already expired.
+func (s *Server) expiration(v string) (time.Time, bool) {
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch {
+	case n == 0:
+		return time.Time{}, true
+	case n < 0:
+		return s.Now().Add(-time.Second), true
+	case n <= maxRelativeExpiration:
+		return s.Now().Add(time.Duration(n) * time.Second), true
+	}
+	return time.Unix(n, 0), true
+}
+
+func (s *Server) get(keys []string, withCAS bool) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var sb strings.Builder
+	for _, k := range keys {
+		it := s.lookup(k)
+		if it == nil {
+			s.stats["get_misses"]++
+			continue
+		}
+		s.stats["get_hits"]++
+		it.la = s.Now()
+		fmt.Fprintf(&sb, "VALUE %s %d %d", k, it.flags, len(it.value))
+		if withCAS {
+			fmt.Fprintf(&sb, " %d", it.cas)
+		}
+		sb.WriteString("\r\n")
+		sb.Write(it.value)
+		sb.WriteString("\r\n")
+	}
+	sb.WriteString("END\r\n")
+	return sb.String()
+}
+
+// store method executes the storage command, i.e.
+// `<cmd> <key> <flags> <exptime> <bytes> [<cas unique>] [noreply]`.
+func (s *Server) store(f []string, r *bufio.Reader) (string, bool) {
+	n := 5
+	if f[0] == "cas" {
+		n = 6
+	}
+	if len(f) < n {
+		return "ERROR\r\n", true
+	}
+	size, err := strconv.Atoi(f[4])
+	if err != nil || size < 0 {
+		return "CLIENT_ERROR bad command line format\r\n", false
+	}
+	data := make([]byte, size+2)
+	if _, err = io.ReadFull(r, data); err != nil || string(data[size:]) != "\r\n" {
+		return "CLIENT_ERROR bad data chunk\r\n", false
+	}
+	data = data[:size]
+	flags, err := strconv.ParseUint(f[2], 10, 32)
+	if err != nil {
+		return "CLIENT_ERROR bad command line format\r\n", true
+	}
+	exp, ok := s.expiration(f[3])
+	if !ok {
+		return "CLIENT_ERROR bad command line format\r\n", true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cur := s.lookup(f[1])
+	switch f[0] {
+	case "add":
+		if cur != nil {
+			return reply(f, "NOT_STORED"), true
+		}
+	case "replace":
+		if cur == nil {
+			return reply(f, "NOT_STORED"), true
+		}
+	case "append", "prepend":
+		if cur == nil {
+			return reply(f, "NOT_STORED"), true
+		}
+		if f[0] == "append" {
+			data = append(append([]byte(nil), cur.value...), data...)
+		} else {
+			data = append(data, cur.value...)
+		}
+		// append and prepend keep the flags and expiration
+		flags, exp = uint64(cur.flags), cur.exp
+	case "cas":
+		if cur == nil {
+			return reply(f, "NOT_FOUND"), true
+		}
+		if unique, err := strconv.ParseUint(f[5], 10, 64); err != nil || unique != cur.cas {
+			return reply(f, "EXISTS"), true
+		}
+	}
+	s.cas++
+	s.items[f[1]] = &item{value: data, flags: uint32(flags), exp: exp, cas: s.cas, la: s.Now()}
+	return reply(f, "STORED"), true
+}
+
+func (s *Server) delete(f []string) string {
+	if len(f) < 1 {
+		return "ERROR\r\n"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.lookup(f[0]) == nil {
+		return reply(f, "NOT_FOUND")
+	}
+	delete(s.items, f[0])
+	return reply(f, "DELETED")
+}
+
+func (s *Server) incr(f []string, decr bool) string {
+	if len(f) < 2 {
+		return "ERROR\r\n"
+	}
+	delta, err := strconv.ParseUint(f[1], 10, 64)
+	if err != nil {
+		return "CLIENT_ERROR invalid numeric delta argument\r\n"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it := s.lookup(f[0])
+	if it == nil {
+		return reply(f, "NOT_FOUND")
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(string(it.value)), 10, 64)
+	if err != nil {
+		return "CLIENT_ERROR cannot increment or decrement non-numeric value\r\n"
+	}
+	if decr {
+		if delta > v {
+			v = 0
+		} else {
+			v -= delta
+		}
+	} else {
+		// wraps around at 64 bits like memcached
+		v += delta
+	}
+	s.cas++
+	it.value = []byte(strconv.FormatUint(v, 10))
+	it.cas = s.cas
+	return reply(f, strconv.FormatUint(v, 10))
+}
+
+func (s *Server) touch(f []string) string {
+	if len(f) < 2 {
+		return "ERROR\r\n"
+	}
+	exp, ok := s.expiration(f[1])
+	if !ok {
+		return "CLIENT_ERROR invalid exptime argument\r\n"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it := s.lookup(f[0])
+	if it == nil {
+		return reply(f, "NOT_FOUND")
+	}
+	it.exp = exp
+	return reply(f, "TOUCHED")
+}
+
+// metaGet method executes `mg <key> <flags>*` with flags v (value), f
+// (client flags), t (remaining TTL), c (CAS), s (size) and k (key).
+func (s *Server) metaGet(f []string) string {
+	if len(f) < 1 {
+		return "CLIENT_ERROR bad command line format\r\n"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	it := s.lookup(f[0])
+	if it == nil {
+		return "EN\r\n"
+	}
+	var ret []string
+	withValue := false
+	for _, fl := range f[1:] {
+		switch fl {
+		case "v":
+			withValue = true
+		case "f":
+			ret = append(ret, "f"+strconv.FormatUint(uint64(it.flags), 10))
+		case "t":
+			ttl := int64(-1)
+			if !it.exp.IsZero() {
+				ttl = int64(it.exp.Sub(s.Now()) / time.Second)
+			}
+			ret = append(ret, "t"+strconv.FormatInt(ttl, 10))
+		case "c":
+			ret = append(ret, "c"+strconv.FormatUint(it.cas, 10))
+		case "s":
+			ret = append(ret, "s"+strconv.Itoa(len(it.value)))
+		case "k":
+			ret = append(ret, "k"+f[0])
+		}
+	}
+	flags := ""
+	if len(ret) > 0 {
+		flags = " " + strings.Join(ret, " ")
+	}
+	if withValue {
+		return "VA " + strconv.Itoa(len(it.value)) + flags + "\r\n" + string(it.value) + "\r\n"
+	}
+	return "HD" + flags + "\r\n"
+}
+
+func (s *Server) metadump() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	keys := make([]string, 0, len(s.items))
+	for k := range s.items {
+		if s.lookup(k) != nil {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	var sb strings.Builder
+	for _, k := range keys {
+		it := s.items[k]
+		exp := int64(-1)
+		if !it.exp.IsZero() {
+			exp = it.exp.Unix()
+		}
+		fmt.Fprintf(&sb, "key=%s exp=%d la=%d cas=%d fetch=no cls=1 size=%d\r\n",
+			url.QueryEscape(k), exp, it.la.Unix(), it.cas, len(k)+len(it.value))
+	}
+	sb.WriteString("END\r\n")
+	return sb.String()
+}
+
+func (s *Server) statsReply() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var bytes int
+	n := 0
+	for k, it := range s.items {
+		if s.lookup(k) != nil {
+			bytes += len(k) + len(it.value)
+			n++
+		}
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "STAT version %s\r\n", Version)
+	fmt.Fprintf(&sb, "STAT curr_items %d\r\n", n)
+	fmt.Fprintf(&sb, "STAT bytes %d\r\n", bytes)
+	fmt.Fprintf(&sb, "STAT limit_maxbytes %d\r\n", 64<<20)
+	fmt.Fprintf(&sb, "STAT curr_connections %d\r\n", len(s.conns))
+	names := make([]string, 0, len(s.stats))
+	for name := range s.stats {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(&sb, "STAT %s %d\r\n", name, s.stats[name])
+	}
+	sb.WriteString("END\r\n")
+	return sb.String()
+}
+
+// reply function returns the response line unless the command is noreply.
+func reply(f []string, resp string) string {
+	if f[len(f)-1] == "noreply" {
+		return ""
+	}
+	return resp + "\r\n"
+}
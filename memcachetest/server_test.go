@@ -0,0 +1,122 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcachetest
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConn struct {
+	t  *testing.T
+	c  net.Conn
+	rw *bufio.ReadWriter
+}
+
+func dial(t *testing.T, s *Server) *testConn {
+	c, err := net.Dial("tcp", s.Addr())
+	assert.Nil(t, err)
+	return &testConn{t: t, c: c, rw: bufio.NewReadWriter(bufio.NewReader(c), bufio.NewWriter(c))}
+}
+
+// do sends the command and returns the response lines up to given
+// terminator line, single line if empty.
+func (tc *testConn) do(cmd, end string) []string {
+	_, _ = tc.rw.WriteString(cmd)
+	assert.Nil(tc.t, tc.rw.Flush())
+	var lines []string
+	for {
+		line, err := tc.rw.ReadString('\n')
+		assert.Nil(tc.t, err)
+		line = strings.TrimRight(line, "\r\n")
+		lines = append(lines, line)
+		if end == "" || line == end || line == "ERROR" {
+			return lines
+		}
+	}
+}
+
+func TestServerStorage(t *testing.T) {
+	s, err := NewServer()
+	assert.Nil(t, err)
+	defer s.Close()
+	c := dial(t, s)
+
+	assert.Equal(t, []string{"STORED"}, c.do("set key1 42 0 6\r\nvalue1\r\n", ""))
+	assert.Equal(t, []string{"VALUE key1 42 6", "value1", "END"}, c.do("get key1 key2\r\n", "END"))
+	assert.Equal(t, []string{"NOT_STORED"}, c.do("add key1 0 0 1\r\nx\r\n", ""))
+	assert.Equal(t, []string{"NOT_STORED"}, c.do("replace key2 0 0 1\r\nx\r\n", ""))
+	assert.Equal(t, []string{"STORED"}, c.do("append key1 0 0 2\r\n-a\r\n", ""))
+	assert.Equal(t, []string{"STORED"}, c.do("prepend key1 0 0 2\r\np-\r\n", ""))
+	assert.Equal(t, []string{"VALUE key1 42 10", "p-value1-a", "END"}, c.do("get key1\r\n", "END"))
+
+	lines := c.do("gets key1\r\n", "END")
+	f := strings.Fields(lines[0])
+	assert.Equal(t, 5, len(f))
+	assert.Equal(t, []string{"EXISTS"}, c.do("cas key1 0 0 1 999\r\nx\r\n", ""))
+	assert.Equal(t, []string{"STORED"}, c.do("cas key1 0 0 1 "+f[4]+"\r\ny\r\n", ""))
+	assert.Equal(t, []string{"EXISTS"}, c.do("cas key1 0 0 1 "+f[4]+"\r\nz\r\n", ""))
+	assert.Equal(t, []string{"NOT_FOUND"}, c.do("cas key2 0 0 1 1\r\nz\r\n", ""))
+
+	assert.Equal(t, []string{"DELETED"}, c.do("delete key1\r\n", ""))
+	assert.Equal(t, []string{"NOT_FOUND"}, c.do("delete key1\r\n", ""))
+	assert.Equal(t, []string{"ERROR"}, c.do("unknown\r\n", ""))
+	assert.Equal(t, "VERSION "+Version, c.do("version\r\n", "")[0])
+}
+
+func TestServerCounters(t *testing.T) {
+	s, err := NewServer()
+	assert.Nil(t, err)
+	defer s.Close()
+	c := dial(t, s)
+
+	assert.Equal(t, []string{"NOT_FOUND"}, c.do("incr n 1\r\n", ""))
+	assert.Equal(t, []string{"STORED"}, c.do("set n 0 0 1\r\n5\r\n", ""))
+	assert.Equal(t, []string{"8"}, c.do("incr n 3\r\n", ""))
+	assert.Equal(t, []string{"0"}, c.do("decr n 10\r\n", ""))
+	assert.Equal(t, []string{"STORED"}, c.do("set s 0 0 1\r\nx\r\n", ""))
+	assert.True(t, strings.HasPrefix(c.do("incr s 1\r\n", "")[0], "CLIENT_ERROR"))
+}
+
+func TestServerExpiration(t *testing.T) {
+	s, err := NewServer()
+	assert.Nil(t, err)
+	defer s.Close()
+	now := time.Now()
+	s.Now = func() time.Time { return now }
+	c := dial(t, s)
+
+	assert.Equal(t, []string{"STORED"}, c.do("set key1 0 10 1\r\na\r\n", ""))
+	assert.Equal(t, []string{"STORED"}, c.do("set key2 0 -1 1\r\nb\r\n", ""))
+	assert.Equal(t, []string{"STORED"}, c.do("set key3 0 0 1\r\nc\r\n", ""))
+	assert.Equal(t, []string{"key1", "key3"}, s.Keys())
+	assert.Equal(t, []string{"HD t10 f0"}, c.do("mg key1 t f\r\n", ""))
+	assert.Equal(t, []string{"HD t-1"}, c.do("mg key3 t\r\n", ""))
+	assert.Equal(t, []string{"EN"}, c.do("mg key2 t\r\n", ""))
+	assert.Equal(t, []string{"VA 1 s1", "c"}, c.do("mg key3 s v\r\n", "c"))
+
+	lines := c.do("lru_crawler metadump all\r\n", "END")
+	assert.Equal(t, 3, len(lines))
+	assert.True(t, strings.HasPrefix(lines[1], "key=key3 exp=-1 "))
+
+	now = now.Add(11 * time.Second)
+	assert.Equal(t, []string{"END"}, c.do("get key1\r\n", "END"))
+	assert.Equal(t, []string{"NOT_FOUND"}, c.do("touch key1 10\r\n", ""))
+	assert.Equal(t, []string{"TOUCHED"}, c.do("touch key3 5\r\n", ""))
+	now = now.Add(5 * time.Second)
+	assert.Equal(t, 0, s.Len())
+
+	assert.Equal(t, []string{"STORED"}, c.do("set key1 0 0 1\r\na\r\n", ""))
+	assert.Equal(t, []string{"OK"}, c.do("flush_all\r\n", ""))
+	assert.Equal(t, 0, s.Len())
+
+	lines = c.do("stats\r\n", "END")
+	assert.Equal(t, "STAT version "+Version, lines[0])
+}
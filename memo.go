@@ -0,0 +1,171 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"aahframe.work/cache"
+)
+
+// MemoKey is the aah context key the request memo is conventionally stored
+// under, e.g. in the request interceptor.
+//
+//	ctx.Set(memcache.MemoKey, memcache.NewMemo())
+//
+//	// in the components rendering the page
+//	memo := ctx.Get(memcache.MemoKey).(*memcache.Memo)
+//	user := memo.Get(userCache, "user:42")
+const MemoKey = "_aahCacheMemo"
+
+// Memo struct memoizes the cache Gets within single request, so the
+// components asking the same key cost one memcache round trip. Misses are
+// memoized too. Writes via the memo update it, writes bypassing the memo are
+// not seen by the request. Memo is safe for concurrent use, it must not
+// outlive the request.
+type Memo struct {
+	mu      sync.Mutex
+	entries map[memoKey]interface{}
+	hits    uint64
+}
+
+// NewMemo method returns the new request memo.
+func NewMemo() *Memo {
+	return &Memo{entries: make(map[memoKey]interface{})}
+}
+
+// WithMemo function returns the copy of given context carrying new request
+// memo, e.g. the aah request context.
+func WithMemo(ctx context.Context) context.Context {
+	return context.WithValue(ctx, memoCtxKey{}, NewMemo())
+}
+
+// MemoFromContext function returns the request memo carried by given
+// context.
+func MemoFromContext(ctx context.Context) (*Memo, bool) {
+	memo, ok := ctx.Value(memoCtxKey{}).(*Memo)
+	return memo, ok
+}
+
+// Get method returns the memoized entry of given cache and key, it reads
+// the cache on first call.
+func (memo *Memo) Get(c cache.Cache, k string) interface{} {
+	mk := memoKeyOf(c, k)
+	if v, found := memo.load(mk); found {
+		return v
+	}
+	v := c.Get(k)
+	memo.store(mk, v)
+	return v
+}
+
+// GetMulti method returns the memoized entries of given cache and keys, the
+// keys not memoized yet are read via single `GetMulti`.
+func (memo *Memo) GetMulti(c Cache, keys []string) map[string]interface{} {
+	result := make(map[string]interface{}, len(keys))
+	var missing []string
+	memo.mu.Lock()
+	for _, k := range keys {
+		if v, found := memo.entries[memoKeyOf(c, k)]; found {
+			memo.hits++
+			if v != nil {
+				result[k] = v
+			}
+		} else {
+			missing = append(missing, k)
+		}
+	}
+	memo.mu.Unlock()
+	if len(missing) == 0 {
+		return result
+	}
+
+	found := c.GetMulti(missing)
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	for _, k := range missing {
+		v := found[k]
+		memo.entries[memoKeyOf(c, k)] = v
+		if v != nil {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// Put method adds the cache entry and memoizes it.
+func (memo *Memo) Put(c cache.Cache, k string, v interface{}, d time.Duration) error {
+	mk := memoKeyOf(c, k)
+	if err := c.Put(k, v, d); err != nil {
+		memo.forget(mk)
+		return err
+	}
+	memo.store(mk, v)
+	return nil
+}
+
+// Delete method deletes the cache entry and memoizes the miss.
+func (memo *Memo) Delete(c cache.Cache, k string) error {
+	mk := memoKeyOf(c, k)
+	if err := c.Delete(k); err != nil {
+		memo.forget(mk)
+		return err
+	}
+	memo.store(mk, nil)
+	return nil
+}
+
+// Hits method returns the number of the Gets served by the memo.
+func (memo *Memo) Hits() uint64 {
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	return memo.hits
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type memoCtxKey struct{}
+
+type memoKey struct {
+	cache  string
+	tenant string
+	key    string
+}
+
+// memoKeyOf function returns the memo key of given cache and key, tenant
+// caches share the cache name so the tenant is part of the key.
+func memoKeyOf(c cache.Cache, k string) memoKey {
+	mk := memoKey{cache: c.Name(), key: k}
+	if t, ok := c.(interface{ Tenant() string }); ok {
+		mk.tenant = t.Tenant()
+	}
+	return mk
+}
+
+func (memo *Memo) load(mk memoKey) (interface{}, bool) {
+	memo.mu.Lock()
+	defer memo.mu.Unlock()
+	v, found := memo.entries[mk]
+	if found {
+		memo.hits++
+	}
+	return v, found
+}
+
+func (memo *Memo) store(mk memoKey, v interface{}) {
+	memo.mu.Lock()
+	memo.entries[mk] = v
+	memo.mu.Unlock()
+}
+
+func (memo *Memo) forget(mk memoKey) {
+	memo.mu.Lock()
+	delete(memo.entries, mk)
+	memo.mu.Unlock()
+}
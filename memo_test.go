@@ -0,0 +1,69 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/cache/provider/memcache/memcachetest"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheMemo(t *testing.T) {
+	mc := memcachetest.NewMock("memocache")
+	mc.ExpectGet("user:42").Returns("jeeva")
+	mc.ExpectGet("user:43")
+	mc.ExpectPut("user:44", "aah", time.Minute)
+	mc.ExpectDelete("user:42")
+	mc.ExpectPut("user:45", "fail", time.Minute).ReturnsError(errors.New("put failed"))
+	mc.ExpectGet("user:45")
+
+	ctx := WithMemo(context.Background())
+	memo, found := MemoFromContext(ctx)
+	assert.True(t, found)
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "jeeva", memo.Get(mc, "user:42"))
+		assert.Nil(t, memo.Get(mc, "user:43"))
+	}
+	assert.Nil(t, memo.Put(mc, "user:44", "aah", time.Minute))
+	assert.Equal(t, "aah", memo.Get(mc, "user:44"))
+	assert.Nil(t, memo.Delete(mc, "user:42"))
+	assert.Nil(t, memo.Get(mc, "user:42"))
+	assert.NotNil(t, memo.Put(mc, "user:45", "fail", time.Minute))
+	assert.Nil(t, memo.Get(mc, "user:45"))
+
+	assert.Nil(t, mc.ExpectationsWereMet())
+	assert.Equal(t, uint64(10), memo.Hits())
+
+	_, found = MemoFromContext(context.Background())
+	assert.False(t, found)
+}
+
+func TestMemcacheMemoGetMulti(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 10}}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "memo"}, p: p, keyPrefix: "memo-",
+		counters: new(counters), chunkLimit: defaultChunkSize}
+
+	memo := NewMemo()
+	assert.Equal(t, 0, len(memo.GetMulti(m, []string{"key1", "key2"})))
+	n := len(p.DryRunOps())
+	assert.True(t, n > 0)
+	assert.Equal(t, 0, len(memo.GetMulti(m, []string{"key1", "key2"})))
+	assert.Nil(t, memo.Get(m, "key1"))
+	assert.Equal(t, n, len(p.DryRunOps()))
+	assert.Equal(t, uint64(3), memo.Hits())
+
+	tc, err := m.ForTenant("tenant1")
+	assert.Nil(t, err)
+	assert.NotEqual(t, memoKeyOf(m, "key1"), memoKeyOf(tc, "key1"))
+}
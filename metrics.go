@@ -0,0 +1,235 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Metrics struct holds the approximate footprint of the cache as observed by
+// this app instance. Memcache cannot segment its statistics by key prefix,
+// so these are counted by the provider on the write path.
+type Metrics struct {
+	ItemsWritten uint64
+	BytesWritten uint64
+	Deletes      uint64
+
+	// OversizeSkipped counts the entries not stored due to `max_value_size`
+	// with skip policy.
+	OversizeSkipped uint64
+
+	// ReadOnlySkipped counts the memcache writes skipped by the `read_only`
+	// cache.
+	ReadOnlySkipped uint64
+
+	// ValueSizes is the distribution of the encoded value sizes, LargeValues
+	// counts the values over `value_size_warn`.
+	ValueSizes  SizeStats
+	LargeValues uint64
+}
+
+// Stats struct holds the snapshot of cache effectiveness counters since the
+// cache was created.
+type Stats struct {
+	Ops     uint64
+	Hits    uint64
+	Misses  uint64
+	Errors  uint64
+	Latency map[string]LatencyStats // keyed by operation name e.g. get, put
+
+	// Touches and TouchFailures count the expiration extensions of the
+	// entries read in slide eviction mode.
+	Touches       uint64
+	TouchFailures uint64
+
+	// DecodeFailures counts the entries read but could not be decoded.
+	DecodeFailures uint64
+
+	// CodecFailures is the catalog of the encode and decode failures per
+	// Go type, most frequent first.
+	CodecFailures []CodecFailure
+
+	// ReadRepairs counts the entries written back from the secondary
+	// cluster.
+	ReadRepairs uint64
+
+	// ChaosDelays counts the memcache calls delayed by the chaos latency,
+	// ChaosLatency is their total added latency.
+	ChaosDelays  uint64
+	ChaosLatency time.Duration
+
+	// DeadlineExceeded counts the context operations cut by the context
+	// deadline, e.g. `GetContext`.
+	DeadlineExceeded uint64
+
+	// Overloaded counts the memcache calls rejected by `max_inflight`.
+	Overloaded uint64
+
+	// WriteQueueDepth is the number of writes waiting in the write-behind
+	// and `PutAsync` queues, WriteQueueFull counts the Puts finding the
+	// queue full and WriteQueueDropped the writes dropped on overflow.
+	WriteQueueDepth   int
+	WriteQueueFull    uint64
+	WriteQueueDropped uint64
+
+	// TouchQueueDepth is the number of slide touches waiting for the flush,
+	// TouchesDropped counts the touches dropped on overflow.
+	TouchQueueDepth int
+	TouchesDropped  uint64
+
+	// Shed counts the memcache calls of the low priority cache skipped by
+	// the load shedding.
+	Shed uint64
+
+	// RefreshKeysDropped counts the keys matching the refresh ahead pattern
+	// not tracked due to `refresh_ahead.max_keys`.
+	RefreshKeysDropped uint64
+
+	// Servers breaks down the single key operations by memcache server
+	// address, see `ServerOps`.
+	Servers map[string]ServerOps
+}
+
+// HitRatio method returns the ratio of hits to lookups, 0 if no lookups yet.
+func (s Stats) HitRatio() float64 {
+	if lookups := s.Hits + s.Misses; lookups > 0 {
+		return float64(s.Hits) / float64(lookups)
+	}
+	return 0
+}
+
+// Stats method returns the snapshot of the cache hit/miss/error counters.
+func (m *memcacheCache) Stats() Stats {
+	return Stats{
+		Ops:     atomic.LoadUint64(&m.counters.ops),
+		Hits:    atomic.LoadUint64(&m.counters.hits),
+		Misses:  atomic.LoadUint64(&m.counters.misses),
+		Errors:  atomic.LoadUint64(&m.counters.errors),
+		Latency: m.counters.latencies(),
+
+		Touches:       atomic.LoadUint64(&m.counters.touches),
+		TouchFailures: atomic.LoadUint64(&m.counters.touchFailures),
+
+		DecodeFailures: atomic.LoadUint64(&m.counters.decodeFailures),
+		CodecFailures:  m.counters.codecFailures(),
+		ReadRepairs:    atomic.LoadUint64(&m.counters.readRepairs),
+
+		ChaosDelays:  atomic.LoadUint64(&m.counters.chaosDelays),
+		ChaosLatency: time.Duration(atomic.LoadInt64(&m.counters.chaosLatency)),
+
+		DeadlineExceeded: atomic.LoadUint64(&m.counters.deadlineExceeded),
+		Overloaded:       atomic.LoadUint64(&m.counters.overloaded),
+
+		WriteQueueDepth:   m.wq.depth() + m.asyncQueue().depth(),
+		WriteQueueFull:    atomic.LoadUint64(&m.counters.writeQueueFull),
+		WriteQueueDropped: atomic.LoadUint64(&m.counters.writeQueueDropped),
+
+		TouchQueueDepth: m.toucher.depth(),
+		TouchesDropped:  atomic.LoadUint64(&m.counters.touchesDropped),
+
+		Shed:    atomic.LoadUint64(&m.counters.shed),
+		Servers: m.counters.serverOps(),
+
+		RefreshKeysDropped: atomic.LoadUint64(&m.counters.refreshKeysDropped),
+	}
+}
+
+// Metrics method returns the snapshot of the cache metrics.
+func (m *memcacheCache) Metrics() Metrics {
+	return Metrics{
+		ItemsWritten: atomic.LoadUint64(&m.counters.itemsWritten),
+		BytesWritten: atomic.LoadUint64(&m.counters.bytesWritten),
+		Deletes:      atomic.LoadUint64(&m.counters.deletes),
+
+		OversizeSkipped: atomic.LoadUint64(&m.counters.oversizeSkipped),
+		ReadOnlySkipped: atomic.LoadUint64(&m.counters.readOnlySkipped),
+
+		ValueSizes:  m.counters.valueSizes.sizes(),
+		LargeValues: atomic.LoadUint64(&m.counters.largeValues),
+	}
+}
+
+// counters struct is allocated separately so its 64-bit fields are
+// aligned for atomic operations on all platforms.
+type counters struct {
+	itemsWritten  uint64
+	bytesWritten  uint64
+	deletes       uint64
+	ops           uint64
+	hits          uint64
+	misses        uint64
+	errors        uint64
+	touches       uint64
+	touchFailures uint64
+
+	oversizeSkipped uint64
+	decodeFailures  uint64
+	readRepairs     uint64
+	readOnlySkipped uint64
+	chaosDelays     uint64
+	chaosLatency    int64
+
+	deadlineExceeded uint64
+	overloaded       uint64
+
+	writeQueueFull     uint64
+	writeQueueDropped  uint64
+	touchesDropped     uint64
+	shed               uint64
+	largeValues        uint64
+	refreshKeysDropped uint64
+	latency            sync.Map // operation name -> *histogram
+	codecs             sync.Map // op and type name -> *codecFailure
+	servers            sync.Map // server address -> *serverCounters
+	valueSizes         histogram
+}
+
+func (c *counters) record(o *operation, err error) {
+	if c == nil {
+		return
+	}
+	atomic.AddUint64(&c.ops, 1)
+	atomic.AddUint64(&c.hits, uint64(o.hits))
+	atomic.AddUint64(&c.misses, uint64(o.misses))
+	if err != nil {
+		atomic.AddUint64(&c.errors, 1)
+	}
+
+	h, found := c.latency.Load(o.name)
+	if !found {
+		h, _ = c.latency.LoadOrStore(o.name, new(histogram))
+	}
+	h.(*histogram).record(o.elapsed())
+}
+
+func (c *counters) latencies() map[string]LatencyStats {
+	result := make(map[string]LatencyStats)
+	c.latency.Range(func(k, v interface{}) bool {
+		result[k.(string)] = v.(*histogram).snapshot()
+		return true
+	})
+	return result
+}
+
+func (c *counters) written(keyLen, valueLen int) {
+	atomic.AddUint64(&c.itemsWritten, 1)
+	atomic.AddUint64(&c.bytesWritten, uint64(keyLen+valueLen))
+}
+
+func (c *counters) sized(valueLen int, large bool) {
+	if c == nil {
+		return
+	}
+	c.valueSizes.add(uint64(valueLen))
+	if large {
+		atomic.AddUint64(&c.largeValues, 1)
+	}
+}
+
+func (c *counters) deleted() {
+	atomic.AddUint64(&c.deletes, 1)
+}
@@ -0,0 +1,55 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheMetrics(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "metricscache", ProviderName: "memcache1"}).(Cache)
+
+	for i := 0; i < 10; i++ {
+		assert.Nil(t, c.Put(fmt.Sprintf("key_%v", i), i, 3*time.Second))
+	}
+	assert.Nil(t, c.Delete("key_0"))
+
+	mt := c.Metrics()
+	assert.Equal(t, uint64(10), mt.ItemsWritten)
+	assert.True(t, mt.BytesWritten > 10*uint64(len("metricscache-key_0")))
+	assert.Equal(t, uint64(1), mt.Deletes)
+
+	assert.Equal(t, 1, c.Get("key_1"))
+	assert.Nil(t, c.Get("key_0"))
+	assert.Nil(t, c.Get("key_x"))
+	st := c.Stats()
+	assert.Equal(t, uint64(14), st.Ops)
+	assert.Equal(t, uint64(1), st.Hits)
+	assert.Equal(t, uint64(2), st.Misses)
+	assert.Equal(t, uint64(0), st.Errors)
+	assert.InDelta(t, 0.333, st.HitRatio(), 0.001)
+	assert.Equal(t, uint64(3), st.Latency["get"].Count)
+	assert.Equal(t, uint64(10), st.Latency["put"].Count)
+	assert.True(t, st.Latency["put"].P99 <= st.Latency["put"].Max)
+
+	c.Flush()
+}
+
+func TestStatsHitRatio(t *testing.T) {
+	assert.Equal(t, float64(0), Stats{}.HitRatio())
+	assert.Equal(t, 0.75, Stats{Hits: 3, Misses: 1}.HitRatio())
+}
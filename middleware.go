@@ -0,0 +1,309 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/log"
+)
+
+// ErrTimeout error is returned by the timeout middleware when the cache
+// operation does not complete in time.
+var ErrTimeout = errors.New("aah/cache: operation timed out")
+
+// Middleware type wraps the `cache.Cache` to compose cross-cutting behavior
+// such as retry, timeout and logging.
+type Middleware func(next cache.Cache) cache.Cache
+
+// Use method adds the middlewares applied to the caches created by the
+// provider afterwards, first middleware is the outermost. Middlewares
+// configured via `middlewares` setting of the cache are applied after these.
+//
+//	cache {
+//	  mycache {
+//	    # built-in middlewares: logging, retry, timeout
+//	    middlewares = ["logging", "retry"]
+//	    middleware {
+//	      retry {
+//	        # default value is 2
+//	        max = 2
+//	        # default value is 10ms
+//	        backoff = "10ms"
+//	      }
+//	      timeout {
+//	        # default value is 1s
+//	        duration = "1s"
+//	      }
+//	    }
+//	  }
+//	}
+//
+// Wrapped cache does not implement memcache `Cache` interface, use
+// `Extended` to obtain it.
+func (p *Provider) Use(mw ...Middleware) {
+	p.middlewares = append(p.middlewares, mw...)
+}
+
+// Extended function returns the memcache `Cache` of given cache by unwrapping
+// the middlewares, false if it is not created by the memcache provider.
+func Extended(c cache.Cache) (Cache, bool) {
+	for c != nil {
+		if mc, ok := c.(Cache); ok {
+			return mc, true
+		}
+		w, ok := c.(interface{ Unwrap() cache.Cache })
+		if !ok {
+			break
+		}
+		c = w.Unwrap()
+	}
+	return nil, false
+}
+
+// Wrapper struct is the base for the middleware implementations, it
+// delegates all the methods to next cache. Middleware embeds it and
+// overrides the methods it is interested in.
+type Wrapper struct {
+	cache.Cache
+}
+
+// Unwrap method returns the wrapped cache.
+func (w Wrapper) Unwrap() cache.Cache {
+	return w.Cache
+}
+
+// RetryMiddleware function returns the middleware which retries the failed
+// Put, GetOrPut, Delete and Flush up to max times with linear backoff. Only
+// transient failures are retried, i.e. `ErrServerUnavailable` and
+// `ErrTimeout`; other errors such as `memcache.ErrNotStored`, `ErrEncode` or
+// `ErrValueTooLarge` are returned right away.
+func RetryMiddleware(max int, backoff time.Duration) Middleware {
+	return func(next cache.Cache) cache.Cache {
+		return &retryCache{Wrapper: Wrapper{next}, max: max, backoff: backoff}
+	}
+}
+
+// TimeoutMiddleware function returns the middleware which bounds each
+// operation with given duration. Get returns nil and the others return
+// `ErrTimeout` on timeout; the abandoned operation still completes in the
+// background.
+func TimeoutMiddleware(d time.Duration) Middleware {
+	return func(next cache.Cache) cache.Cache {
+		return &timeoutCache{Wrapper: Wrapper{next}, d: d}
+	}
+}
+
+// LoggingMiddleware function returns the middleware which logs every
+// operation with its duration at DEBUG level and failures at ERROR level.
+func LoggingMiddleware(logger log.Loggerer) Middleware {
+	return func(next cache.Cache) cache.Cache {
+		return &loggingCache{Wrapper: Wrapper{next}, logger: logger}
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// applyMiddlewares method wraps the cache with the provider and configured
+// middlewares.
+func (m *memcacheCache) applyMiddlewares() (cache.Cache, error) {
+	mws := append([]Middleware(nil), m.p.middlewares...)
+	names, _ := m.p.config().StringList(m.settingKey("middlewares"))
+	for _, name := range names {
+		switch name {
+		case "logging":
+			mws = append(mws, LoggingMiddleware(m.p.logger))
+		case "retry":
+			retry := RetryMiddleware(m.settingInt("middleware.retry.max", 2),
+				parseDuration(m.settingString("middleware.retry.backoff", ""), "10ms"))
+			mws = append(mws, func(next cache.Cache) cache.Cache {
+				r := retry(next).(*retryCache)
+				r.p = m.p
+				return r
+			})
+		case "timeout":
+			mws = append(mws, TimeoutMiddleware(
+				parseDuration(m.settingString("middleware.timeout.duration", ""), "1s")))
+		default:
+			return nil, fmt.Errorf("aah/cache/%s: unknown middleware '%s'", m.Name(), name)
+		}
+	}
+
+	var c cache.Cache = m
+	for i := len(mws) - 1; i >= 0; i-- {
+		c = mws[i](c)
+	}
+	return c, nil
+}
+
+type retryCache struct {
+	Wrapper
+	max     int
+	backoff time.Duration
+	p       *Provider // policy set via SetRetryPolicy overrides, optional
+}
+
+func (r *retryCache) policy() (int, time.Duration) {
+	if r.p != nil {
+		if policy, ok := r.p.RetryPolicy(); ok {
+			return policy.Max, policy.Backoff
+		}
+	}
+	return r.max, r.backoff
+}
+
+func (r *retryCache) do(fn func() error) error {
+	max, backoff := r.policy()
+	err := fn()
+	for i := 0; i < max && transient(err); i++ {
+		time.Sleep(time.Duration(i+1) * backoff)
+		err = fn()
+	}
+	return err
+}
+
+func (r *retryCache) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
+	var ev interface{}
+	err := r.do(func() (err error) {
+		ev, err = r.Cache.GetOrPut(k, v, d)
+		return
+	})
+	return ev, err
+}
+
+func (r *retryCache) Put(k string, v interface{}, d time.Duration) error {
+	return r.do(func() error { return r.Cache.Put(k, v, d) })
+}
+
+func (r *retryCache) Delete(k string) error {
+	return r.do(func() error { return r.Cache.Delete(k) })
+}
+
+func (r *retryCache) Flush() error {
+	return r.do(r.Cache.Flush)
+}
+
+// transient function reports whether the failed operation is worth retrying.
+func transient(err error) bool {
+	return err != nil && (unavailable(err) || errors.Is(err, ErrTimeout))
+}
+
+type timeoutCache struct {
+	Wrapper
+	d time.Duration
+}
+
+func (t *timeoutCache) run(fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-time.After(t.d):
+		return ErrTimeout
+	}
+}
+
+func (t *timeoutCache) Get(k string) interface{} {
+	var v interface{}
+	done := make(chan struct{})
+	go func() {
+		v = t.Cache.Get(k)
+		close(done)
+	}()
+	select {
+	case <-done:
+		return v
+	case <-time.After(t.d):
+		return nil
+	}
+}
+
+func (t *timeoutCache) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
+	type result struct {
+		v   interface{}
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		ev, err := t.Cache.GetOrPut(k, v, d)
+		done <- result{ev, err}
+	}()
+	select {
+	case r := <-done:
+		return r.v, r.err
+	case <-time.After(t.d):
+		return nil, ErrTimeout
+	}
+}
+
+func (t *timeoutCache) Put(k string, v interface{}, d time.Duration) error {
+	return t.run(func() error { return t.Cache.Put(k, v, d) })
+}
+
+func (t *timeoutCache) Delete(k string) error {
+	return t.run(func() error { return t.Cache.Delete(k) })
+}
+
+func (t *timeoutCache) Exists(k string) bool {
+	return t.Get(k) != nil
+}
+
+func (t *timeoutCache) Flush() error {
+	return t.run(t.Cache.Flush)
+}
+
+type loggingCache struct {
+	Wrapper
+	logger log.Loggerer
+}
+
+func (l *loggingCache) log(op, k string, start time.Time, err error) {
+	if err != nil {
+		l.logger.Errorf("aah/cache/%s: %s key(%s) failed after %v: %v", l.Name(), op, logKeyOf(l.Cache, k), time.Since(start), err)
+		return
+	}
+	l.logger.Debugf("aah/cache/%s: %s key(%s) took %v", l.Name(), op, logKeyOf(l.Cache, k), time.Since(start))
+}
+
+func (l *loggingCache) Get(k string) interface{} {
+	start := time.Now()
+	v := l.Cache.Get(k)
+	l.log("get", k, start, nil)
+	return v
+}
+
+func (l *loggingCache) GetOrPut(k string, v interface{}, d time.Duration) (interface{}, error) {
+	start := time.Now()
+	ev, err := l.Cache.GetOrPut(k, v, d)
+	l.log("getorput", k, start, err)
+	return ev, err
+}
+
+func (l *loggingCache) Put(k string, v interface{}, d time.Duration) error {
+	start := time.Now()
+	err := l.Cache.Put(k, v, d)
+	l.log("put", k, start, err)
+	return err
+}
+
+func (l *loggingCache) Delete(k string) error {
+	start := time.Now()
+	err := l.Cache.Delete(k)
+	l.log("delete", k, start, err)
+	return err
+}
+
+func (l *loggingCache) Flush() error {
+	start := time.Now()
+	err := l.Cache.Flush()
+	l.log("flush", "*", start, err)
+	return err
+}
@@ -0,0 +1,136 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheMiddlewares(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		mwcache {
+			middlewares = ["logging", "retry", "timeout"]
+		}
+	}
+`)
+	var calls []string
+	mgr.Provider("memcache1").(*Provider).Use(func(next cache.Cache) cache.Cache {
+		return &recordCache{Wrapper: Wrapper{next}, calls: &calls}
+	})
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "mwcache", ProviderName: "memcache1"}))
+	c := mgr.Cache("mwcache")
+
+	_, ok := c.(Cache)
+	assert.False(t, ok)
+	mc, ok := Extended(c)
+	assert.True(t, ok)
+	assert.Equal(t, "mwcache", mc.Name())
+
+	assert.Nil(t, c.Put("key1", "value1", 3*time.Second))
+	assert.Equal(t, "value1", c.Get("key1"))
+	assert.Equal(t, []string{"put:key1"}, calls)
+
+	c.Flush()
+}
+
+func TestMemcacheMiddlewareUnknown(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		badcache {
+			middlewares = ["unknown"]
+		}
+	}
+`)
+	err := mgr.CreateCache(&cache.Config{Name: "badcache", ProviderName: "memcache1"})
+	assert.Equal(t, "aah/cache/badcache: unknown middleware 'unknown'", err.Error())
+}
+
+func TestMemcacheRetryTimeoutMiddleware(t *testing.T) {
+	f := &failingCache{fails: 2}
+	c := RetryMiddleware(2, time.Millisecond)(f)
+	assert.Nil(t, c.Put("key1", "value1", time.Second))
+	assert.Equal(t, 3, f.attempts)
+
+	f = &failingCache{fails: 5}
+	c = RetryMiddleware(1, time.Millisecond)(f)
+	assert.Equal(t, errFailing, c.Delete("key1"))
+	assert.Equal(t, 2, f.attempts)
+
+	// non-transient error is not retried
+	for _, err := range []error{memcache.ErrNotStored, memcache.ErrCASConflict, ErrValueTooLarge, ErrLocked, ErrEncode} {
+		f = &failingCache{fails: 5, err: err}
+		c = RetryMiddleware(3, time.Millisecond)(f)
+		assert.Equal(t, err, c.Put("key1", "value1", time.Second))
+		assert.Equal(t, 1, f.attempts)
+	}
+	assert.True(t, transient(ErrTimeout))
+	assert.False(t, transient(nil))
+
+	s := &failingCache{delay: 50 * time.Millisecond}
+	c = TimeoutMiddleware(5 * time.Millisecond)(s)
+	assert.Equal(t, ErrTimeout, c.Put("key1", "value1", time.Second))
+	assert.Nil(t, c.Get("key1"))
+	assert.False(t, c.Exists("key1"))
+
+	_, ok := Extended(c)
+	assert.False(t, ok)
+}
+
+type recordCache struct {
+	Wrapper
+	calls *[]string
+}
+
+func (r *recordCache) Put(k string, v interface{}, d time.Duration) error {
+	*r.calls = append(*r.calls, "put:"+k)
+	return r.Cache.Put(k, v, d)
+}
+
+var errFailing error = &net.OpError{Op: "dial", Err: errors.New("failing")}
+
+type failingCache struct {
+	cache.Cache
+	fails    int
+	attempts int
+	delay    time.Duration
+	err      error
+}
+
+func (f *failingCache) try() error {
+	time.Sleep(f.delay)
+	f.attempts++
+	if f.attempts <= f.fails {
+		if f.err != nil {
+			return f.err
+		}
+		return errFailing
+	}
+	return nil
+}
+
+func (f *failingCache) Get(k string) interface{} {
+	time.Sleep(f.delay)
+	return "value"
+}
+
+func (f *failingCache) Put(k string, v interface{}, d time.Duration) error { return f.try() }
+
+func (f *failingCache) Delete(k string) error { return f.try() }
@@ -0,0 +1,101 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import "sync"
+
+// MigrationFunc type upgrades the cache value decoded from the entry written
+// with older schema version by one version. Returning error makes the read a
+// cache miss.
+type MigrationFunc func(v interface{}) (interface{}, error)
+
+// RegisterSchema method sets the current schema version of given value type,
+// the version is stored in the envelope of the entries of the type. Version
+// starts at 1, entries written before the registration are version 0.
+//
+//	p.RegisterSchema(User{}, 2)
+//	p.RegisterMigration(User{}, 1, func(v interface{}) (interface{}, error) {
+//		u := v.(User)
+//		u.DisplayName = u.FirstName + " " + u.LastName
+//		return u, nil
+//	})
+func (p *Provider) RegisterSchema(v interface{}, version int) {
+	p.schemas.mu.Lock()
+	defer p.schemas.mu.Unlock()
+	p.schemas.init()
+	p.schemas.versions[schemaHint(v)] = version
+}
+
+// RegisterMigration method registers the migration func of given value type
+// from given schema version to the next one. Entries older than the current
+// schema version are upgraded on read through the chain of migrations, if
+// the chain is incomplete the read is a cache miss.
+func (p *Provider) RegisterMigration(v interface{}, from int, fn MigrationFunc) {
+	p.schemas.mu.Lock()
+	defer p.schemas.mu.Unlock()
+	p.schemas.init()
+	p.schemas.migrations[migrationKey{schemaHint(v), from}] = fn
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type migrationKey struct {
+	schema  string
+	version int
+}
+
+type schemaRegistry struct {
+	mu         sync.RWMutex
+	versions   map[string]int
+	migrations map[migrationKey]MigrationFunc
+}
+
+func (sr *schemaRegistry) init() {
+	if sr.versions == nil {
+		sr.versions = make(map[string]int)
+		sr.migrations = make(map[migrationKey]MigrationFunc)
+	}
+}
+
+// version method returns the current schema version of given schema hint.
+func (sr *schemaRegistry) version(schema string) int {
+	sr.mu.RLock()
+	defer sr.mu.RUnlock()
+	return sr.versions[schema]
+}
+
+// migrate method upgrades the entry value to the current schema version, it
+// reports false if the entry cannot be upgraded.
+func (sr *schemaRegistry) migrate(e *entry) bool {
+	sr.mu.RLock()
+	current, found := sr.versions[e.S]
+	if !found || e.SV >= current {
+		sr.mu.RUnlock()
+		// entry of newer schema version is written by newer app version
+		return e.SV == current
+	}
+	chain := make([]MigrationFunc, 0, current-e.SV)
+	for ver := e.SV; ver < current; ver++ {
+		fn, found := sr.migrations[migrationKey{e.S, ver}]
+		if !found {
+			sr.mu.RUnlock()
+			return false
+		}
+		chain = append(chain, fn)
+	}
+	sr.mu.RUnlock()
+
+	v := e.V
+	for _, fn := range chain {
+		var err error
+		if v, err = fn(v); err != nil {
+			return false
+		}
+	}
+	e.V, e.SV = v, current
+	return true
+}
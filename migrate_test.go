@@ -0,0 +1,56 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type profile struct {
+	Name    string
+	Display string
+}
+
+func TestMemcacheSchemaMigration(t *testing.T) {
+	p := &Provider{}
+
+	// unregistered type
+	assert.True(t, p.schemas.migrate(&entry{S: "memcache.profile", V: profile{}}))
+	assert.False(t, p.schemas.migrate(&entry{S: "memcache.profile", SV: 1, V: profile{}}))
+
+	p.RegisterSchema(profile{}, 3)
+	assert.Equal(t, 3, p.schemas.version("memcache.profile"))
+	p.RegisterMigration(profile{}, 1, func(v interface{}) (interface{}, error) {
+		pr := v.(profile)
+		pr.Display = "Mr. " + pr.Name
+		return pr, nil
+	})
+
+	// incomplete chain
+	e := &entry{S: "memcache.profile", SV: 0, V: profile{Name: "Jeeva"}}
+	assert.False(t, p.schemas.migrate(e))
+
+	p.RegisterMigration(profile{}, 2, func(v interface{}) (interface{}, error) {
+		pr := v.(profile)
+		pr.Display += "!"
+		return pr, nil
+	})
+	e = &entry{S: "memcache.profile", SV: 1, V: profile{Name: "Jeeva"}}
+	assert.True(t, p.schemas.migrate(e))
+	assert.Equal(t, profile{Name: "Jeeva", Display: "Mr. Jeeva!"}, e.V)
+	assert.Equal(t, 3, e.SV)
+
+	// current and newer versions
+	assert.True(t, p.schemas.migrate(&entry{S: "memcache.profile", SV: 3}))
+	assert.False(t, p.schemas.migrate(&entry{S: "memcache.profile", SV: 4}))
+
+	p.RegisterMigration(profile{}, 2, func(v interface{}) (interface{}, error) {
+		return nil, errors.New("cannot migrate")
+	})
+	assert.False(t, p.schemas.migrate(&entry{S: "memcache.profile", SV: 2, V: profile{}}))
+}
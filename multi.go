@@ -0,0 +1,108 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// GetMulti method returns the cached entries for given keys in single round
+// trip per memcache server. Keys not found in the cache store are passed to
+// the batch loader registered via `SetBatchLoader`, loaded values are stored
+// into cache store and merged into result.
+//
+// Keys are split by their memcache server and the multigets of the servers
+// are issued concurrently.
+func (m *memcacheCache) GetMulti(keys []string) map[string]interface{} {
+	result, _ := m.getMulti(context.Background(), keys)
+	return result
+}
+
+// getMulti method returns the cached entries for given keys along with the
+// timing of each memcache server.
+func (m *memcacheCache) getMulti(ctx context.Context, keys []string) (map[string]interface{}, map[string]ServerTiming) {
+	result := make(map[string]interface{}, len(keys))
+	if len(keys) == 0 {
+		return result, map[string]ServerTiming{}
+	}
+
+	if m.gens != nil && m.tenant == "" {
+		m.gens.warm(keys)
+	}
+	pkeys := make([]string, len(keys))
+	for i, k := range keys {
+		pkeys[i] = m.key(k)
+		m.hot.observe(k)
+	}
+	o := m.beginContext(ctx, "getmulti", "")
+	items, timings, err := m.shardedGet(ctx, pkeys)
+	if err != nil {
+		m.p.logError(fmt.Errorf("aah/cache/%s: getmulti %w", m.Name(), err))
+	}
+	for _, pk := range pkeys {
+		if item, found := items[pk]; found {
+			o.hit(len(item.Value))
+		} else {
+			o.miss()
+		}
+	}
+	o.end(err)
+
+	var missing []string
+	for i, k := range keys {
+		if item, found := items[pkeys[i]]; found {
+			if e, ok := m.decodeItem(k, item); ok {
+				if !e.notFound {
+					result[k] = e.V
+				}
+				continue
+			}
+		}
+		missing = append(missing, k)
+	}
+
+	if len(missing) > 0 && ctx.Err() == nil {
+		m.loadMissing(missing, result)
+	}
+	return result, timings
+}
+
+// SetBatchLoader method registers the batch loader func, which is invoked by
+// `GetMulti` with only the missing keys. Loaded values are stored into cache
+// store with given expiration.
+func (m *memcacheCache) SetBatchLoader(d time.Duration, fn BatchLoaderFunc) {
+	m.loaderMu.Lock()
+	m.batchTTL, m.batchFn = d, fn
+	m.loaderMu.Unlock()
+}
+
+func (m *memcacheCache) loadMissing(keys []string, result map[string]interface{}) {
+	m.loaderMu.RLock()
+	d, fn := m.batchTTL, m.batchFn
+	m.loaderMu.RUnlock()
+	if fn == nil {
+		return
+	}
+
+	values, err := fn(keys)
+	if err != nil {
+		m.p.logError(fmt.Errorf("aah/cache/%s: batch loader %w", m.Name(), err))
+		return
+	}
+	for _, k := range keys {
+		v, found := values[k]
+		if found {
+			result[k] = v
+			err = m.Put(k, v, d)
+		} else {
+			err = m.PutNotFound(k)
+		}
+		if err != nil {
+			m.p.logError(err)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheGetMultiBatchLoader(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "multicache", ProviderName: "memcache1"}).(Cache)
+
+	assert.Nil(t, c.Put("key1", 1, 3*time.Second))
+	assert.Nil(t, c.Put("key2", 2, 3*time.Second))
+
+	keys := []string{"key1", "key2", "key3", "key4", "key5"}
+	assert.Equal(t, map[string]interface{}{"key1": 1, "key2": 2}, c.GetMulti(keys))
+
+	var requested []string
+	c.SetBatchLoader(3*time.Second, func(keys []string) (map[string]interface{}, error) {
+		requested = keys
+		result := make(map[string]interface{})
+		for i, k := range keys[:2] {
+			result[k] = fmt.Sprintf("loaded %d", i)
+		}
+		return result, nil
+	})
+
+	result := c.GetMulti(keys)
+	assert.Equal(t, []string{"key3", "key4", "key5"}, requested)
+	assert.Equal(t, 4, len(result))
+	assert.Equal(t, "loaded 0", result["key3"])
+	assert.Equal(t, "loaded 0", c.Get("key3"))
+	assert.Nil(t, result["key5"])
+
+	c.Flush()
+}
@@ -0,0 +1,64 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import "strings"
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// namespace method returns the key namespace of the provider, it is folded
+// into key prefix of every cache to prevent key collisions across the
+// applications and environments sharing the memcache servers. Template
+// supports `{app}` - aah application name and `{env}` - active environment
+// profile.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//	    namespace {
+//	      # default value is false
+//	      enable = true
+//	      # default value is "{app}-{env}-"
+//	      template = "{app}-{env}-"
+//	    }
+//	  }
+//	}
+func (p *Provider) namespace() string {
+	cfgPrefix := "cache." + p.name + ".namespace."
+	if !p.config().BoolDefault(cfgPrefix+"enable", false) {
+		return ""
+	}
+	return strings.NewReplacer(
+		"{app}", p.config().StringDefault("name", "aah"),
+		"{env}", p.config().StringDefault("env.active", "dev"),
+	).Replace(p.config().StringDefault(cfgPrefix+"template", "{app}-{env}-"))
+}
+
+// prefix method returns the key prefix of the cache from `key_prefix`
+// template. Template supports `{namespace}` - provider namespace, `{name}` -
+// cache name, `{delim}` - `key_delimiter` and `{version}` - `key_version`.
+// Empty template disables the prefix, for interop with the keys written by
+// other systems.
+//
+//	cache {
+//	  mycache {
+//	    # default value is "{namespace}{name}{delim}"
+//	    key_prefix = "{namespace}{name}{delim}{version}{delim}"
+//	    # default value is "-"
+//	    key_delimiter = ":"
+//	    # bump the version to abandon all the existing entries, default value is ""
+//	    key_version = "v2"
+//	  }
+//	}
+func (m *memcacheCache) prefix() string {
+	return strings.NewReplacer(
+		"{namespace}", m.p.ns,
+		"{name}", m.cfg.Name,
+		"{delim}", m.settingString("key_delimiter", "-"),
+		"{version}", m.settingString("key_version", ""),
+	).Replace(m.settingString("key_prefix", "{namespace}{name}{delim}"))
+}
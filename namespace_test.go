@@ -0,0 +1,72 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheNamespace(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	name = "myapp"
+	env {
+		active = "staging"
+	}
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+			namespace {
+				enable = true
+			}
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "nscache", ProviderName: "memcache1"}))
+	c := mgr.Cache("nscache").(Cache)
+	assert.Equal(t, "myapp-staging-nscache-", c.(*memcacheCache).keyPrefix)
+
+	assert.Nil(t, c.Put("key1", "value1", 3*time.Second))
+	assert.Equal(t, "value1", c.Get("key1"))
+	_, err := mgr.Provider("memcache1").(*Provider).Client().Get("nscache-key1")
+	assert.NotNil(t, err)
+	assert.Nil(t, c.Delete("key1"))
+}
+
+func TestMemcacheKeyPrefix(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		versioned {
+			key_prefix = "{name}{delim}{version}{delim}"
+			key_delimiter = ":"
+			key_version = "v2"
+		}
+		noprefix {
+			key_prefix = ""
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "versioned", ProviderName: "memcache1"}))
+	assert.Equal(t, "versioned:v2:", mgr.Cache("versioned").(*memcacheCache).keyPrefix)
+
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "defaultprefix", ProviderName: "memcache1"}))
+	assert.Equal(t, "defaultprefix-", mgr.Cache("defaultprefix").(*memcacheCache).keyPrefix)
+
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "noprefix", ProviderName: "memcache1"}))
+	c := mgr.Cache("noprefix")
+	assert.Nil(t, c.Put("interop-key1", "value1", 3*time.Second))
+	item, err := mgr.Provider("memcache1").(*Provider).Client().Get("interop-key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "interop-key1", item.Key)
+	assert.Nil(t, c.Delete("interop-key1"))
+}
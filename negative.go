@@ -0,0 +1,59 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// flagNotFound flag marks the memcache item as negative entry, it carries no
+// value bytes.
+const flagNotFound uint32 = 1 << 0
+
+// PutNotFound method caches the "not found" result for given key with the
+// cache `negative_ttl`, so repeated lookups of nonexistent value skip the
+// origin. Get returns nil and Fetch returns `ErrNotFound` for such key.
+//
+//	cache {
+//	  mycache {
+//	    # default value is 30s
+//	    negative_ttl = "10s"
+//	  }
+//	}
+//
+// Negative entries are not stored in the interop mode.
+func (m *memcacheCache) PutNotFound(k string) error {
+	if m.negativeTTL <= 0 || m.interop != interopNone {
+		return nil
+	}
+	item := &memcache.Item{
+		Key:        m.key(k),
+		Value:      []byte{},
+		Flags:      flagNotFound,
+		Expiration: expirationOf(int32(m.negativeTTL.Seconds())),
+	}
+	if err := m.client().Set(item); err != nil {
+		return m.opError("put", k, item.Key, nil, err)
+	}
+	m.counters.written(len(item.Key), 0)
+	if m.registry != nil {
+		m.registry.add(k, m.negativeTTL)
+	}
+	return nil
+}
+
+// putLoaded method stores the loader result, `ErrNotFound` is stored as
+// negative entry and other errors are returned as-is.
+func (m *memcacheCache) putLoaded(k string, v interface{}, err error, d time.Duration) error {
+	if err == ErrNotFound {
+		return m.PutNotFound(k)
+	}
+	if err != nil {
+		return err
+	}
+	return m.Put(k, v, d)
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheNegativeCaching(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		negativecache {
+			negative_ttl = "2s"
+		}
+	}
+`, &cache.Config{Name: "negativecache", ProviderName: "memcache1"}).(Cache)
+
+	calls := 0
+	loader := func() (interface{}, error) {
+		calls++
+		return nil, ErrNotFound
+	}
+	for i := 0; i < 3; i++ {
+		v, err := c.Fetch("user:404", 3*time.Second, loader)
+		assert.Nil(t, v)
+		assert.Equal(t, ErrNotFound, err)
+	}
+	assert.Equal(t, 1, calls)
+	assert.Nil(t, c.Get("user:404"))
+
+	v, err := c.GetOrPut("user:404", "now exists", 3*time.Second)
+	assert.Nil(t, err)
+	assert.Equal(t, "now exists", v)
+	assert.Equal(t, "now exists", c.Get("user:404"))
+
+	assert.Nil(t, c.PutNotFound("user:405"))
+	time.Sleep(3 * time.Second)
+	v, err = c.Fetch("user:405", 3*time.Second, func() (interface{}, error) { return "found", nil })
+	assert.Nil(t, err)
+	assert.Equal(t, "found", v)
+
+	c.Flush()
+}
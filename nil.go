@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+// flagNil marks the entry of the cached `Nil` value.
+const flagNil uint32 = 1 << 16
+
+// Nil is the cached nil value, it is distinguishable from the miss. Put it
+// to cache the "no such record" result with the regular expiration and put
+// options, Get returns `Nil` for such key.
+//
+//	if err := c.Put("user:"+id, memcache.Nil, time.Hour); err != nil {
+//		// ...
+//	}
+//	switch v := c.Get("user:" + id); v {
+//	case nil:
+//		// miss
+//	case memcache.Nil:
+//		// cached nil
+//	}
+//
+// `GetInto`, `Query` and `Typed` caches set the zero value for it. Nil is
+// not supported in the interop mode. Unlike `PutNotFound`, `Fetch` returns
+// `Nil` as the value rather than `ErrNotFound`.
+var Nil = nilValue{}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type nilValue struct{}
+
+func (nilValue) String() string {
+	return "<nil>"
+}
+
+// encodeNil function returns the entry stored for the cached `Nil`, the
+// envelope carries no value and the item is flagged with `flagNil`.
+func encodeNil(e *entry) *entry {
+	ne := *e
+	ne.V = nil
+	ne.flags |= flagNil
+	return &ne
+}
+
+// decodeNil function restores the cached `Nil` of the decoded entry.
+func decodeNil(e *entry) {
+	if e.flags&flagNil == flagNil {
+		e.V = Nil
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheCachedNil(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "nil"}, p: p, keyPrefix: "nil-",
+		counters: new(counters), chunkLimit: defaultChunkSize}
+
+	e := newEntry(Nil, time.Minute)
+	item, err := m.encodeEntry("user:1", e)
+	assert.Nil(t, err)
+	assert.Equal(t, flagNil, item.Flags&flagNil)
+	assert.Equal(t, Nil, e.V)
+
+	de, found := m.decodeItem("user:1", item)
+	assert.True(t, found)
+	assert.Equal(t, Nil, de.V)
+	assert.Equal(t, int32(60), de.D)
+	assert.Equal(t, "<nil>", Nil.String())
+
+	// plain nil value stays nil
+	item, err = m.encodeEntry("user:2", newEntry(nil, time.Minute))
+	assert.Nil(t, err)
+	de, found = m.decodeItem("user:2", item)
+	assert.True(t, found)
+	assert.Nil(t, de.V)
+
+	var u queryUser
+	u.Name = "jeeva"
+	assert.Nil(t, assign(reflect.ValueOf(&u).Elem(), Nil))
+	assert.Equal(t, queryUser{}, u)
+
+	m.interop = interopJSON
+	_, err = m.encodeEntry("user:1", newEntry(Nil, time.Minute))
+	assert.True(t, errors.Is(err, ErrEncode))
+}
@@ -0,0 +1,115 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"time"
+)
+
+// operation struct tracks single cache operation, its outcome is reported to
+// the instrumentation of the cache when it ends.
+type operation struct {
+	m        *memcacheCache
+	name     string
+	key      string
+	start    time.Time
+	duration time.Duration
+	span     Span
+	hits     int
+	misses   int
+	bytes    int
+	addr     string
+}
+
+// begin method starts tracking of the operation for given key, empty key
+// denotes multi-key operation.
+func (m *memcacheCache) begin(name, k string) *operation {
+	return m.beginContext(context.Background(), name, k)
+}
+
+// beginContext method is `begin` within given context, the span of the
+// operation is started with it. Server of the key is resolved before the
+// memcache call, so the outcome is recorded against the server picked for
+// it even if the server list changes meanwhile.
+func (m *memcacheCache) beginContext(ctx context.Context, name, k string) *operation {
+	o := &operation{m: m, name: name, key: k, start: time.Now(), span: m.startSpan(ctx, name, k)}
+	if k != "" {
+		o.addr = m.p.serverAddr(m.key(k))
+	}
+	return o
+}
+
+func (o *operation) hit(bytes int) {
+	o.hits++
+	o.bytes += bytes
+}
+
+func (o *operation) miss() {
+	o.misses++
+}
+
+func (o *operation) written(bytes int) {
+	o.bytes += bytes
+}
+
+// elapsed method returns the duration of the operation so far, it is fixed
+// once the operation ends.
+func (o *operation) elapsed() time.Duration {
+	if o.duration == 0 {
+		return time.Since(o.start)
+	}
+	return o.duration
+}
+
+// end method completes the operation, cache miss is not an error.
+func (o *operation) end(err error) {
+	o.duration = time.Since(o.start)
+	if o.hits+o.misses > 0 {
+		o.span.SetAttribute(AttrHit, o.misses == 0)
+	}
+	if o.key == "" {
+		o.span.SetAttribute(AttrKeyCount, o.hits+o.misses)
+	}
+	o.span.SetAttribute(AttrBytes, o.bytes)
+	o.span.End(err)
+
+	if t := o.m.slowThreshold; t > 0 && o.duration >= t {
+		o.logSlow()
+	}
+
+	o.m.counters.record(o, err)
+	o.m.counters.serverOp(o.server(), o.duration, err)
+	o.m.vars.record(o, err)
+	o.m.p.statsd.record(o, err)
+	o.trackServer(err)
+	o.fireHooks(err)
+}
+
+// server method returns the memcache server address of the operation key
+// resolved at begin, empty for multi-key operation or if the server is
+// unknown.
+func (o *operation) server() string {
+	return o.addr
+}
+
+// logSlow method logs the operation exceeded `slow_op_threshold` at WARN
+// level, key is logged as hash.
+//
+//	cache {
+//	  mycache {
+//	    # default value is 0s, disabled
+//	    slow_op_threshold = "50ms"
+//	  }
+//	}
+func (o *operation) logSlow() {
+	if o.key == "" {
+		o.m.p.logger.Warnf("aah/cache/%s: slow %s of %d keys took %v, bytes(%d)",
+			o.m.Name(), o.name, o.hits+o.misses, o.duration, o.bytes)
+		return
+	}
+	o.m.p.logger.Warnf("aah/cache/%s: slow %s key_hash(%s) server(%s) took %v, bytes(%d)",
+		o.m.Name(), o.name, hashKey(o.key), o.server(), o.duration, o.bytes)
+}
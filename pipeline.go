@@ -0,0 +1,177 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrPipelineExecuted error is returned by `Exec` of the pipeline already
+// executed.
+var ErrPipelineExecuted = errors.New("aah/cache: pipeline already executed")
+
+// Pipeline type queues the cache operations of different kinds and executes
+// them with minimal round trips, e.g. for the composite page rendering.
+//
+//	pl := c.Pipeline()
+//	user := pl.Get("user:42")
+//	pl.Put("visit:42", time.Now(), time.Hour)
+//	pl.Delete("cart:42")
+//	if err := pl.Exec(); err != nil {
+//		// ...
+//	}
+//	if v, found := user.Value(); found {
+//		// ...
+//	}
+//
+// Gets of the batch are read via single `GetMulti`, i.e. one round trip per
+// memcache server. Puts and Deletes run concurrently across the servers, in
+// the queued order per server. Operations of the same key are applied in the
+// queued order, the later one starts the next batch.
+type Pipeline struct {
+	m        *memcacheCache
+	ops      []*PipelineResult
+	executed bool
+}
+
+// PipelineResult type holds the result of the pipeline operation, it is
+// available after `Exec`.
+type PipelineResult struct {
+	op    string
+	key   string
+	v     interface{}
+	d     time.Duration
+	found bool
+	err   error
+}
+
+// Pipeline method returns the new pipeline of the cache.
+func (m *memcacheCache) Pipeline() *Pipeline {
+	return &Pipeline{m: m}
+}
+
+// Get method queues the read of given key.
+func (pl *Pipeline) Get(k string) *PipelineResult {
+	return pl.add(&PipelineResult{op: "get", key: k})
+}
+
+// Put method queues the write of given key.
+func (pl *Pipeline) Put(k string, v interface{}, d time.Duration) *PipelineResult {
+	return pl.add(&PipelineResult{op: "put", key: k, v: v, d: d})
+}
+
+// Delete method queues the delete of given key.
+func (pl *Pipeline) Delete(k string) *PipelineResult {
+	return pl.add(&PipelineResult{op: "delete", key: k})
+}
+
+// Len method returns the number of queued operations.
+func (pl *Pipeline) Len() int {
+	return len(pl.ops)
+}
+
+// Exec method executes the queued operations, it returns the first error of
+// the Puts and Deletes. Errors of each operation are available in its result.
+func (pl *Pipeline) Exec() error {
+	if pl.executed {
+		return ErrPipelineExecuted
+	}
+	pl.executed = true
+	var (
+		firstErr error
+		batch    []*PipelineResult
+		keys     = make(map[string]bool)
+	)
+	flush := func() {
+		if err := pl.exec(batch); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		batch = batch[:0]
+		keys = make(map[string]bool)
+	}
+	for _, r := range pl.ops {
+		if keys[r.key] {
+			flush()
+		}
+		keys[r.key] = true
+		batch = append(batch, r)
+	}
+	if len(batch) > 0 {
+		flush()
+	}
+	return firstErr
+}
+
+// Value method returns the value read by the Get, false if it was not found.
+func (r *PipelineResult) Value() (interface{}, bool) {
+	return r.v, r.found
+}
+
+// Err method returns the error of the Put or Delete.
+func (r *PipelineResult) Err() error {
+	return r.err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func (pl *Pipeline) add(r *PipelineResult) *PipelineResult {
+	pl.ops = append(pl.ops, r)
+	return r
+}
+
+// exec method executes the batch of distinct keys.
+func (pl *Pipeline) exec(batch []*PipelineResult) error {
+	m := pl.m
+	var (
+		gets    []*PipelineResult
+		servers = make(map[string][]*PipelineResult)
+	)
+	for _, r := range batch {
+		if r.op == "get" {
+			gets = append(gets, r)
+			continue
+		}
+		addr := m.p.serverAddr(m.key(r.key))
+		servers[addr] = append(servers[addr], r)
+	}
+
+	var wg sync.WaitGroup
+	for _, ops := range servers {
+		wg.Add(1)
+		go func(ops []*PipelineResult) {
+			defer wg.Done()
+			for _, r := range ops {
+				if r.op == "put" {
+					r.err = m.Put(r.key, r.v, r.d)
+				} else {
+					r.err = m.Delete(r.key)
+				}
+			}
+		}(ops)
+	}
+
+	if len(gets) > 0 {
+		keys := make([]string, len(gets))
+		for i, r := range gets {
+			keys[i] = r.key
+		}
+		result := m.GetMulti(keys)
+		for _, r := range gets {
+			r.v, r.found = result[r.key]
+		}
+	}
+	wg.Wait()
+
+	for _, r := range batch {
+		if r.err != nil {
+			return r.err
+		}
+	}
+	return nil
+}
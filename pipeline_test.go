@@ -0,0 +1,56 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"sort"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcachePipeline(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 100}}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "pipeline"}, p: p, keyPrefix: "pipeline-",
+		counters: new(counters), chunkLimit: defaultChunkSize}
+
+	pl := m.Pipeline()
+	r1 := pl.Get("key1")
+	r2 := pl.Put("key2", "value2", time.Minute)
+	r3 := pl.Get("key3")
+	r4 := pl.Get("key2")
+	r5 := pl.Delete("key1")
+	assert.Equal(t, 5, pl.Len())
+	assert.Nil(t, pl.Exec())
+	assert.Equal(t, ErrPipelineExecuted, pl.Exec())
+
+	_, found := r1.Value()
+	assert.False(t, found)
+	assert.Nil(t, r2.Err())
+	_, found = r3.Value()
+	assert.False(t, found)
+	_, found = r4.Value()
+	assert.False(t, found)
+	assert.Nil(t, r5.Err())
+
+	// key2 read starts the second batch, after the write of the first one
+	var ops []string
+	for _, op := range p.DryRunOps() {
+		ops = append(ops, op.Op+" "+op.Key)
+	}
+	assert.Equal(t, 5, len(ops))
+	sort.Strings(ops[:3])
+	sort.Strings(ops[3:])
+	assert.Equal(t, []string{"get pipeline-key1", "get pipeline-key3", "set pipeline-key2",
+		"delete pipeline-key1", "get pipeline-key2"}, ops)
+
+	assert.Nil(t, m.Pipeline().Exec())
+}
@@ -0,0 +1,235 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ErrPrefixInvalidationDisabled error is returned by `InvalidatePrefix` when
+// the cache does not have `prefix_invalidation` enabled.
+var ErrPrefixInvalidationDisabled = errors.New("aah/cache: prefix invalidation is not enabled")
+
+// InvalidatePrefix method invalidates all the cache entries whose key starts
+// with given prefix, e.g. `user:42:`, without enumerating them.
+//
+// Every delimiter terminated prefix of a key has generation number stored in
+// memcache and the generations are folded into the effective memcache key.
+// Invalidation increments the generation, so the old entries are no longer
+// addressable and memcache evicts them eventually. Other app instances
+// observe the new generation within `staleness` window.
+//
+//	cache {
+//	  mycache {
+//	    prefix_invalidation {
+//	      enable = true
+//	      # default value is ":"
+//	      delimiter = ":"
+//	      # default value is 1s
+//	      staleness = "1s"
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) InvalidatePrefix(prefix string) error {
+	if m.gens == nil {
+		return ErrPrefixInvalidationDisabled
+	}
+	prefix = m.tenant + prefix
+	if !strings.HasSuffix(prefix, m.gens.delim) {
+		prefix += m.gens.delim
+	}
+
+	gk := m.gens.genKey(prefix)
+	n, err := m.client().Increment(gk, 1)
+	if err == memcache.ErrCacheMiss {
+		var gen string
+		if gen, err = m.gens.initGen(gk); err == nil {
+			n, err = strconv.ParseUint(gen, 10, 64)
+		}
+	}
+	if err != nil {
+		err = m.opError("invalidateprefix", prefix, gk, nil, err)
+		m.audit("invalidateprefix", prefix, -1, err)
+		return err
+	}
+
+	m.gens.set(prefix, strconv.FormatUint(n, 10))
+	m.audit("invalidateprefix", prefix, -1, nil)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// generations
+//______________________________________________________________________________
+
+// generations keeps the locally known prefix generation numbers, each value
+// is reused for `staleness` duration before it is fetched again.
+type generations struct {
+	m         *memcacheCache
+	delim     string
+	staleness time.Duration
+
+	mu    sync.Mutex
+	local map[string]genValue
+}
+
+type genValue struct {
+	v  string
+	at time.Time
+}
+
+func newGenerations(m *memcacheCache) *generations {
+	g := &generations{
+		m:         m,
+		delim:     m.settingString("prefix_invalidation.delimiter", ":"),
+		staleness: parseDuration(m.settingString("prefix_invalidation.staleness", ""), "1s"),
+		local:     make(map[string]genValue),
+	}
+	if g.delim == "" {
+		g.delim = ":"
+	}
+	return g
+}
+
+// key method returns memcache key for given key with prefix generations
+// folded in, e.g. `user:42:profile` becomes `<cache-prefix>user:42:profile@<g1>.<g2>`.
+func (g *generations) key(k string) string {
+	ps := g.prefixes(k)
+	if len(ps) == 0 {
+		return g.m.keyPrefix + k
+	}
+	gens := g.resolve(ps)
+	var sb strings.Builder
+	sb.WriteString(g.m.keyPrefix)
+	sb.WriteString(k)
+	for i, p := range ps {
+		if i == 0 {
+			sb.WriteByte('@')
+		} else {
+			sb.WriteByte('.')
+		}
+		sb.WriteString(gens[p])
+	}
+	return sb.String()
+}
+
+// warm method resolves the prefix generations of given keys in single round
+// trip, so subsequent key computations are served locally.
+func (g *generations) warm(keys []string) {
+	seen := make(map[string]bool)
+	var ps []string
+	for _, k := range keys {
+		for _, p := range g.prefixes(k) {
+			if !seen[p] {
+				seen[p] = true
+				ps = append(ps, p)
+			}
+		}
+	}
+	if len(ps) > 0 {
+		g.resolve(ps)
+	}
+}
+
+// prefixes method returns delimiter terminated proper prefixes of given key.
+func (g *generations) prefixes(k string) []string {
+	var ps []string
+	for i := 0; i < len(k); {
+		j := strings.Index(k[i:], g.delim)
+		if j == -1 {
+			break
+		}
+		i += j + len(g.delim)
+		if i < len(k) {
+			ps = append(ps, k[:i])
+		}
+	}
+	return ps
+}
+
+// resolve method returns generation of given prefixes, stale or unknown
+// generations are fetched from memcache in single round trip. Generation
+// absent in memcache is initialized with current time, so an evicted
+// generation never resurrects the entries of its earlier life.
+func (g *generations) resolve(prefixes []string) map[string]string {
+	gens := make(map[string]string, len(prefixes))
+	var fetch []string
+	now := time.Now()
+	g.mu.Lock()
+	for _, p := range prefixes {
+		if gv, found := g.local[p]; found && now.Sub(gv.at) < g.staleness {
+			gens[p] = gv.v
+			continue
+		}
+		fetch = append(fetch, g.genKey(p))
+	}
+	g.mu.Unlock()
+	if len(fetch) == 0 {
+		return gens
+	}
+
+	items, err := g.m.client().GetMulti(fetch)
+	if err != nil {
+		g.m.p.logError(fmt.Errorf("aah/cache/%s: prefix generations %w", g.m.Name(), err))
+	}
+	for _, p := range prefixes {
+		if _, found := gens[p]; found {
+			continue
+		}
+		gk := g.genKey(p)
+		if item, found := items[gk]; found {
+			gens[p] = string(item.Value)
+		} else if err == nil {
+			v, ierr := g.initGen(gk)
+			if ierr != nil {
+				g.m.p.logError(fmt.Errorf("aah/cache/%s: prefix(%s) %w", g.m.Name(), p, ierr))
+			}
+			gens[p] = v
+		}
+		if gens[p] == "" {
+			// generation "0" is never allocated, entries written with it
+			// cannot be read once memcache is reachable again.
+			gens[p] = "0"
+			continue
+		}
+		g.set(p, gens[p])
+	}
+	return gens
+}
+
+// initGen method creates the generation key with current time, if other
+// instance won the race, its value is returned.
+func (g *generations) initGen(gk string) (string, error) {
+	v := strconv.FormatInt(time.Now().UnixNano(), 10)
+	err := g.m.client().Add(&memcache.Item{Key: gk, Value: []byte(v)})
+	if err == memcache.ErrNotStored {
+		item, gerr := g.m.client().Get(gk)
+		if gerr != nil {
+			return "", gerr
+		}
+		return string(item.Value), nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return v, nil
+}
+
+func (g *generations) set(prefix, v string) {
+	g.mu.Lock()
+	g.local[prefix] = genValue{v: v, at: time.Now()}
+	g.mu.Unlock()
+}
+
+func (g *generations) genKey(prefix string) string {
+	return g.m.keyPrefix + "__gen__" + prefix
+}
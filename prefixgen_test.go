@@ -0,0 +1,63 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheInvalidatePrefix(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		prefixcache {
+			prefix_invalidation {
+				enable = true
+			}
+		}
+	}
+`, &cache.Config{Name: "prefixcache", ProviderName: "memcache1"}).(Cache)
+
+	assert.Nil(t, c.Put("user:42:profile", "profile 42", 5*time.Second))
+	assert.Nil(t, c.Put("user:42:orders", "orders 42", 5*time.Second))
+	assert.Nil(t, c.Put("user:43:profile", "profile 43", 5*time.Second))
+
+	assert.Nil(t, c.InvalidatePrefix("user:42"))
+	assert.Nil(t, c.Get("user:42:profile"))
+	assert.Nil(t, c.Get("user:42:orders"))
+	assert.Equal(t, "profile 43", c.Get("user:43:profile"))
+
+	assert.Nil(t, c.InvalidatePrefix("user:"))
+	assert.Nil(t, c.Get("user:43:profile"))
+
+	c2 := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "noprefixcache", ProviderName: "memcache1"}).(Cache)
+	assert.Equal(t, ErrPrefixInvalidationDisabled, c2.InvalidatePrefix("user:"))
+
+	c.Flush()
+}
+
+func TestGenerationsPrefixes(t *testing.T) {
+	g := &generations{delim: ":"}
+	assert.Equal(t, []string{"user:", "user:42:"}, g.prefixes("user:42:profile"))
+	assert.Nil(t, g.prefixes("user"))
+	assert.Equal(t, []string{"user:"}, g.prefixes("user:42:"))
+
+	g = &generations{delim: "::"}
+	assert.Equal(t, []string{"a::"}, g.prefixes("a::b"))
+}
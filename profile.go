@@ -0,0 +1,129 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"runtime/pprof"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Profiler label names set on the memcache calls.
+const (
+	LabelCache = "cache"
+	LabelOp    = "op"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// profileOps are the memcache operations labeled, see `FaultPolicy.Ops`.
+var profileOps = []string{"get", "set", "add", "replace", "cas", "delete", "incr", "decr", "touch", "flush_all"}
+
+// profileLabels holds the pprof label contexts of the cache per memcache
+// operation, they are built once so labeling does not allocate per call.
+//
+// With `pprof_labels` enabled the goroutine is labeled with `cache` and `op`
+// during the memcache calls, so the CPU and goroutine profiles attribute
+// the time to the caches and operations. The labels of the goroutine set by
+// the app are cleared after the call.
+//
+//	cache {
+//	  mycache {
+//	    # default value is false
+//	    pprof_labels = true
+//	  }
+//	}
+type profileLabels map[string]context.Context
+
+func newProfileLabels(m *memcacheCache) profileLabels {
+	if !m.settingBool("pprof_labels", false) {
+		return nil
+	}
+	pl := make(profileLabels, len(profileOps))
+	for _, op := range profileOps {
+		pl[op] = pprof.WithLabels(context.Background(), pprof.Labels(LabelCache, m.Name(), LabelOp, op))
+	}
+	return pl
+}
+
+// label method sets the labels of given operation on the goroutine, it
+// returns the func clearing them.
+func (pl profileLabels) label(op string) func() {
+	pprof.SetGoroutineLabels(pl[op])
+	return clearLabels
+}
+
+func clearLabels() {
+	pprof.SetGoroutineLabels(context.Background())
+}
+
+// profileClient labels the goroutine during the memcache calls.
+type profileClient struct {
+	client
+	pl profileLabels
+}
+
+func (pc profileClient) Get(key string) (*memcache.Item, error) {
+	defer pc.pl.label("get")()
+	return pc.client.Get(key)
+}
+
+func (pc profileClient) metaGet(key, flags string) (string, error) {
+	defer pc.pl.label("get")()
+	return pc.client.metaGet(key, flags)
+}
+
+func (pc profileClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	defer pc.pl.label("get")()
+	return pc.client.GetMulti(keys)
+}
+
+func (pc profileClient) Set(item *memcache.Item) error {
+	defer pc.pl.label("set")()
+	return pc.client.Set(item)
+}
+
+func (pc profileClient) Add(item *memcache.Item) error {
+	defer pc.pl.label("add")()
+	return pc.client.Add(item)
+}
+
+func (pc profileClient) Replace(item *memcache.Item) error {
+	defer pc.pl.label("replace")()
+	return pc.client.Replace(item)
+}
+
+func (pc profileClient) CompareAndSwap(item *memcache.Item) error {
+	defer pc.pl.label("cas")()
+	return pc.client.CompareAndSwap(item)
+}
+
+func (pc profileClient) Delete(key string) error {
+	defer pc.pl.label("delete")()
+	return pc.client.Delete(key)
+}
+
+func (pc profileClient) Increment(key string, delta uint64) (uint64, error) {
+	defer pc.pl.label("incr")()
+	return pc.client.Increment(key, delta)
+}
+
+func (pc profileClient) Decrement(key string, delta uint64) (uint64, error) {
+	defer pc.pl.label("decr")()
+	return pc.client.Decrement(key, delta)
+}
+
+func (pc profileClient) Touch(key string, seconds int32) error {
+	defer pc.pl.label("touch")()
+	return pc.client.Touch(key, seconds)
+}
+
+func (pc profileClient) FlushAll() error {
+	defer pc.pl.label("flush_all")()
+	return pc.client.FlushAll()
+}
@@ -0,0 +1,43 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"runtime/pprof"
+	"testing"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheProfileLabels(t *testing.T) {
+	p := &Provider{name: "memcache1"}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "profiled"}, p: p}
+	assert.Nil(t, newProfileLabels(m))
+	_, ok := m.client().(profileClient)
+	assert.False(t, ok)
+
+	m.labels = profileLabels{}
+	for _, op := range profileOps {
+		m.labels[op] = pprof.WithLabels(context.Background(), pprof.Labels(LabelCache, m.Name(), LabelOp, op))
+	}
+	v, _ := pprof.Label(m.labels["cas"], LabelOp)
+	assert.Equal(t, "cas", v)
+	v, _ = pprof.Label(m.labels["cas"], LabelCache)
+	assert.Equal(t, "profiled", v)
+
+	pc, ok := m.client().(profileClient)
+	assert.True(t, ok)
+	pc.client = &dryRunClient{max: 10}
+	_, err := pc.Get("key1")
+	assert.Equal(t, memcache.ErrCacheMiss, err)
+	assert.Nil(t, pc.Set(&memcache.Item{Key: "key1"}))
+	assert.Nil(t, pc.FlushAll())
+	assert.Equal(t, 3, len(pc.client.(*dryRunClient).ops))
+}
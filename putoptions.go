@@ -0,0 +1,112 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"time"
+
+	"aahframe.work/cache"
+)
+
+// FlagUserMask is the memcache item flag bits available to the aah user via
+// `WithFlags`, lower bits are reserved by the provider.
+const FlagUserMask uint32 = 0xFFFF0000
+
+// Provider flag bits of the memcache item.
+const (
+	flagSlide   uint32 = 1 << 1
+	flagStatic  uint32 = 1 << 2
+	flagNoTouch uint32 = 1 << 3
+)
+
+// PutOption type customizes the single `PutWith` call.
+type PutOption func(o *putOptions) error
+
+// WithEvictionMode option overrides the cache eviction mode for the entry.
+func WithEvictionMode(mode cache.EvictionMode) PutOption {
+	return func(o *putOptions) error {
+		o.flags &^= flagSlide | flagStatic
+		if mode == cache.EvictionModeSlide {
+			o.flags |= flagSlide
+		} else {
+			o.flags |= flagStatic
+		}
+		return nil
+	}
+}
+
+// WithFlags option sets the custom flag bits of the memcache item, bits must
+// be within `FlagUserMask`.
+func WithFlags(bits uint32) PutOption {
+	return func(o *putOptions) error {
+		if bits&^FlagUserMask != 0 {
+			return fmt.Errorf("aah/cache: flags %#x outside of FlagUserMask", bits)
+		}
+		o.flags |= bits
+		return nil
+	}
+}
+
+// NoTouch option marks the entry to be not touched on read, its expiration
+// is not extended even if the cache is in slide eviction mode.
+func NoTouch() PutOption {
+	return func(o *putOptions) error {
+		o.flags |= flagNoTouch
+		return nil
+	}
+}
+
+// PutWith method adds the cache entry with specified expiration and given
+// options, so the policy could be adjusted per entry.
+//
+//	err := mc.PutWith("token:1234", token, 15*time.Minute,
+//		memcache.WithEvictionMode(cache.EvictionModeTime),
+//		memcache.NoTouch())
+func (m *memcacheCache) PutWith(k string, v interface{}, d time.Duration, opts ...PutOption) error {
+	var o putOptions
+	for _, opt := range opts {
+		if err := opt(&o); err != nil {
+			return m.opError("put", k, "", nil, err)
+		}
+	}
+	e, err := m.newEntry(k, v, d)
+	if err != nil {
+		return err
+	}
+	e.flags = o.flags
+	if len(o.subjects) > 0 {
+		if err = m.tagSubjects(k, o.subjects, e.D); err != nil {
+			return err
+		}
+	}
+	if m.wq != nil {
+		return m.putBehind(k, e, nil)
+	}
+	return m.storeEntry(m.client().Set, k, e)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type putOptions struct {
+	flags    uint32
+	subjects []string
+}
+
+// touchOnRead method reports whether the item read to be touched to extend
+// its expiration.
+func (m *memcacheCache) touchOnRead(flags uint32) bool {
+	switch {
+	case flags&flagNoTouch == flagNoTouch:
+		return false
+	case flags&flagSlide == flagSlide:
+		return true
+	case flags&flagStatic == flagStatic:
+		return false
+	}
+	return m.cfg.EvictionMode == cache.EvictionModeSlide
+}
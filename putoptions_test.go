@@ -0,0 +1,55 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcachePutOptions(t *testing.T) {
+	var o putOptions
+	assert.Nil(t, WithEvictionMode(cache.EvictionModeSlide)(&o))
+	assert.Equal(t, flagSlide, o.flags)
+	assert.Nil(t, WithEvictionMode(cache.EvictionModeTime)(&o))
+	assert.Equal(t, flagStatic, o.flags)
+	assert.Nil(t, NoTouch()(&o))
+	assert.Nil(t, WithFlags(1<<16)(&o))
+	assert.Equal(t, flagStatic|flagNoTouch|1<<16, o.flags)
+	assert.NotNil(t, WithFlags(1<<2)(&o))
+
+	slide := &memcacheCache{cfg: &cache.Config{EvictionMode: cache.EvictionModeSlide}}
+	assert.True(t, slide.touchOnRead(0))
+	assert.False(t, slide.touchOnRead(flagStatic))
+	assert.False(t, slide.touchOnRead(flagSlide|flagNoTouch))
+
+	static := &memcacheCache{cfg: &cache.Config{EvictionMode: cache.EvictionModeTime}}
+	assert.False(t, static.touchOnRead(0))
+	assert.True(t, static.touchOnRead(flagSlide))
+}
+
+func TestMemcachePutWith(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "putwith", ProviderName: "memcache1", EvictionMode: cache.EvictionModeSlide}).(Cache)
+
+	assert.Nil(t, c.PutWith("key1", "value1", 3*time.Second, NoTouch(), WithFlags(1<<20)))
+	assert.Equal(t, "value1", c.Get("key1"))
+
+	item, err := c.(*memcacheCache).p.Client().Get("putwith-key1")
+	assert.Nil(t, err)
+	assert.Equal(t, flagNoTouch|1<<20, item.Flags&^flagFormatMask)
+
+	assert.NotNil(t, c.PutWith("key2", "value2", 3*time.Second, WithFlags(1)))
+	assert.Nil(t, c.Delete("key1"))
+}
@@ -0,0 +1,103 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"encoding/gob"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// gobTypes holds the types registered with gob by `Query`.
+var gobTypes sync.Map
+
+// Query method is the cache-aside helper for the query results, it returns
+// the cached result of given key into dst, which must be a non-nil pointer.
+// On miss it calls given func and caches its result, concurrent callers of
+// the same key share one call. Func returning `ErrNotFound` is cached as
+// negative entry, Query returns `ErrNotFound` until it expires.
+//
+// Concrete type of the result is registered with gob, so that it does not
+// need to be registered upfront.
+//
+//	var user User
+//	err := c.Query("user:"+id, 10*time.Minute, &user, func() (interface{}, error) {
+//		return repo.FindUser(id)
+//	})
+func (m *memcacheCache) Query(k string, d time.Duration, dst interface{}, fn LoaderFunc) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("aah/cache/%s: query destination must be a non-nil pointer, got %T", m.Name(), dst)
+	}
+	v, err := m.Fetch(k, d, func() (interface{}, error) {
+		v, err := fn()
+		if err == nil {
+			registerGob(v)
+		}
+		return v, err
+	})
+	if err != nil {
+		return err
+	}
+	if err = assign(rv.Elem(), v); err != nil {
+		return m.opError("get", k, m.key(k), ErrDecode, err)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// registerGob function registers the concrete type of given value with gob
+// once, type already registered by the app under another name is kept.
+func registerGob(v interface{}) {
+	if v == nil {
+		return
+	}
+	t := reflect.TypeOf(v)
+	if _, loaded := gobTypes.LoadOrStore(t, true); loaded {
+		return
+	}
+	defer func() { _ = recover() }()
+	gob.Register(v)
+}
+
+// assign function sets given value into dst, pointer values are
+// dereferenced, named types of same kind and numbers are converted.
+func assign(dst reflect.Value, v interface{}) error {
+	if v == nil || v == Nil {
+		dst.Set(reflect.Zero(dst.Type()))
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	for {
+		switch {
+		case rv.Type().AssignableTo(dst.Type()):
+			dst.Set(rv)
+			return nil
+		case rv.Kind() == reflect.Ptr && !rv.IsNil():
+			rv = rv.Elem()
+		case convertible(rv, dst.Type()):
+			dst.Set(rv.Convert(dst.Type()))
+			return nil
+		default:
+			return fmt.Errorf("cannot assign %T to %s", v, dst.Type())
+		}
+	}
+}
+
+func convertible(v reflect.Value, t reflect.Type) bool {
+	if !v.Type().ConvertibleTo(t) {
+		return false
+	}
+	return v.Kind() == t.Kind() || (isNumber(v.Kind()) && isNumber(t.Kind()))
+}
+
+func isNumber(k reflect.Kind) bool {
+	return k >= reflect.Int && k <= reflect.Float64
+}
@@ -0,0 +1,68 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type queryUser struct {
+	ID   int
+	Name string
+}
+
+func TestMemcacheQuery(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "query", ProviderName: "memcache1"}).(Cache)
+
+	calls := 0
+	find := func() (interface{}, error) {
+		calls++
+		return &queryUser{ID: 1, Name: "jeeva"}, nil
+	}
+	for i := 0; i < 2; i++ {
+		var u queryUser
+		assert.Nil(t, c.Query("user:1", time.Minute, &u, find))
+		assert.Equal(t, queryUser{ID: 1, Name: "jeeva"}, u)
+	}
+	assert.Equal(t, 1, calls)
+
+	var u queryUser
+	err := c.Query("user:2", time.Minute, &u, func() (interface{}, error) { return nil, ErrNotFound })
+	assert.Equal(t, ErrNotFound, err)
+	assert.NotNil(t, c.Query("user:1", time.Minute, u, find))
+
+	_ = c.DeleteMulti([]string{"user:1", "user:2"})
+}
+
+func TestMemcacheQueryAssign(t *testing.T) {
+	type userID int
+
+	var id userID
+	assert.Nil(t, assign(reflect.ValueOf(&id).Elem(), 42))
+	assert.Equal(t, userID(42), id)
+	assert.Nil(t, assign(reflect.ValueOf(&id).Elem(), float64(7)))
+	assert.Equal(t, userID(7), id)
+
+	var s string
+	assert.NotNil(t, assign(reflect.ValueOf(&s).Elem(), 65))
+	u := &queryUser{ID: 3}
+	var dst queryUser
+	assert.Nil(t, assign(reflect.ValueOf(&dst).Elem(), u))
+	assert.Equal(t, 3, dst.ID)
+	assert.Nil(t, assign(reflect.ValueOf(&dst).Elem(), nil))
+	assert.Equal(t, queryUser{}, dst)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// rateKeyPrefix is prepended to the rate limiter window key, so that
+// counters do not collide with the cache entries.
+const rateKeyPrefix = "~rate:"
+
+// RateLimiter type throttles the events per key with the fixed window
+// counters in the memcache, limits are shared across the app instances.
+type RateLimiter struct {
+	m    *memcacheCache
+	name string
+}
+
+// RateLimiter method returns the rate limiter of given name, name scopes
+// the keys of limiter, e.g. "login" or "api".
+func (m *memcacheCache) RateLimiter(name string) *RateLimiter {
+	return &RateLimiter{m: m, name: name}
+}
+
+// Allow method reports whether the event of given key is within given limit
+// for the current window. Windows are aligned to the multiples of window
+// duration, counter of the window expires with it.
+//
+//	allowed, err := c.RateLimiter("login").Allow(clientIP, 5, time.Minute)
+func (r *RateLimiter) Allow(k string, limit int, window time.Duration) (bool, error) {
+	n, err := r.Count(k, window)
+	if err != nil {
+		return false, err
+	}
+	return n <= uint64(limit), nil
+}
+
+// Count method increments and returns the event count of the current window
+// for given key.
+func (r *RateLimiter) Count(k string, window time.Duration) (uint64, error) {
+	if window < time.Second {
+		window = time.Second
+	}
+	slot := time.Now().UnixNano() / int64(window)
+	rk := rateKeyPrefix + r.name + ":" + k + ":" + strconv.FormatInt(slot, 10)
+	// window slot ends before expiration, extra second covers clock skew
+	return r.m.incr(rk, 1, int32(window/time.Second)+1)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// incr method increments the counter of given key by delta, negative delta
+// decrements it, memcache counters do not go below zero. Counter is created
+// with given expiration if it does not exist; memcache fails the incr of
+// absent key, so "0" is added first and the incr is retried.
+func (m *memcacheCache) incr(k string, delta int64, exp int32) (uint64, error) {
+	o := m.begin("incr", k)
+	mk := m.key(k)
+	n, err := m.incrOnce(mk, delta)
+	if err == memcache.ErrCacheMiss {
+		err = m.client().Add(&memcache.Item{Key: mk, Value: []byte("0"), Expiration: expirationOf(exp)})
+		if err == nil || err == memcache.ErrNotStored {
+			// created by this or another caller meanwhile
+			n, err = m.incrOnce(mk, delta)
+		}
+	}
+	o.end(err)
+	if err != nil {
+		return 0, m.opError("incr", k, mk, nil, err)
+	}
+	return n, nil
+}
+
+func (m *memcacheCache) incrOnce(mk string, delta int64) (uint64, error) {
+	if delta < 0 {
+		return m.client().Decrement(mk, uint64(-delta))
+	}
+	return m.client().Increment(mk, uint64(delta))
+}
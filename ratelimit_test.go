@@ -0,0 +1,40 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheRateLimiter(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "ratelimit", ProviderName: "memcache1"}).(Cache)
+
+	rl := c.RateLimiter("login")
+	for i := 0; i < 3; i++ {
+		allowed, err := rl.Allow("10.0.0.1", 3, time.Hour)
+		assert.Nil(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, err := rl.Allow("10.0.0.1", 3, time.Hour)
+	assert.Nil(t, err)
+	assert.False(t, allowed)
+
+	// keys and limiters are independent
+	allowed, _ = rl.Allow("10.0.0.2", 3, time.Hour)
+	assert.True(t, allowed)
+	allowed, _ = c.RateLimiter("api").Allow("10.0.0.1", 3, time.Hour)
+	assert.True(t, allowed)
+}
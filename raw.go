@@ -0,0 +1,80 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"encoding/binary"
+	"errors"
+	"unsafe"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Raw payload flag bits of the memcache item.
+const (
+	flagRaw       uint32 = 1 << 6
+	flagRawString uint32 = 1 << 7
+)
+
+var errRawHeader = &corruptError{errors.New("invalid raw payload header")}
+
+// rawHeaderSize is the size of raw payload header, it holds the entry
+// expiration in seconds.
+const rawHeaderSize = 4
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// encodeRaw method returns the raw payload of []byte and string values, so
+// they are stored without gob envelope. Get returns a view over the fetched
+// item buffer without decode or copy; item buffer is allocated per read and
+// not retained by the provider, so the caller owns the returned []byte and
+// may modify it. Raw entries carry no write timestamp, soft TTL and early
+// recompute do not apply to them.
+//
+//	cache {
+//	  mycache {
+//	    # default value is false
+//	    raw_values = true
+//	  }
+//	}
+func (m *memcacheCache) encodeRaw(e *entry) ([]byte, uint32, bool) {
+	var b []byte
+	flags := flagRaw
+	switch v := e.V.(type) {
+	case []byte:
+		b = v
+	case string:
+		b = []byte(v)
+		flags |= flagRawString
+	default:
+		return nil, 0, false
+	}
+	value := make([]byte, rawHeaderSize+len(b))
+	binary.BigEndian.PutUint32(value, uint32(e.D))
+	copy(value[rawHeaderSize:], b)
+	return value, flags, true
+}
+
+// decodeRaw function returns the entry of the raw payload.
+func decodeRaw(value []byte, flags uint32) (*entry, bool) {
+	if len(value) < rawHeaderSize {
+		return nil, false
+	}
+	e := &entry{D: int32(binary.BigEndian.Uint32(value)), flags: flags}
+	b := value[rawHeaderSize:]
+	if flags&flagRawString == flagRawString {
+		// buffer is not modified after this point, it is safe to share
+		e.V = *(*string)(unsafe.Pointer(&b))
+	} else {
+		e.V = b
+	}
+	return e, true
+}
+
+func isRaw(v *memcache.Item) bool {
+	return v.Flags&flagRaw == flagRaw
+}
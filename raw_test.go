@@ -0,0 +1,39 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheRawPayload(t *testing.T) {
+	m := &memcacheCache{}
+
+	value, flags, ok := m.encodeRaw(&entry{D: 60, V: []byte("raw bytes")})
+	assert.True(t, ok)
+	assert.Equal(t, flagRaw, flags)
+	e, ok := decodeRaw(value, flags)
+	assert.True(t, ok)
+	assert.Equal(t, int32(60), e.D)
+	assert.Equal(t, []byte("raw bytes"), e.V)
+
+	// view over the fetched buffer
+	e.V.([]byte)[0] = 'R'
+	assert.Equal(t, byte('R'), value[rawHeaderSize])
+
+	value, flags, ok = m.encodeRaw(&entry{D: 30, V: "raw string"})
+	assert.True(t, ok)
+	assert.Equal(t, flagRaw|flagRawString, flags)
+	e, ok = decodeRaw(value, flags)
+	assert.True(t, ok)
+	assert.Equal(t, "raw string", e.V)
+
+	_, _, ok = m.encodeRaw(&entry{V: 10})
+	assert.False(t, ok)
+	_, ok = decodeRaw([]byte{0, 1}, flagRaw)
+	assert.False(t, ok)
+}
@@ -0,0 +1,69 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"sync/atomic"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ErrReadOnly error is returned by the counter and conditional write
+// operations, e.g. `Counter`, `RateLimiter`, `Lock` and `PutCAS`, of the
+// read-only cache since they cannot be skipped.
+var ErrReadOnly = errors.New("aah/cache: cache is read-only")
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// readOnlyClient skips the memcache writes of the read-only cache, e.g. a
+// canary deployment pointed at the production memcache. Skipped writes
+// succeed and are counted in `Metrics.ReadOnlySkipped`, except add and
+// compare-and-swap which fail with `ErrReadOnly`, their success would
+// report e.g. a lock held without storing it.
+//
+//	cache {
+//	  mycache {
+//	    # default value is false
+//	    read_only = true
+//	  }
+//	}
+type readOnlyClient struct {
+	client
+	m *memcacheCache
+}
+
+func (r readOnlyClient) skip() error {
+	atomic.AddUint64(&r.m.counters.readOnlySkipped, 1)
+	return nil
+}
+
+func (r readOnlyClient) Set(*memcache.Item) error     { return r.skip() }
+func (r readOnlyClient) Replace(*memcache.Item) error { return r.skip() }
+func (r readOnlyClient) Delete(string) error          { return r.skip() }
+func (r readOnlyClient) Touch(string, int32) error    { return r.skip() }
+func (r readOnlyClient) FlushAll() error              { return r.skip() }
+
+func (r readOnlyClient) Add(*memcache.Item) error {
+	_ = r.skip()
+	return ErrReadOnly
+}
+
+func (r readOnlyClient) CompareAndSwap(*memcache.Item) error {
+	_ = r.skip()
+	return ErrReadOnly
+}
+
+func (r readOnlyClient) Increment(string, uint64) (uint64, error) {
+	_ = r.skip()
+	return 0, ErrReadOnly
+}
+
+func (r readOnlyClient) Decrement(string, uint64) (uint64, error) {
+	_ = r.skip()
+	return 0, ErrReadOnly
+}
@@ -0,0 +1,87 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheReadOnly(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		readonlycache {
+			read_only = true
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "readonlycache", ProviderName: "memcache1"}))
+	c := mgr.Cache("readonlycache").(Cache)
+	p := mgr.Provider("memcache1").(*Provider)
+	assert.Nil(t, p.Client().Set(&memcache.Item{Key: "readonlycache-key1", Value: []byte("raw")}))
+
+	assert.Nil(t, c.Put("key2", "value2", time.Minute))
+	assert.Nil(t, c.Get("key2"))
+	assert.Nil(t, c.Delete("key1"))
+	assert.Nil(t, c.Flush())
+	_, err := p.Client().Get("readonlycache-key1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), c.Metrics().ReadOnlySkipped)
+
+	_, err = c.Counter("hits").Add(1)
+	assert.True(t, errors.Is(err, ErrReadOnly))
+
+	_, err = c.Lock("job1", time.Minute)
+	assert.True(t, errors.Is(err, ErrReadOnly))
+
+	assert.Nil(t, p.Client().Delete("readonlycache-key1"))
+}
+
+func TestMemcacheReadOnlyClient(t *testing.T) {
+	m := &memcacheCache{counters: new(counters), readOnly: true, p: &Provider{}}
+	r, ok := m.client().(readOnlyClient)
+	assert.True(t, ok)
+
+	item := &memcache.Item{Key: "key1"}
+	assert.Nil(t, r.Set(item))
+	assert.Equal(t, ErrReadOnly, r.Add(item))
+	assert.Nil(t, r.Replace(item))
+	assert.Equal(t, ErrReadOnly, r.CompareAndSwap(item))
+	assert.Nil(t, r.Delete("key1"))
+	assert.Nil(t, r.Touch("key1", 10))
+	assert.Nil(t, r.FlushAll())
+	_, err := r.Increment("key1", 1)
+	assert.Equal(t, ErrReadOnly, err)
+	_, err = r.Decrement("key1", 1)
+	assert.Equal(t, ErrReadOnly, err)
+	assert.Equal(t, uint64(9), m.counters.readOnlySkipped)
+
+	m.readOnly = false
+	_, ok = m.client().(readOnlyClient)
+	assert.False(t, ok)
+}
+
+func TestMemcacheReadOnlyLock(t *testing.T) {
+	m := newBenchCache()
+	m.p.dryRun = nil
+	m.readOnly = true
+
+	_, err := m.tryLock(lockKeyPrefix, "job1", time.Minute)
+	assert.True(t, errors.Is(err, ErrReadOnly))
+	err = m.WithLock("job1", time.Minute, func() error {
+		t.Error("func called without the lock")
+		return nil
+	})
+	assert.True(t, errors.Is(err, ErrReadOnly))
+}
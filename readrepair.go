@@ -0,0 +1,124 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// SetSecondary method enables the dual read for the caches of the provider,
+// Get missed on this provider cluster is read from the secondary cluster,
+// e.g. the old cluster during migration. Entries hit on the secondary are
+// written back to this cluster in the background with their remaining TTL,
+// so the clusters converge over time. Chunked entries are not dual read.
+//
+// Read repairs are rate limited and queued, repairs are dropped when the
+// queue is full.
+//
+//	cache {
+//	  memcache1 {
+//	    read_repair {
+//	      # repairs per second; default value is 100
+//	      rate = 100
+//
+//	      # default value is 1000
+//	      max_pending = 1000
+//	    }
+//	  }
+//	}
+func (p *Provider) SetSecondary(secondary *Provider) {
+	cfgPrefix := "cache." + p.name + ".read_repair."
+	p.secondary = secondary
+	p.repairer = &repairer{
+		p:       p,
+		rate:    p.config().IntDefault(cfgPrefix+"rate", 100),
+		pending: make(chan repairItem, p.config().IntDefault(cfgPrefix+"max_pending", 1000)),
+	}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type repairItem struct {
+	m    *memcacheCache
+	item *memcache.Item
+}
+
+// repairer writes the entries hit on the secondary cluster back to the
+// primary cluster in the background.
+type repairer struct {
+	p       *Provider
+	rate    int
+	once    sync.Once
+	pending chan repairItem
+}
+
+// readSecondary method reads the entry of given key from the secondary
+// cluster on primary miss, hit is queued for read repair.
+func (m *memcacheCache) readSecondary(k, mk string) (*entry, bool) {
+	v, err := m.p.secondary.Client().Get(mk)
+	if err != nil || v.Flags&flagChunked == flagChunked {
+		return nil, false
+	}
+	e, found := m.decodeItem(k, v)
+	if !found {
+		return nil, false
+	}
+	if exp, ok := e.remaining(time.Now()); ok {
+		v.Expiration = expirationOf(exp)
+		m.p.repairer.enqueue(repairItem{m: m, item: v})
+	}
+	return e, true
+}
+
+// remaining method returns the remaining expiration of the entry in seconds,
+// false if it is expired.
+func (e *entry) remaining(now time.Time) (int32, bool) {
+	if e.D <= 0 {
+		return 0, true
+	}
+	if e.T == 0 {
+		// write time is unknown, e.g. raw entries
+		return e.D, true
+	}
+	left := time.Unix(0, e.T).Add(time.Duration(e.D) * time.Second).Sub(now)
+	if left < time.Second {
+		return 0, false
+	}
+	return int32(left / time.Second), true
+}
+
+func (r *repairer) enqueue(ri repairItem) {
+	r.once.Do(func() { go r.run() })
+	select {
+	case r.pending <- ri:
+	default:
+		// queue is full, entry is repaired on next read
+	}
+}
+
+func (r *repairer) run() {
+	var interval time.Duration
+	if r.rate > 0 {
+		interval = time.Second / time.Duration(r.rate)
+	}
+	for ri := range r.pending {
+		// Add keeps the entry written meanwhile by the app
+		err := ri.m.client().Add(ri.item)
+		if err == nil {
+			atomic.AddUint64(&ri.m.counters.readRepairs, 1)
+		} else if err != memcache.ErrNotStored {
+			r.p.logError(ri.m.opError("repair", "", ri.item.Key, nil, err))
+		}
+		if interval > 0 {
+			time.Sleep(interval)
+		}
+	}
+}
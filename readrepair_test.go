@@ -0,0 +1,33 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheEntryRemaining(t *testing.T) {
+	now := time.Unix(1500000000, 0)
+
+	exp, ok := (&entry{}).remaining(now)
+	assert.True(t, ok)
+	assert.Equal(t, int32(0), exp)
+
+	exp, ok = (&entry{D: 60}).remaining(now)
+	assert.True(t, ok)
+	assert.Equal(t, int32(60), exp)
+
+	e := &entry{D: 60, T: now.Add(-20 * time.Second).UnixNano()}
+	exp, ok = e.remaining(now)
+	assert.True(t, ok)
+	assert.Equal(t, int32(40), exp)
+
+	e.T = now.Add(-60 * time.Second).UnixNano()
+	_, ok = e.remaining(now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,81 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+
+	"aahframe.work/cache"
+)
+
+const (
+	logKeyPlain    = "plain"
+	logKeyHash     = "hash"
+	logKeyTruncate = "truncate"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// keyRedactor redacts the cache keys in the log output and error messages,
+// since keys often embed user IDs and tokens.
+//
+//	cache {
+//	  mycache {
+//	    log_key {
+//	      # supported values are plain, hash, truncate; default value is plain
+//	      mode = "hash"
+//	      # applicable to truncate mode, default value is 8
+//	      length = 8
+//	    }
+//	  }
+//	}
+type keyRedactor struct {
+	mode   string
+	length int
+}
+
+func newKeyRedactor(m *memcacheCache) (keyRedactor, error) {
+	r := keyRedactor{
+		mode:   m.settingString("log_key.mode", logKeyPlain),
+		length: m.settingInt("log_key.length", 8),
+	}
+	switch r.mode {
+	case logKeyPlain, logKeyHash, logKeyTruncate:
+	default:
+		return r, fmt.Errorf("aah/cache/%s: unsupported log_key.mode '%s'", m.Name(), r.mode)
+	}
+	return r, nil
+}
+
+// redact method returns the given key in the form to be logged.
+func (r keyRedactor) redact(k string) string {
+	switch r.mode {
+	case logKeyHash:
+		return "#" + hashKey(k)
+	case logKeyTruncate:
+		if len(k) > r.length {
+			return k[:r.length] + "..."
+		}
+	}
+	return k
+}
+
+// logKey method returns the given key in the configured log form.
+func (m *memcacheCache) logKey(k string) string {
+	return m.redactor.redact(k)
+}
+
+// logKeyOf function returns the given key of the cache in the configured log
+// form, plain key if the cache is not created by the memcache provider.
+func logKeyOf(c cache.Cache, k string) string {
+	if mc, ok := Extended(c); ok {
+		if m, ok := mc.(*memcacheCache); ok {
+			return m.logKey(k)
+		}
+	}
+	return k
+}
@@ -0,0 +1,49 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheKeyRedactor(t *testing.T) {
+	assert.Equal(t, "user:1234:token", keyRedactor{}.redact("user:1234:token"))
+	assert.Equal(t, "user:1234:token", keyRedactor{mode: logKeyPlain}.redact("user:1234:token"))
+	assert.Equal(t, "#"+hashKey("user:1234:token"), keyRedactor{mode: logKeyHash}.redact("user:1234:token"))
+	assert.Equal(t, "user:...", keyRedactor{mode: logKeyTruncate, length: 5}.redact("user:1234:token"))
+	assert.Equal(t, "user", keyRedactor{mode: logKeyTruncate, length: 5}.redact("user"))
+
+	m := &memcacheCache{
+		cfg:      &cache.Config{Name: "redactcache"},
+		p:        &Provider{},
+		redactor: keyRedactor{mode: logKeyHash},
+	}
+	err := m.opError("delete", "user:1234:token", "", nil, memcache.ErrServerError)
+	assert.Equal(t, "aah/cache/redactcache: key(#"+hashKey("user:1234:token")+") memcache: server error", err.Error())
+	assert.Equal(t, "user:1234:token", err.(*OpError).Key)
+	assert.Equal(t, "#"+hashKey("k1"), logKeyOf(RetryMiddleware(1, 0)(m), "k1"))
+}
+
+func TestMemcacheKeyRedactorInvalid(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		redactcache {
+			log_key {
+				mode = "mask"
+			}
+		}
+	}
+`)
+	err := mgr.CreateCache(&cache.Config{Name: "redactcache", ProviderName: "memcache1"})
+	assert.Equal(t, "aah/cache/redactcache: unsupported log_key.mode 'mask'", err.Error())
+}
@@ -0,0 +1,220 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"path"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RefreshAhead method registers the key or key pattern with loader func, the
+// matching entries are refreshed in the background every given interval with
+// given expiration, keeping critical entries warm before they expire.
+//
+// Pattern syntax is same as `path.Match`, e.g. `product:*`. Keys matching the
+// pattern are discovered as they are read or written by this cache instance,
+// up to `refresh_ahead.max_keys` per pattern; keys over the limit are not
+// refreshed and counted in `Stats.RefreshKeysDropped`. Discovered keys stop
+// being refreshed once they are deleted, the loader returns `ErrNotFound`,
+// or they are not read for the given expiration.
+// Registering the same key or pattern again replaces previous registration.
+//
+//	cache {
+//	  mycache {
+//	    refresh_ahead {
+//	      # default value is 10000
+//	      max_keys = 10000
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) RefreshAhead(pattern string, interval, d time.Duration, fn KeyLoaderFunc) error {
+	if interval <= 0 {
+		return fmt.Errorf("aah/cache/%s: refresh interval must be greater than zero", m.Name())
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("aah/cache/%s: pattern(%s) %v", m.Name(), pattern, err)
+	}
+
+	r := &refresher{
+		pattern:  pattern,
+		isGlob:   strings.ContainsAny(pattern, "*?["),
+		interval: interval,
+		d:        d,
+		fn:       fn,
+		maxKeys:  m.settingInt("refresh_ahead.max_keys", 10000),
+		dropped:  &m.counters.refreshKeysDropped,
+		keys:     make(map[string]time.Time),
+		stop:     make(chan struct{}),
+	}
+	if !r.isGlob {
+		r.keys[pattern] = time.Time{}
+	}
+
+	m.refreshMu.Lock()
+	if m.closed {
+		m.refreshMu.Unlock()
+		return fmt.Errorf("aah/cache/%s: cache is closed", m.Name())
+	}
+	if m.refreshers == nil {
+		m.refreshers = make(map[string]*refresher)
+	}
+	if old, found := m.refreshers[pattern]; found {
+		close(old.stop)
+	}
+	m.refreshers[pattern] = r
+	m.refreshMu.Unlock()
+
+	go m.runRefresher(r)
+	return nil
+}
+
+// CancelRefreshAhead method stops refreshing the key or key pattern registered
+// via `RefreshAhead`.
+func (m *memcacheCache) CancelRefreshAhead(pattern string) {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	if r, found := m.refreshers[pattern]; found {
+		close(r.stop)
+		delete(m.refreshers, pattern)
+	}
+}
+
+// Close method stops the refresh ahead workers and the hot key reporter of
+// the cache, further `RefreshAhead` registrations fail. The cache is still
+// usable for reads and writes. Tenant cache leaves the hot key reporter
+// shared with its root cache running.
+func (m *memcacheCache) Close() error {
+	if m.parent == nil {
+		m.hot.stop()
+	}
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+	m.closed = true
+	for pattern, r := range m.refreshers {
+		close(r.stop)
+		delete(m.refreshers, pattern)
+	}
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type refresher struct {
+	pattern  string
+	isGlob   bool
+	interval time.Duration
+	d        time.Duration
+	fn       KeyLoaderFunc
+	maxKeys  int
+	dropped  *uint64
+	stop     chan struct{}
+
+	mu   sync.Mutex
+	keys map[string]time.Time // key -> last read
+}
+
+// add method tracks the key matching the pattern, read updates its last read
+// time.
+func (r *refresher) add(k string, read bool) {
+	if !r.isGlob {
+		return
+	}
+	if matched, _ := path.Match(r.pattern, k); !matched {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.keys[k]; found {
+		if read {
+			r.keys[k] = time.Now()
+		}
+		return
+	}
+	if r.maxKeys > 0 && len(r.keys) >= r.maxKeys {
+		atomic.AddUint64(r.dropped, 1)
+		return
+	}
+	r.keys[k] = time.Now()
+}
+
+func (r *refresher) remove(k string) {
+	if !r.isGlob {
+		return
+	}
+	r.mu.Lock()
+	delete(r.keys, k)
+	r.mu.Unlock()
+}
+
+func (r *refresher) snapshot() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]string, 0, len(r.keys))
+	for k := range r.keys {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// expire method removes the discovered keys not read within the refresh
+// expiration, they would have expired without the refresh.
+func (r *refresher) expire(now time.Time) {
+	if !r.isGlob || r.d <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, seen := range r.keys {
+		if now.Sub(seen) > r.d {
+			delete(r.keys, k)
+		}
+	}
+}
+
+func (m *memcacheCache) runRefresher(r *refresher) {
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case now := <-ticker.C:
+			r.expire(now)
+			for _, k := range r.snapshot() {
+				v, err := r.fn(k)
+				if err == ErrNotFound {
+					r.remove(k)
+				}
+				if err = m.putLoaded(k, v, err, r.d); err != nil {
+					m.p.logError(fmt.Errorf("aah/cache/%s: key(%s) refresh ahead %w", m.Name(), m.logKey(k), err))
+				}
+			}
+		}
+	}
+}
+
+// trackRefresh method records the key against matching refresh patterns.
+func (m *memcacheCache) trackRefresh(k string, read bool) {
+	m.refreshMu.RLock()
+	defer m.refreshMu.RUnlock()
+	for _, r := range m.refreshers {
+		r.add(k, read)
+	}
+}
+
+// untrackRefresh method removes deleted key from refresh patterns.
+func (m *memcacheCache) untrackRefresh(k string) {
+	m.refreshMu.RLock()
+	defer m.refreshMu.RUnlock()
+	for _, r := range m.refreshers {
+		r.remove(k)
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheRefreshAhead(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "refreshcache", ProviderName: "memcache1"}).(Cache)
+
+	err := c.RefreshAhead("product:*", 500*time.Millisecond, 10*time.Second, func(k string) (interface{}, error) {
+		return "warm " + k, nil
+	})
+	assert.Nil(t, err)
+	defer c.CancelRefreshAhead("product:*")
+
+	assert.Nil(t, c.Put("product:1", "cold", 10*time.Second))
+	assert.Nil(t, c.Put("order:1", "cold", 10*time.Second))
+	time.Sleep(700 * time.Millisecond)
+
+	assert.Equal(t, "warm product:1", c.Get("product:1"))
+	assert.Equal(t, "cold", c.Get("order:1"))
+
+	err = c.RefreshAhead("product:*", 0, 10*time.Second, nil)
+	assert.NotNil(t, err)
+
+	c.Flush()
+}
+
+func TestRefresherTrackKeys(t *testing.T) {
+	var dropped uint64
+	r := &refresher{pattern: "user:*:profile", isGlob: true, d: time.Minute, maxKeys: 2,
+		dropped: &dropped, keys: make(map[string]time.Time)}
+	r.add("user:42:profile", false)
+	r.add("user:42:orders", false)
+	assert.Equal(t, []string{"user:42:profile"}, r.snapshot())
+
+	r.remove("user:42:profile")
+	assert.Equal(t, 0, len(r.snapshot()))
+
+	// bounded
+	r.add("user:1:profile", false)
+	r.add("user:2:profile", false)
+	r.add("user:3:profile", true)
+	assert.Equal(t, 2, len(r.snapshot()))
+	assert.Equal(t, uint64(1), dropped)
+
+	// keys not read within the expiration are dropped
+	r.keys["user:1:profile"] = time.Now().Add(-2 * time.Minute)
+	r.add("user:2:profile", true)
+	r.expire(time.Now())
+	assert.Equal(t, []string{"user:2:profile"}, r.snapshot())
+}
+
+func TestMemcacheRefreshAheadClose(t *testing.T) {
+	m := newBenchCache()
+	fn := func(k string) (interface{}, error) { return k, nil }
+	assert.Nil(t, m.RefreshAhead("product:*", time.Hour, time.Minute, fn))
+	assert.Nil(t, m.RefreshAhead("banner", time.Hour, time.Minute, fn))
+	r := m.refreshers["product:*"]
+
+	m.trackRefresh("product:1", false)
+	assert.Equal(t, []string{"product:1"}, r.snapshot())
+	m.untrackRefresh("product:1")
+	assert.Equal(t, 0, len(r.snapshot()))
+
+	assert.Nil(t, m.Close())
+	assert.Equal(t, 0, len(m.refreshers))
+	select {
+	case <-r.stop:
+	default:
+		t.Error("refresher is not stopped")
+	}
+	assert.NotNil(t, m.RefreshAhead("product:*", time.Hour, time.Minute, fn))
+}
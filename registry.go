@@ -0,0 +1,230 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Bulk delete defaults, see `deleteKeys`.
+const (
+	defaultDeleteBatchSize   = 100
+	defaultDeleteConcurrency = 8
+)
+
+// ErrKeyTrackingDisabled error is returned by the methods which rely on key
+// registry when the cache does not have `key_tracking` enabled.
+var ErrKeyTrackingDisabled = errors.New("aah/cache: key tracking is not enabled")
+
+// Keys method returns the keys written by this cache instance which are not
+// expired yet and match given pattern, empty pattern matches all the keys.
+// Pattern syntax is same as `path.Match`, e.g. `user:*`. Key registry is
+// maintained locally per app instance, so keys written by other instances
+// are not included.
+//
+//	cache {
+//	  mycache {
+//	    key_tracking {
+//	      enable = true
+//	      # default value is 100000
+//	      max_keys = 100000
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) Keys(pattern string) ([]string, error) {
+	if m.registry == nil {
+		return nil, ErrKeyTrackingDisabled
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("aah/cache/%s: pattern(%s) %v", m.Name(), pattern, err)
+	}
+	keys := m.registry.keys()
+	if pattern == "" {
+		return keys, nil
+	}
+	matched := keys[:0]
+	for _, k := range keys {
+		if ok, _ := path.Match(pattern, k); ok {
+			matched = append(matched, k)
+		}
+	}
+	return matched, nil
+}
+
+// DeleteMulti method deletes the cache entries of given keys from cache store.
+// Memcache has no multi-key delete, keys are deleted in batches of
+// `bulk_delete.batch_size` keys, up to `bulk_delete.concurrency` batches at a
+// time.
+//
+//	cache {
+//	  mycache {
+//	    bulk_delete {
+//	      # default value is 100
+//	      batch_size = 100
+//
+//	      # default value is 8
+//	      concurrency = 8
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) DeleteMulti(keys []string) error {
+	err := m.deleteMulti(keys)
+	m.audit("deletemulti", "", len(keys), err)
+	return err
+}
+
+// DeleteByPattern method deletes the tracked keys matching given pattern,
+// see `Keys`, and returns the number of keys deleted. Memcache has no key
+// scan, so it requires `key_tracking` enabled and deletes the keys written
+// by this app instance only. Deletes are sent in bounded batches, see
+// `DeleteMulti`.
+//
+//	n, err := mc.DeleteByPattern("user:42:*")
+func (m *memcacheCache) DeleteByPattern(pattern string) (int, error) {
+	if pattern == "" {
+		return 0, fmt.Errorf("aah/cache/%s: pattern is required", m.Name())
+	}
+	keys, err := m.Keys(pattern)
+	if err != nil {
+		return 0, err
+	}
+	n := len(keys) - m.deleteKeys(keys)
+	if n < len(keys) {
+		err = fmt.Errorf("aah/cache/%s: unable to delete %d of %d keys of pattern(%s)", m.Name(), len(keys)-n, len(keys), pattern)
+	}
+	m.audit("deletebypattern", pattern, n, err)
+	return n, err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+func (m *memcacheCache) deleteMulti(keys []string) error {
+	if failed := m.deleteKeys(keys); failed > 0 {
+		return fmt.Errorf("aah/cache/%s: unable to delete %d of %d keys", m.Name(), failed, len(keys))
+	}
+	return nil
+}
+
+// deleteKeys method deletes given keys in bounded batches concurrently and
+// returns the number of keys failed to delete.
+func (m *memcacheCache) deleteKeys(keys []string) int {
+	size, concurrency := m.deleteBatch, m.deleteConcurrency
+	if size < 1 {
+		size = defaultDeleteBatchSize
+	}
+	if concurrency < 1 {
+		concurrency = defaultDeleteConcurrency
+	}
+	var (
+		failed int64
+		wg     sync.WaitGroup
+		sem    = make(chan struct{}, concurrency)
+	)
+	for i := 0; i < len(keys); i += size {
+		end := i + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(batch []string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			for _, k := range batch {
+				if err := m.Delete(k); err != nil {
+					m.p.logError(err)
+					atomic.AddInt64(&failed, 1)
+				}
+			}
+		}(keys[i:end])
+	}
+	wg.Wait()
+	return int(failed)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// keyRegistry
+//______________________________________________________________________________
+
+// keyRegistry tracks the keys written by the cache with their expiry time,
+// zero expiry means entry does not expire.
+type keyRegistry struct {
+	mu       sync.Mutex
+	entries  map[string]time.Time
+	max      int
+	overflow bool
+}
+
+func newKeyRegistry(max int) *keyRegistry {
+	return &keyRegistry{entries: make(map[string]time.Time), max: max}
+}
+
+func (r *keyRegistry) add(k string, d time.Duration) {
+	var exp time.Time
+	if d > 0 {
+		exp = time.Now().Add(d)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, found := r.entries[k]; !found && r.max > 0 && len(r.entries) >= r.max {
+		r.purgeExpired()
+		if len(r.entries) >= r.max {
+			r.overflow = true
+			return
+		}
+	}
+	r.entries[k] = exp
+}
+
+func (r *keyRegistry) remove(k string) {
+	r.mu.Lock()
+	delete(r.entries, k)
+	r.mu.Unlock()
+}
+
+func (r *keyRegistry) keys() []string {
+	now := time.Now()
+	r.mu.Lock()
+	keys := make([]string, 0, len(r.entries))
+	for k, exp := range r.entries {
+		if exp.IsZero() || exp.After(now) {
+			keys = append(keys, k)
+		}
+	}
+	r.mu.Unlock()
+	sort.Strings(keys)
+	return keys
+}
+
+// reset method clears the registry and reports whether registry had
+// overflown since last reset, i.e. it was not complete.
+func (r *keyRegistry) reset() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	overflow := r.overflow
+	r.entries = make(map[string]time.Time)
+	r.overflow = false
+	return overflow
+}
+
+func (r *keyRegistry) purgeExpired() {
+	now := time.Now()
+	for k, exp := range r.entries {
+		if !exp.IsZero() && !exp.After(now) {
+			delete(r.entries, k)
+		}
+	}
+}
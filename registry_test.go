@@ -0,0 +1,150 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheKeyTracking(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		trackedcache {
+			key_tracking {
+				enable = true
+			}
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "trackedcache", ProviderName: "memcache1"}))
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "othercache", ProviderName: "memcache1"}))
+	c := mgr.Cache("trackedcache").(Cache)
+	other := mgr.Cache("othercache").(Cache)
+
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, c.Put(fmt.Sprintf("key_%v", i), i, 5*time.Second))
+	}
+	assert.Nil(t, other.Put("key_0", "other", 5*time.Second))
+	assert.Nil(t, c.Delete("key_4"))
+
+	keys, err := c.Keys("")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key_0", "key_1", "key_2", "key_3"}, keys)
+	keys, err = c.Keys("key_[12]")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"key_1", "key_2"}, keys)
+	keys, err = c.Keys("user:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(keys))
+	_, err = c.Keys("[")
+	assert.NotNil(t, err)
+
+	assert.Nil(t, c.Flush())
+	assert.Nil(t, c.Get("key_0"))
+	assert.Equal(t, "other", other.Get("key_0"))
+	keys, _ = c.Keys("")
+	assert.Equal(t, 0, len(keys))
+
+	_, err = other.Keys("")
+	assert.Equal(t, ErrKeyTrackingDisabled, err)
+	assert.Nil(t, other.DeleteMulti([]string{"key_0"}))
+}
+
+func TestKeyRegistryMaxKeys(t *testing.T) {
+	r := newKeyRegistry(2)
+	r.add("key1", time.Minute)
+	r.add("key2", -1)
+	r.add("key3", time.Minute)
+	assert.Equal(t, []string{"key1", "key2"}, r.keys())
+	assert.True(t, r.reset())
+	assert.False(t, r.reset())
+}
+
+func TestMemcacheKeysPattern(t *testing.T) {
+	m := &memcacheCache{cfg: &cache.Config{Name: "tracked"}, registry: newKeyRegistry(0)}
+	m.registry.add("user:1", time.Minute)
+	m.registry.add("user:2", 0)
+	m.registry.add("order:1", time.Minute)
+
+	keys, err := m.Keys("user:*")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"user:1", "user:2"}, keys)
+	keys, _ = m.Keys("")
+	assert.Equal(t, []string{"order:1", "user:1", "user:2"}, keys)
+	_, err = m.Keys("user:[")
+	assert.Equal(t, "aah/cache/tracked: pattern(user:[) syntax error in pattern", err.Error())
+
+	m.registry = nil
+	_, err = m.Keys("user:*")
+	assert.Equal(t, ErrKeyTrackingDisabled, err)
+}
+
+func TestMemcacheDeleteByPattern(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 10}}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "tracked"}, p: p, keyPrefix: "tracked-",
+		counters: new(counters), registry: newKeyRegistry(0)}
+	m.registry.add("user:42:profile", time.Minute)
+	m.registry.add("user:42:cart", time.Minute)
+	m.registry.add("user:7:cart", time.Minute)
+
+	n, err := m.DeleteByPattern("user:42:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	var deleted []string
+	for _, op := range p.DryRunOps() {
+		assert.Equal(t, "delete", op.Op)
+		deleted = append(deleted, op.Key)
+	}
+	sort.Strings(deleted)
+	assert.Equal(t, []string{"tracked-user:42:cart", "tracked-user:42:profile"}, deleted)
+	keys, _ := m.Keys("")
+	assert.Equal(t, []string{"user:7:cart"}, keys)
+
+	n, err = m.DeleteByPattern("order:*")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, n)
+	_, err = m.DeleteByPattern("")
+	assert.NotNil(t, err)
+
+	m.registry = nil
+	_, err = m.DeleteByPattern("user:*")
+	assert.Equal(t, ErrKeyTrackingDisabled, err)
+}
+
+func TestMemcacheDeleteMultiBatches(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 1000}}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "bulk"}, p: p, keyPrefix: "bulk-",
+		counters: new(counters), deleteBatch: 10, deleteConcurrency: 3}
+
+	keys := make([]string, 95)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("key%03d", i)
+	}
+	assert.Nil(t, m.DeleteMulti(keys))
+	var deleted []string
+	for _, op := range p.DryRunOps() {
+		assert.Equal(t, "delete", op.Op)
+		deleted = append(deleted, op.Key[len("bulk-"):])
+	}
+	sort.Strings(deleted)
+	assert.Equal(t, keys, deleted)
+	assert.Equal(t, 0, m.deleteKeys(nil))
+}
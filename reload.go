@@ -0,0 +1,102 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"aahframe.work/config"
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Reload method applies the changed provider settings of given app config at
+// runtime, e.g. from the app config hot reload. The server list is updated in
+// place and re-admits the ejected servers; on timeout or `max_idle_conns` change a new memcache client is
+// swapped in, operations in-flight on the old client complete as is.
+//
+// Settings read per operation, such as `lock.*`, `lease.*` or
+// `cas_retry.*`, take effect with the reloaded config. Settings resolved when
+// the cache is created, such as `soft_ttl`, `chunk_size` and `middlewares`,
+// apply to the caches created afterwards.
+func (p *Provider) Reload(appCfg *config.Config) error {
+	cfgPrefix := "cache." + p.name + "."
+	if strings.ToLower(appCfg.StringDefault(cfgPrefix+"provider", "")) != "memcache" {
+		return fmt.Errorf("aah/cache/%s: reload not a vaild provider name, expected 'memcache'", p.name)
+	}
+	addresses, found := appCfg.StringList(cfgPrefix + "addresses")
+	if !found {
+		addresses = []string{"0.0.0.0:11211"}
+	}
+	// validate before applying, so invalid config leaves the provider as is;
+	// reported as no servers, same as Init
+	if err := new(memcache.ServerList).SetServers(addresses...); err != nil {
+		p.logger.Errorf("aah/cache/%s: reload %s", p.name, err)
+		return fmt.Errorf("aah/cache/%s: reload %s", p.name, memcache.ErrNoServers)
+	}
+
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	if !equalStrings(addresses, p.serverAddrs()) {
+		p.ejector.reset()
+		if err := p.servers.SetServers(addresses...); err != nil {
+			return fmt.Errorf("aah/cache/%s: reload %s", p.name, err)
+		}
+		p.setAddresses(addresses)
+		p.logger.Infof("aah/cache/provider: %s reloaded servers %s", p.name, strings.Join(addresses, ", "))
+	}
+	timeout := parseDuration(appCfg.StringDefault(cfgPrefix+"timeout", "5s"), "5s")
+	maxIdle := appCfg.IntDefault(cfgPrefix+"max_idle_conns", memcache.DefaultMaxIdleConns)
+	if c := p.Client(); c.Timeout != timeout || c.MaxIdleConns != maxIdle {
+		p.client.Store(p.newClient(timeout, maxIdle))
+		p.logger.Infof("aah/cache/provider: %s reloaded timeout %v, max_idle_conns %d", p.name, timeout, maxIdle)
+	}
+	p.appCfg.Store(appCfg)
+	return nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// config method returns the current app config of the provider.
+func (p *Provider) config() *config.Config {
+	cfg, _ := p.appCfg.Load().(*config.Config)
+	return cfg
+}
+
+// newClient method returns the memcache client of the provider server list,
+// published client is never modified.
+func (p *Provider) newClient(timeout time.Duration, maxIdleConns int) *memcache.Client {
+	c := memcache.NewFromSelector(p.servers)
+	c.Timeout = timeout
+	c.MaxIdleConns = maxIdleConns
+	return c
+}
+
+func (p *Provider) serverAddrs() []string {
+	p.addrMu.RLock()
+	defer p.addrMu.RUnlock()
+	return p.addresses
+}
+
+func (p *Provider) setAddresses(addresses []string) {
+	p.addrMu.Lock()
+	defer p.addrMu.Unlock()
+	p.addresses = addresses
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,79 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheReload(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "reloadcache", ProviderName: "memcache1"}))
+	c := mgr.Cache("reloadcache")
+	p := mgr.Provider("memcache1").(*Provider)
+	assert.Nil(t, c.Put("key1", "value1", 10*time.Second))
+	old := p.Client()
+
+	cfg, err := config.ParseString(`
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["127.0.0.1:11211"]
+			timeout = "2s"
+			max_idle_conns = 8
+			lock {
+				wait = "50ms"
+			}
+		}
+	}
+`)
+	assert.Nil(t, err)
+	assert.Nil(t, p.Reload(cfg))
+	assert.Equal(t, []string{"127.0.0.1:11211"}, p.serverAddrs())
+	assert.Equal(t, 2*time.Second, p.Client().Timeout)
+	assert.Equal(t, 8, p.Client().MaxIdleConns)
+	assert.True(t, old != p.Client())
+	assert.Equal(t, "50ms", c.(*memcacheCache).settingString("lock.wait", ""))
+	assert.Equal(t, "value1", c.Get("key1"))
+
+	// unchanged client settings keep the client
+	current := p.Client()
+	assert.Nil(t, p.Reload(cfg))
+	assert.True(t, current == p.Client())
+
+	invalid, _ := config.ParseString(`
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["invalid:address:11211"]
+		}
+	}
+`)
+	assert.Equal(t, "aah/cache/memcache1: reload memcache: no servers configured or available",
+		p.Reload(invalid).Error())
+	assert.Equal(t, []string{"127.0.0.1:11211"}, p.serverAddrs())
+	assert.Equal(t, 2*time.Second, p.Client().Timeout)
+
+	assert.Nil(t, c.Flush())
+}
+
+func TestMemcacheEqualStrings(t *testing.T) {
+	assert.True(t, equalStrings(nil, []string{}))
+	assert.True(t, equalStrings([]string{"a", "b"}, []string{"a", "b"}))
+	assert.False(t, equalStrings([]string{"a", "b"}, []string{"b", "a"}))
+	assert.False(t, equalStrings([]string{"a"}, []string{"a", "b"}))
+}
@@ -0,0 +1,52 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"time"
+)
+
+// SetLoader method registers the loader func of the cache. When the cache
+// has `soft_ttl` configured, Get of an entry older than soft TTL returns the
+// stale value immediately and refreshes the entry in the background using
+// this loader.
+//
+//	cache {
+//	  mycache {
+//	    # default value is 0s, disabled
+//	    soft_ttl = "30s"
+//	  }
+//	}
+func (m *memcacheCache) SetLoader(fn KeyLoaderFunc) {
+	m.loaderMu.Lock()
+	m.loader = fn
+	m.loaderMu.Unlock()
+}
+
+func (m *memcacheCache) keyLoader() KeyLoaderFunc {
+	m.loaderMu.RLock()
+	defer m.loaderMu.RUnlock()
+	return m.loader
+}
+
+// revalidate method refreshes the entry for given key in the background,
+// only one refresh per key is in-flight at a time.
+func (m *memcacheCache) revalidate(k string, d time.Duration) {
+	fn := m.keyLoader()
+	if fn == nil {
+		return
+	}
+	if _, inflight := m.refreshing.LoadOrStore(k, struct{}{}); inflight {
+		return
+	}
+	go func() {
+		defer m.refreshing.Delete(k)
+		v, err := fn(k)
+		if err = m.putLoaded(k, v, err, d); err != nil {
+			m.p.logError(fmt.Errorf("aah/cache/%s: key(%s) refresh %w", m.Name(), m.logKey(k), err))
+		}
+	}()
+}
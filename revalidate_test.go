@@ -0,0 +1,42 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheSoftTTL(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		softttlcache {
+			soft_ttl = "1s"
+		}
+	}
+`, &cache.Config{Name: "softttlcache", ProviderName: "memcache1"}).(Cache)
+
+	c.SetLoader(func(k string) (interface{}, error) {
+		return "refreshed " + k, nil
+	})
+
+	assert.Nil(t, c.Put("key1", "stale key1", 10*time.Second))
+	assert.Equal(t, "stale key1", c.Get("key1"))
+
+	time.Sleep(1100 * time.Millisecond)
+	assert.Equal(t, "stale key1", c.Get("key1"))
+
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, "refreshed key1", c.Get("key1"))
+
+	c.Flush()
+}
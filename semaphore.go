@@ -0,0 +1,97 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// semaphoreKeyPrefix is prepended to the semaphore slot key, so that slots
+// do not collide with the cache entries.
+const semaphoreKeyPrefix = "~sem:"
+
+// ErrSemaphoreFull error is returned by `Acquire` when all the slots of the
+// semaphore are held.
+var ErrSemaphoreFull = errors.New("aah/cache: semaphore is full")
+
+// Semaphore type bounds the concurrency across the app instances, each
+// permit holds one of the fixed slots of the semaphore. Slot is released by
+// the memcache after the TTL, in case the holder dies.
+type Semaphore struct {
+	m    *memcacheCache
+	name string
+	size int
+}
+
+// Permit type is the semaphore slot acquired via `Acquire`.
+type Permit struct {
+	l *lock
+}
+
+// Semaphore method returns the semaphore of given name with given number
+// of slots.
+func (m *memcacheCache) Semaphore(name string, size int) *Semaphore {
+	if size < 1 {
+		size = 1
+	}
+	return &Semaphore{m: m, name: name, size: size}
+}
+
+// Acquire method acquires a free slot of the semaphore for given TTL. If all
+// the slots are held, it is retried every `semaphore.retry_interval` until
+// `semaphore.wait` elapses, then `ErrSemaphoreFull` is returned.
+//
+//	cache {
+//	  mycache {
+//	    semaphore {
+//	      # default value is 0s, no wait
+//	      wait = "5s"
+//
+//	      # default value is 100ms
+//	      retry_interval = "100ms"
+//	    }
+//	  }
+//	}
+func (s *Semaphore) Acquire(ttl time.Duration) (*Permit, error) {
+	wait := parseDuration(s.m.settingString("semaphore.wait", ""), "0s")
+	interval := parseDuration(s.m.settingString("semaphore.retry_interval", ""), "100ms")
+	deadline := time.Now().Add(wait)
+	for {
+		p, err := s.TryAcquire(ttl)
+		if err == nil {
+			return p, nil
+		}
+		if !errors.Is(err, ErrSemaphoreFull) || !time.Now().Add(interval).Before(deadline) {
+			return nil, err
+		}
+		time.Sleep(interval)
+	}
+}
+
+// TryAcquire method acquires a free slot of the semaphore without waiting.
+func (s *Semaphore) TryAcquire(ttl time.Duration) (*Permit, error) {
+	// random start spreads the callers over the slots
+	start := rand.Intn(s.size)
+	for i := 0; i < s.size; i++ {
+		slot := strconv.Itoa((start + i) % s.size)
+		l, err := s.m.tryLock(semaphoreKeyPrefix+s.name+":", slot, ttl)
+		if err == nil {
+			return &Permit{l: l}, nil
+		}
+		if !errors.Is(err, ErrLocked) {
+			return nil, err
+		}
+	}
+	return nil, s.m.opError("lock", s.name, "", nil, ErrSemaphoreFull)
+}
+
+// Release method releases the slot of the permit, it returns
+// `ErrLockNotHeld` if the slot expired meanwhile.
+func (p *Permit) Release() error {
+	return p.l.Unlock()
+}
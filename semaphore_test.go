@@ -0,0 +1,42 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheSemaphore(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "semaphore", ProviderName: "memcache1"}).(Cache)
+
+	s := c.Semaphore("report", 2)
+	p1, err := s.Acquire(5 * time.Second)
+	assert.Nil(t, err)
+	p2, err := s.Acquire(5 * time.Second)
+	assert.Nil(t, err)
+
+	_, err = s.Acquire(5 * time.Second)
+	assert.True(t, errors.Is(err, ErrSemaphoreFull))
+
+	assert.Nil(t, p1.Release())
+	p3, err := s.TryAcquire(5 * time.Second)
+	assert.Nil(t, err)
+
+	assert.Nil(t, p2.Release())
+	assert.Nil(t, p3.Release())
+	assert.True(t, errors.Is(p3.Release(), ErrLockNotHeld))
+}
@@ -0,0 +1,71 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// ServerOps struct holds the outcome of the cache operations on a memcache
+// server, e.g. to spot the single misbehaving node of the ring by its error
+// count or latency. Multi-key operations span the servers and are not
+// counted, except their single key fallbacks.
+type ServerOps struct {
+	Ops     uint64
+	Errors  uint64
+	Latency LatencyStats
+}
+
+// ErrorRate method returns the ratio of errors to operations, 0 if no
+// operations yet.
+func (s ServerOps) ErrorRate() float64 {
+	if s.Ops > 0 {
+		return float64(s.Errors) / float64(s.Ops)
+	}
+	return 0
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type serverCounters struct {
+	ops     uint64
+	errors  uint64
+	latency histogram
+}
+
+// serverOp method records the operation outcome on given server address,
+// empty address is ignored.
+func (c *counters) serverOp(addr string, d time.Duration, err error) {
+	if c == nil || addr == "" {
+		return
+	}
+	v, found := c.servers.Load(addr)
+	if !found {
+		v, _ = c.servers.LoadOrStore(addr, new(serverCounters))
+	}
+	sc := v.(*serverCounters)
+	atomic.AddUint64(&sc.ops, 1)
+	if err != nil {
+		atomic.AddUint64(&sc.errors, 1)
+	}
+	sc.latency.record(d)
+}
+
+func (c *counters) serverOps() map[string]ServerOps {
+	result := make(map[string]ServerOps)
+	c.servers.Range(func(k, v interface{}) bool {
+		sc := v.(*serverCounters)
+		result[k.(string)] = ServerOps{
+			Ops:     atomic.LoadUint64(&sc.ops),
+			Errors:  atomic.LoadUint64(&sc.errors),
+			Latency: sc.latency.snapshot(),
+		}
+		return true
+	})
+	return result
+}
@@ -0,0 +1,40 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheServerOps(t *testing.T) {
+	m := newBenchCache()
+	m.counters.serverOp("10.0.0.1:11211", time.Millisecond, nil)
+	m.counters.serverOp("10.0.0.1:11211", 2*time.Millisecond, nil)
+	m.counters.serverOp("10.0.0.2:11211", 80*time.Millisecond, errors.New("i/o timeout"))
+	m.counters.serverOp("", time.Millisecond, nil)
+
+	servers := m.Stats().Servers
+	assert.Equal(t, 2, len(servers))
+	assert.Equal(t, uint64(2), servers["10.0.0.1:11211"].Ops)
+	assert.Equal(t, uint64(0), servers["10.0.0.1:11211"].Errors)
+	assert.Equal(t, float64(0), servers["10.0.0.1:11211"].ErrorRate())
+	assert.Equal(t, uint64(2), servers["10.0.0.1:11211"].Latency.Count)
+	assert.Equal(t, float64(1), servers["10.0.0.2:11211"].ErrorRate())
+	assert.True(t, servers["10.0.0.2:11211"].Latency.Max >= 70*time.Millisecond)
+	assert.Equal(t, float64(0), ServerOps{}.ErrorRate())
+
+	// server is unknown without the server list
+	o := m.begin("get", "user")
+	assert.Equal(t, "", o.server())
+	o.end(nil)
+	assert.Equal(t, 2, len(m.Stats().Servers))
+
+	var c *counters
+	c.serverOp("10.0.0.1:11211", time.Millisecond, nil)
+}
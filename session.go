@@ -0,0 +1,163 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/security/session"
+)
+
+var errSessionCiphertext = errors.New("aah/session/memcache: invalid ciphertext")
+
+// SessionStore struct implements `session.Storer` interface backed by the
+// memcache cache, so that aah sessions are shared across the app instances.
+//
+// Register the store and choose it in the session config.
+//
+//	session.AddStore("memcache", memcache.NewSessionStore(sessionCache))
+//
+//	security {
+//	  session {
+//	    store {
+//	      type = "memcache"
+//	    }
+//
+//	    memcache {
+//	      # session entry expiration; default value is session ttl,
+//	      # if session ttl is 0m then 30m
+//	      ttl = "30m"
+//
+//	      # extends the session expiration on every read; default value is true
+//	      slide = true
+//
+//	      # AES key of 16, 24 or 32 bytes, session value is encrypted with
+//	      # AES-GCM in the memcache; default value is empty, not encrypted
+//	      encrypt_key = ""
+//	    }
+//	  }
+//	}
+type SessionStore struct {
+	c     cache.Cache
+	ttl   time.Duration
+	slide bool
+	aead  cipher.AEAD
+}
+
+var _ session.Storer = (*SessionStore)(nil)
+
+// NewSessionStore method returns the session store of given cache, cache is
+// usually created with the memcache provider.
+func NewSessionStore(c cache.Cache) *SessionStore {
+	return &SessionStore{c: c}
+}
+
+// Init method initializes the session store with app config.
+func (s *SessionStore) Init(appCfg *config.Config) error {
+	if s.c == nil {
+		return errors.New("aah/session/memcache: cache is nil")
+	}
+	ttl := appCfg.StringDefault("security.session.ttl", "0m")
+	s.ttl = parseDuration(appCfg.StringDefault("security.session.memcache.ttl", ttl), "30m")
+	if s.ttl <= 0 {
+		s.ttl = 30 * time.Minute
+	}
+	s.slide = appCfg.BoolDefault("security.session.memcache.slide", true)
+	if key := appCfg.StringDefault("security.session.memcache.encrypt_key", ""); key != "" {
+		block, err := aes.NewCipher([]byte(key))
+		if err != nil {
+			return fmt.Errorf("aah/session/memcache: encrypt_key %s", err)
+		}
+		if s.aead, err = cipher.NewGCM(block); err != nil {
+			return fmt.Errorf("aah/session/memcache: %s", err)
+		}
+	}
+	return nil
+}
+
+// Read method returns the session value of given session id, empty string
+// if session does not exist.
+func (s *SessionStore) Read(id string) string {
+	v := s.c.Get(id)
+	if v == nil {
+		return ""
+	}
+	switch t := v.(type) {
+	case string:
+		return t
+	case []byte:
+		if s.aead == nil {
+			return string(t)
+		}
+		b, err := s.decrypt(t)
+		if err != nil {
+			return ""
+		}
+		return string(b)
+	}
+	return ""
+}
+
+// Save method stores the session value of given session id.
+func (s *SessionStore) Save(id, value string) error {
+	var v interface{} = value
+	if s.aead != nil {
+		b, err := s.encrypt([]byte(value))
+		if err != nil {
+			return err
+		}
+		v = b
+	}
+	if c, ok := Extended(s.c); ok {
+		mode := cache.EvictionModeTime
+		if s.slide {
+			mode = cache.EvictionModeSlide
+		}
+		return c.PutWith(id, v, s.ttl, WithEvictionMode(mode))
+	}
+	return s.c.Put(id, v, s.ttl)
+}
+
+// Delete method deletes the session of given session id.
+func (s *SessionStore) Delete(id string) error {
+	return s.c.Delete(id)
+}
+
+// IsExist method reports whether the session of given session id exists.
+func (s *SessionStore) IsExist(id string) bool {
+	return s.c.Exists(id)
+}
+
+// Cleanup method is no-op, memcache expires the sessions.
+func (s *SessionStore) Cleanup(_ *session.Manager) {}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// encrypt method returns nonce followed by the AES-GCM sealed value.
+func (s *SessionStore) encrypt(b []byte) ([]byte, error) {
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, b, nil), nil
+}
+
+func (s *SessionStore) decrypt(b []byte) ([]byte, error) {
+	n := s.aead.NonceSize()
+	if len(b) < n {
+		return nil, errSessionCiphertext
+	}
+	return s.aead.Open(nil, b[:n], b[n:], nil)
+}
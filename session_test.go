@@ -0,0 +1,69 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheSessionStore(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "sessions", ProviderName: "memcache1"})
+
+	cfg, err := config.ParseString(`
+	security {
+		session {
+			ttl = "10m"
+			memcache {
+				encrypt_key = "0123456789abcdef"
+			}
+		}
+	}`)
+	assert.Nil(t, err)
+
+	s := NewSessionStore(c)
+	assert.Nil(t, s.Init(cfg))
+	assert.Equal(t, 10*time.Minute, s.ttl)
+	assert.True(t, s.slide)
+
+	assert.Nil(t, s.Save("sid1", "session value"))
+	assert.True(t, s.IsExist("sid1"))
+	assert.Equal(t, "session value", s.Read("sid1"))
+
+	// stored encrypted
+	b, ok := c.Get("sid1").([]byte)
+	assert.True(t, ok)
+	assert.False(t, strings.Contains(string(b), "session value"))
+
+	assert.Nil(t, s.Delete("sid1"))
+	assert.False(t, s.IsExist("sid1"))
+	assert.Equal(t, "", s.Read("sid1"))
+}
+
+func TestMemcacheSessionStoreInit(t *testing.T) {
+	s := NewSessionStore(nil)
+	assert.NotNil(t, s.Init(config.NewEmpty()))
+
+	s = NewSessionStore(new(memcacheCache))
+	assert.Nil(t, s.Init(config.NewEmpty()))
+	assert.Equal(t, 30*time.Minute, s.ttl)
+	assert.Nil(t, s.aead)
+
+	cfg := config.NewEmpty()
+	cfg.SetString("security.session.memcache.encrypt_key", "short")
+	assert.NotNil(t, s.Init(cfg))
+}
@@ -0,0 +1,89 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ServerTiming struct holds the multiget of single memcache server issued by
+// `GetMultiDetailed`.
+type ServerTiming struct {
+	Keys     int
+	Hits     int
+	Duration time.Duration
+	Err      error
+}
+
+// MultiResult struct holds the result of `GetMultiDetailed`, Servers is
+// keyed by server address.
+type MultiResult struct {
+	Values  map[string]interface{}
+	Servers map[string]ServerTiming
+}
+
+// GetMultiDetailed method is `GetMulti` reporting the timing of the
+// multiget of each memcache server, for diagnostics of the slow pages.
+func (m *memcacheCache) GetMultiDetailed(keys []string) MultiResult {
+	values, servers := m.getMulti(context.Background(), keys)
+	return MultiResult{Values: values, Servers: servers}
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// shardedGet method splits the memcache keys by their server and issues the
+// multigets of the servers concurrently, it returns the items found along
+// with the timing of each server. Error is the first server failure, items
+// of the other servers are returned regardless.
+func (m *memcacheCache) shardedGet(ctx context.Context, mks []string) (map[string]*memcache.Item, map[string]ServerTiming, error) {
+	shards := make(map[string][]string)
+	for _, mk := range mks {
+		addr := m.p.serverAddr(mk)
+		shards[addr] = append(shards[addr], mk)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+		items    = make(map[string]*memcache.Item, len(mks))
+		timings  = make(map[string]ServerTiming, len(shards))
+	)
+	get := func(addr string, keys []string) {
+		start := time.Now()
+		found, err := m.getItems(ctx, keys)
+		st := ServerTiming{Keys: len(keys), Hits: len(found), Duration: time.Since(start), Err: err}
+		mu.Lock()
+		defer mu.Unlock()
+		for k, item := range found {
+			items[k] = item
+		}
+		timings[addr] = st
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	if len(shards) == 1 {
+		for addr, keys := range shards {
+			get(addr, keys)
+		}
+		return items, timings, firstErr
+	}
+	for addr, keys := range shards {
+		wg.Add(1)
+		go func(addr string, keys []string) {
+			defer wg.Done()
+			get(addr, keys)
+		}(addr, keys)
+	}
+	wg.Wait()
+	return items, timings, firstErr
+}
@@ -0,0 +1,58 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"testing"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheGetMultiDetailed(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 100}}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "sharded"}, p: p, keyPrefix: "sharded-",
+		counters: new(counters), chunkLimit: defaultChunkSize}
+
+	r := m.GetMultiDetailed([]string{"key1", "key2", "key3"})
+	assert.Equal(t, 0, len(r.Values))
+	assert.Equal(t, 1, len(r.Servers))
+	st := r.Servers[""]
+	assert.Equal(t, 3, st.Keys)
+	assert.Equal(t, 0, st.Hits)
+	assert.Nil(t, st.Err)
+
+	r = m.GetMultiDetailed(nil)
+	assert.Equal(t, 0, len(r.Servers))
+}
+
+func TestMemcacheShardedGet(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, dryRun: &dryRunClient{max: 100}}
+	p.servers = new(memcache.ServerList)
+	assert.Nil(t, p.servers.SetServers("127.0.0.1:21211", "127.0.0.1:21212"))
+	m := &memcacheCache{cfg: &cache.Config{Name: "sharded"}, p: p, counters: new(counters)}
+
+	var mks []string
+	for _, k := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		mks = append(mks, "sharded-"+k)
+	}
+	_, timings, err := m.shardedGet(context.Background(), mks)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(timings))
+	total := 0
+	for addr, st := range timings {
+		assert.True(t, addr == "127.0.0.1:21211" || addr == "127.0.0.1:21212")
+		total += st.Keys
+	}
+	assert.Equal(t, len(mks), total)
+	assert.Equal(t, len(mks), len(p.DryRunOps()))
+}
@@ -0,0 +1,281 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Shedding method reports whether the provider is shedding the load of its
+// low priority caches.
+func (p *Provider) Shedding() bool {
+	return p.health != nil && p.health.shedding()
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// health tracks the error rate and average latency of the memcache calls
+// of the provider per window. When a window of at least `min_ops` calls
+// exceeds `error_rate` or `latency`, the low priority caches of the provider
+// turn pass-through until a window is healthy again: reads miss and writes
+// are skipped, counted in `Stats.Shed`. Counters and conditional writes,
+// e.g. locks and CAS updates, fail with `ErrOverloaded` instead, skipping
+// them would report success without storing. Deletes and flushes are still
+// sent, so the entries do not turn stale once shedding ends. A window
+// without calls ends the shedding, the next window probes the memcache
+// again.
+//
+//	cache {
+//	  memcache1 {
+//	    provider = "memcache"
+//	    load_shedding {
+//	      # default value is false
+//	      enable = true
+//
+//	      # default value is 10s
+//	      window = "10s"
+//
+//	      # default value is 100
+//	      min_ops = 100
+//
+//	      # default value is 0.2
+//	      error_rate = 0.2
+//
+//	      # average latency; default value is 0s, not checked
+//	      latency = "50ms"
+//	    }
+//	  }
+//	  lowcache {
+//	    # low or normal; default value is normal
+//	    priority = "low"
+//	  }
+//	}
+type health struct {
+	p         *Provider
+	window    time.Duration
+	minOps    uint64
+	errorRate float64
+	latency   time.Duration
+	shed      int32
+
+	mu      sync.Mutex
+	start   time.Time
+	ops     uint64
+	errs    uint64
+	elapsed time.Duration
+}
+
+func newHealth(p *Provider) *health {
+	cfgPrefix := "cache." + p.name + ".load_shedding."
+	cfg := p.config()
+	if !cfg.BoolDefault(cfgPrefix+"enable", false) {
+		return nil
+	}
+	h := &health{
+		p:       p,
+		window:  parseDuration(cfg.StringDefault(cfgPrefix+"window", ""), "10s"),
+		minOps:  uint64(cfg.IntDefault(cfgPrefix+"min_ops", 100)),
+		latency: parseDuration(cfg.StringDefault(cfgPrefix+"latency", ""), "0s"),
+		start:   time.Now(),
+	}
+	h.errorRate, _ = strconv.ParseFloat(cfg.StringDefault(cfgPrefix+"error_rate", "0.2"), 64)
+	if h.window <= 0 {
+		h.window = 10 * time.Second
+	}
+	return h
+}
+
+func (h *health) shedding() bool {
+	if atomic.LoadInt32(&h.shed) == 0 {
+		return false
+	}
+	h.mu.Lock()
+	h.rollover(time.Now())
+	h.mu.Unlock()
+	return atomic.LoadInt32(&h.shed) == 1
+}
+
+// record method records the outcome of the memcache call.
+func (h *health) record(d time.Duration, err error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.rollover(time.Now())
+	h.ops++
+	h.elapsed += d
+	if failed(err) {
+		h.errs++
+	}
+}
+
+// rollover method evaluates the window once it is over and starts the next
+// one, caller holds the lock.
+func (h *health) rollover(now time.Time) {
+	if now.Sub(h.start) < h.window {
+		return
+	}
+	unhealthy := false
+	if h.ops > 0 && h.ops >= h.minOps {
+		unhealthy = h.errorRate > 0 && float64(h.errs)/float64(h.ops) > h.errorRate ||
+			h.latency > 0 && h.elapsed/time.Duration(h.ops) > h.latency
+	}
+	if unhealthy && atomic.CompareAndSwapInt32(&h.shed, 0, 1) {
+		h.p.logger.Warnf("aah/cache/provider: %s load shedding started, %d of %d calls failed, average latency %v",
+			h.p.name, h.errs, h.ops, h.elapsed/time.Duration(h.ops))
+	} else if !unhealthy && atomic.CompareAndSwapInt32(&h.shed, 1, 0) {
+		h.p.logger.Infof("aah/cache/provider: %s load shedding ended", h.p.name)
+	}
+	h.start, h.ops, h.errs, h.elapsed = now, 0, 0, 0
+}
+
+// failed function reports whether the memcache call failed, misses and
+// not-stored outcomes are responses of healthy server.
+func failed(err error) bool {
+	switch err {
+	case nil, memcache.ErrCacheMiss, memcache.ErrNotStored, memcache.ErrCASConflict:
+		return false
+	}
+	return true
+}
+
+// healthClient records the outcome of the memcache calls into the provider
+// health.
+type healthClient struct {
+	client
+	h *health
+}
+
+func (hc healthClient) Get(key string) (*memcache.Item, error) {
+	start := time.Now()
+	item, err := hc.client.Get(key)
+	hc.h.record(time.Since(start), err)
+	return item, err
+}
+
+func (hc healthClient) metaGet(key, flags string) (string, error) {
+	start := time.Now()
+	line, err := hc.client.metaGet(key, flags)
+	hc.h.record(time.Since(start), err)
+	return line, err
+}
+
+func (hc healthClient) GetMulti(keys []string) (map[string]*memcache.Item, error) {
+	start := time.Now()
+	items, err := hc.client.GetMulti(keys)
+	hc.h.record(time.Since(start), err)
+	return items, err
+}
+
+func (hc healthClient) Set(item *memcache.Item) error {
+	start := time.Now()
+	err := hc.client.Set(item)
+	hc.h.record(time.Since(start), err)
+	return err
+}
+
+func (hc healthClient) Add(item *memcache.Item) error {
+	start := time.Now()
+	err := hc.client.Add(item)
+	hc.h.record(time.Since(start), err)
+	return err
+}
+
+func (hc healthClient) Replace(item *memcache.Item) error {
+	start := time.Now()
+	err := hc.client.Replace(item)
+	hc.h.record(time.Since(start), err)
+	return err
+}
+
+func (hc healthClient) CompareAndSwap(item *memcache.Item) error {
+	start := time.Now()
+	err := hc.client.CompareAndSwap(item)
+	hc.h.record(time.Since(start), err)
+	return err
+}
+
+func (hc healthClient) Delete(key string) error {
+	start := time.Now()
+	err := hc.client.Delete(key)
+	hc.h.record(time.Since(start), err)
+	return err
+}
+
+func (hc healthClient) Increment(key string, delta uint64) (uint64, error) {
+	start := time.Now()
+	n, err := hc.client.Increment(key, delta)
+	hc.h.record(time.Since(start), err)
+	return n, err
+}
+
+func (hc healthClient) Decrement(key string, delta uint64) (uint64, error) {
+	start := time.Now()
+	n, err := hc.client.Decrement(key, delta)
+	hc.h.record(time.Since(start), err)
+	return n, err
+}
+
+func (hc healthClient) Touch(key string, seconds int32) error {
+	start := time.Now()
+	err := hc.client.Touch(key, seconds)
+	hc.h.record(time.Since(start), err)
+	return err
+}
+
+// shedClient is the pass-through client of the low priority cache while the
+// provider is shedding load.
+type shedClient struct {
+	client
+	m *memcacheCache
+}
+
+func (s shedClient) shed() {
+	atomic.AddUint64(&s.m.counters.shed, 1)
+}
+
+func (s shedClient) Get(string) (*memcache.Item, error) {
+	s.shed()
+	return nil, memcache.ErrCacheMiss
+}
+
+func (s shedClient) metaGet(string, string) (string, error) {
+	s.shed()
+	return "", memcache.ErrCacheMiss
+}
+
+func (s shedClient) GetMulti([]string) (map[string]*memcache.Item, error) {
+	s.shed()
+	return map[string]*memcache.Item{}, nil
+}
+
+func (s shedClient) Set(*memcache.Item) error     { s.shed(); return nil }
+func (s shedClient) Replace(*memcache.Item) error { s.shed(); return nil }
+func (s shedClient) Touch(string, int32) error    { s.shed(); return nil }
+
+func (s shedClient) Add(*memcache.Item) error {
+	s.shed()
+	return ErrOverloaded
+}
+
+func (s shedClient) CompareAndSwap(*memcache.Item) error {
+	s.shed()
+	return ErrOverloaded
+}
+
+func (s shedClient) Increment(string, uint64) (uint64, error) {
+	s.shed()
+	return 0, ErrOverloaded
+}
+
+func (s shedClient) Decrement(string, uint64) (uint64, error) {
+	s.shed()
+	return 0, ErrOverloaded
+}
@@ -0,0 +1,82 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheLoadShedding(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(config.NewEmpty())
+	assert.Nil(t, newHealth(p))
+	assert.False(t, p.Shedding())
+
+	p.health = &health{p: p, window: 50 * time.Millisecond, minOps: 4, errorRate: 0.5, start: time.Now()}
+	low := &memcacheCache{cfg: &cache.Config{Name: "low"}, p: p, counters: new(counters), lowPriority: true}
+	normal := &memcacheCache{cfg: &cache.Config{Name: "normal"}, p: p, counters: new(counters)}
+	hc, ok := low.client().(healthClient)
+	assert.True(t, ok)
+	hc.client = &dryRunClient{max: 10}
+
+	errDown := errors.New("down")
+	for i := 0; i < 4; i++ {
+		hc.h.record(time.Millisecond, errDown)
+	}
+	hc.h.record(time.Millisecond, memcache.ErrCacheMiss)
+	_, err := hc.Get("key1")
+	assert.Equal(t, memcache.ErrCacheMiss, err)
+	assert.False(t, p.Shedding())
+
+	time.Sleep(60 * time.Millisecond)
+	hc.h.record(time.Millisecond, nil)
+	assert.True(t, p.Shedding())
+
+	sc, ok := low.client().(shedClient)
+	assert.True(t, ok)
+	_, err = sc.Get("key1")
+	assert.Equal(t, memcache.ErrCacheMiss, err)
+	items, err := sc.GetMulti([]string{"key1"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(items))
+	assert.Nil(t, sc.Set(&memcache.Item{Key: "key1"}))
+	assert.Equal(t, ErrOverloaded, sc.Add(&memcache.Item{Key: "key1"}))
+	assert.Equal(t, ErrOverloaded, sc.CompareAndSwap(&memcache.Item{Key: "key1"}))
+	_, err = sc.Increment("key1", 1)
+	assert.Equal(t, ErrOverloaded, err)
+	assert.Equal(t, uint64(6), low.Stats().Shed)
+	_, ok = normal.client().(healthClient)
+	assert.True(t, ok)
+
+	// window without calls ends the shedding
+	time.Sleep(60 * time.Millisecond)
+	assert.False(t, p.Shedding())
+	_, ok = low.client().(healthClient)
+	assert.True(t, ok)
+}
+
+func TestMemcacheLoadSheddingLatency(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	h := &health{p: p, window: time.Hour, minOps: 2, latency: 10 * time.Millisecond, start: time.Now()}
+	p.health = h
+	h.record(30*time.Millisecond, nil)
+	h.rollover(h.start.Add(time.Hour))
+	assert.Equal(t, int32(0), h.shed, "below min_ops")
+
+	h.record(30*time.Millisecond, nil)
+	h.record(10*time.Millisecond, nil)
+	h.rollover(h.start.Add(time.Hour))
+	assert.Equal(t, int32(1), h.shed)
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"sync"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// flightGroup coalesces concurrent calls for the same key
+//______________________________________________________________________________
+
+// flightGroup is a minimal singleflight implementation, concurrent callers of
+// `Do` with the same key wait for the first caller and share its result.
+type flightGroup struct {
+	mu sync.Mutex
+	m  map[string]*flightCall
+}
+
+type flightCall struct {
+	done chan struct{}
+	v    interface{}
+	err  error
+}
+
+// Do method executes and returns the result of given func, making sure only
+// one execution is in-flight for given key at a time. If duplicate comes in,
+// the duplicate caller waits for the original to complete and receives the
+// same result.
+func (g *flightGroup) Do(key string, fn func() (interface{}, error)) (interface{}, error) {
+	return g.DoContext(context.Background(), key, fn)
+}
+
+// DoContext method is `Do` whose duplicate caller stops waiting for the
+// original once given context is done, it returns the context error. The
+// original caller always executes given func.
+func (g *flightGroup) DoContext(ctx context.Context, key string, fn func() (interface{}, error)) (interface{}, error) {
+	g.mu.Lock()
+	if g.m == nil {
+		g.m = make(map[string]*flightCall)
+	}
+	if c, found := g.m[key]; found {
+		g.mu.Unlock()
+		select {
+		case <-c.done:
+			return c.v, c.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	c := &flightCall{done: make(chan struct{})}
+	g.m[key] = c
+	g.mu.Unlock()
+
+	g.doCall(c, key, fn)
+	return c.v, c.err
+}
+
+func (g *flightGroup) doCall(c *flightCall, key string, fn func() (interface{}, error)) {
+	defer func() {
+		g.mu.Lock()
+		delete(g.m, key)
+		g.mu.Unlock()
+		close(c.done)
+	}()
+	c.v, c.err = fn()
+}
@@ -0,0 +1,62 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlightGroupCoalesce(t *testing.T) {
+	var g flightGroup
+	var calls int32
+	var wg sync.WaitGroup
+	start := make(chan struct{})
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			v, err := g.Do("key1", func() (interface{}, error) {
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(50 * time.Millisecond)
+				return "value1", nil
+			})
+			assert.Nil(t, err)
+			assert.Equal(t, "value1", v)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	v, _ := g.Do("key1", func() (interface{}, error) { return "value2", nil })
+	assert.Equal(t, "value2", v)
+}
+
+func TestFlightGroupContext(t *testing.T) {
+	var g flightGroup
+	started, release := make(chan struct{}), make(chan struct{})
+	go func() {
+		_, _ = g.Do("key1", func() (interface{}, error) {
+			close(started)
+			<-release
+			return "value1", nil
+		})
+	}()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	v, err := g.DoContext(ctx, "key1", func() (interface{}, error) { return "value2", nil })
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.Nil(t, v)
+	close(release)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ServerStats struct holds the statistics reported by memcache server via
+// `stats` command. Raw holds all the reported values as-is.
+type ServerStats struct {
+	Version         string
+	Uptime          uint64
+	CurrItems       uint64
+	Bytes           uint64
+	LimitMaxBytes   uint64
+	Hits            uint64
+	Misses          uint64
+	Evictions       uint64
+	CurrConnections uint64
+	Raw             map[string]string
+}
+
+// Stats method issues `stats` command to every memcache server and returns
+// the parsed statistics keyed by server address. If some of the servers
+// fail, statistics of the reachable servers are returned along with error.
+func (p *Provider) Stats() (map[string]ServerStats, error) {
+	addresses := p.serverAddrs()
+	result := make(map[string]ServerStats, len(addresses))
+	var failed []string
+	for _, addr := range addresses {
+		ss, err := p.serverStats(addr)
+		if err != nil {
+			p.logError(err)
+			failed = append(failed, addr)
+			continue
+		}
+		result[addr] = ss
+	}
+	if len(failed) > 0 {
+		return result, fmt.Errorf("aah/cache/%s: unable to get stats from %s", p.name, strings.Join(failed, ", "))
+	}
+	return result, nil
+}
+
+func (p *Provider) serverStats(addr string) (ServerStats, error) {
+	ss := ServerStats{Raw: make(map[string]string)}
+	ac, err := p.dialAdmin(addr)
+	if err != nil {
+		return ss, err
+	}
+	defer ac.Close()
+
+	err = ac.command("stats", func(line string) (bool, error) {
+		if line == "END" {
+			return false, nil
+		}
+		if f := strings.Fields(line); len(f) >= 3 && f[0] == "STAT" {
+			ss.Raw[f[1]] = strings.Join(f[2:], " ")
+		}
+		return true, nil
+	})
+	if err != nil {
+		return ss, fmt.Errorf("aah/cache/%s: server(%s) stats %v", p.name, addr, err)
+	}
+
+	ss.Version = ss.Raw["version"]
+	ss.Uptime = parseUint(ss.Raw["uptime"])
+	ss.CurrItems = parseUint(ss.Raw["curr_items"])
+	ss.Bytes = parseUint(ss.Raw["bytes"])
+	ss.LimitMaxBytes = parseUint(ss.Raw["limit_maxbytes"])
+	ss.Hits = parseUint(ss.Raw["get_hits"])
+	ss.Misses = parseUint(ss.Raw["get_misses"])
+	ss.Evictions = parseUint(ss.Raw["evictions"])
+	ss.CurrConnections = parseUint(ss.Raw["curr_connections"])
+	return ss, nil
+}
+
+func parseUint(v string) uint64 {
+	n, _ := strconv.ParseUint(v, 10, 64)
+	return n
+}
@@ -0,0 +1,33 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheServerStats(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`)
+
+	p := mgr.Provider("memcache1").(*Provider)
+	stats, err := p.Stats()
+	assert.Nil(t, err)
+
+	ss, found := stats["localhost:11211"]
+	assert.True(t, found)
+	assert.NotEqual(t, "", ss.Version)
+	assert.True(t, ss.CurrConnections > 0)
+	assert.True(t, ss.LimitMaxBytes > 0)
+	assert.Equal(t, ss.Raw["version"], ss.Version)
+}
@@ -0,0 +1,114 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// statsdSink emits per operation timing and count metrics to StatsD or
+// DogStatsD over UDP. Writes are fire-and-forget, metrics are dropped if
+// the agent is unreachable.
+//
+//	cache {
+//	  memcache1 {
+//	    statsd {
+//	      address = "127.0.0.1:8125"
+//	      # default value is "aah.cache"
+//	      prefix = "aah.cache"
+//	      # statsd or dogstatsd; default value is statsd
+//	      format = "dogstatsd"
+//	      # applicable to dogstatsd format
+//	      tags = ["env:prod", "service:web"]
+//	    }
+//	  }
+//	}
+//
+// StatsD format folds the cache name into metric name e.g.
+// `aah.cache.mycache.get.hit:1|c`, DogStatsD format sends it as
+// `cache:mycache` tag, along with `server:host:port` tag of the memcache
+// server for the single key operations.
+type statsdSink struct {
+	conn   net.Conn
+	prefix string
+	dog    bool
+	tags   string
+}
+
+func newStatsdSink(p *Provider) (*statsdSink, error) {
+	cfgPrefix := "cache." + p.name + ".statsd."
+	addr := p.config().StringDefault(cfgPrefix+"address", "")
+	if addr == "" {
+		return nil, nil
+	}
+	conn, err := net.Dial("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("aah/cache/%s: statsd %v", p.name, err)
+	}
+
+	s := &statsdSink{
+		conn:   conn,
+		prefix: strings.TrimSuffix(p.config().StringDefault(cfgPrefix+"prefix", "aah.cache"), "."),
+		dog:    strings.ToLower(p.config().StringDefault(cfgPrefix+"format", "statsd")) == "dogstatsd",
+	}
+	if tags, found := p.config().StringList(cfgPrefix + "tags"); found {
+		s.tags = strings.Join(tags, ",")
+	}
+	return s, nil
+}
+
+func (s *statsdSink) record(o *operation, err error) {
+	if s == nil {
+		return
+	}
+	var sb strings.Builder
+	elapsed := float64(o.elapsed()) / float64(time.Millisecond)
+	s.line(&sb, o, "time", strconv.FormatFloat(elapsed, 'f', 3, 64), "ms")
+	if o.hits > 0 {
+		s.line(&sb, o, "hit", strconv.Itoa(o.hits), "c")
+	}
+	if o.misses > 0 {
+		s.line(&sb, o, "miss", strconv.Itoa(o.misses), "c")
+	}
+	if err != nil {
+		s.line(&sb, o, "error", "1", "c")
+	}
+	_, _ = s.conn.Write([]byte(sb.String()))
+}
+
+func (s *statsdSink) line(sb *strings.Builder, o *operation, metric, value, typ string) {
+	if sb.Len() > 0 {
+		sb.WriteByte('\n')
+	}
+	sb.WriteString(s.prefix)
+	sb.WriteByte('.')
+	if !s.dog {
+		sb.WriteString(o.m.Name())
+		sb.WriteByte('.')
+	}
+	sb.WriteString(o.name)
+	sb.WriteByte('.')
+	sb.WriteString(metric)
+	sb.WriteByte(':')
+	sb.WriteString(value)
+	sb.WriteByte('|')
+	sb.WriteString(typ)
+	if s.dog {
+		sb.WriteString("|#cache:")
+		sb.WriteString(o.m.Name())
+		if addr := o.server(); addr != "" {
+			sb.WriteString(",server:")
+			sb.WriteString(addr)
+		}
+		if s.tags != "" {
+			sb.WriteByte(',')
+			sb.WriteString(s.tags)
+		}
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatsdSinkRecord(t *testing.T) {
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer pc.Close()
+	conn, err := net.Dial("udp", pc.LocalAddr().String())
+	assert.Nil(t, err)
+
+	m := &memcacheCache{cfg: &cache.Config{Name: "statsdcache"}}
+	read := func() []string {
+		buf := make([]byte, 1024)
+		_ = pc.SetReadDeadline(time.Now().Add(time.Second))
+		n, _, err := pc.ReadFrom(buf)
+		assert.Nil(t, err)
+		return strings.Split(string(buf[:n]), "\n")
+	}
+
+	s := &statsdSink{conn: conn, prefix: "aah.cache"}
+	s.record(&operation{m: m, name: "get", start: time.Now(), hits: 1}, nil)
+	lines := read()
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasPrefix(lines[0], "aah.cache.statsdcache.get.time:"))
+	assert.Equal(t, "aah.cache.statsdcache.get.hit:1|c", lines[1])
+
+	s = &statsdSink{conn: conn, prefix: "aah.cache", dog: true, tags: "env:test"}
+	s.record(&operation{m: m, name: "put", start: time.Now()}, errors.New("timeout"))
+	lines = read()
+	assert.Equal(t, 2, len(lines))
+	assert.True(t, strings.HasSuffix(lines[0], "|ms|#cache:statsdcache,env:test"))
+	assert.Equal(t, "aah.cache.put.error:1|c|#cache:statsdcache,env:test", lines[1])
+}
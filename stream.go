@@ -0,0 +1,179 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// flagStream flag marks the chunked memcache item as raw bytes written by
+// `PutReader`, value is not gob encoded.
+const flagStream uint32 = 1 << 5
+
+// ErrNotStream error is returned by `GetReader` when the entry of given key
+// is not written by `PutReader`.
+var ErrNotStream = errors.New("aah/cache: entry is not a stream")
+
+// PutReader method stores the bytes read from given reader as the cache
+// entry, bytes are written in chunks of `chunk_size` as they are read, so
+// the payload is never fully buffered. Entry is readable via `GetReader`,
+// `Get` returns it as []byte. Stream entries are not touched in slide
+// eviction mode. Streams are not supported in the interop mode.
+//
+// Bytes read are counted against `max_value_size` like the Put value, the
+// stream exceeding it is not stored as per `oversize_policy`: `reject`
+// returns `ErrValueTooLarge`, `skip` skips it. Chunks written so far are
+// left to expire.
+func (m *memcacheCache) PutReader(k string, r io.Reader, d time.Duration) error {
+	if m.interop != interopNone {
+		return m.opError("put", k, "", nil, errInteropStream)
+	}
+	d, err := m.ttl.apply(d)
+	if err != nil {
+		return m.opError("put", k, "", nil, err)
+	}
+	o := m.begin("put", k)
+	mk := m.key(k)
+	exp := int32(d.Seconds())
+	h := sha256.New()
+	gen := newChunkGen()
+	buf := make([]byte, m.chunkLimit)
+	var n, size int
+	var oversize bool
+	for {
+		c, rerr := io.ReadFull(r, buf)
+		if oversize = c > 0 && m.oversizeStream(size+c); oversize {
+			break
+		}
+		if c > 0 {
+			if err = m.client().Set(&memcache.Item{
+				Key:        chunkKey(mk, gen, n),
+				Value:      buf[:c],
+				Expiration: expirationOf(exp),
+			}); err != nil {
+				break
+			}
+			_, _ = h.Write(buf[:c])
+			n++
+			size += c
+		}
+		if rerr == io.EOF || rerr == io.ErrUnexpectedEOF {
+			break
+		}
+		if rerr != nil {
+			err = rerr
+			break
+		}
+	}
+	if oversize {
+		o.end(nil)
+		if m.size.policy == oversizeSkip {
+			atomic.AddUint64(&m.counters.oversizeSkipped, 1)
+			return nil
+		}
+		return m.opError("put", k, mk, ErrValueTooLarge,
+			fmt.Errorf("stream size exceeds max_value_size %d", m.size.max))
+	}
+	if err == nil {
+		mf := manifest{n: n, size: size, sum: hex.EncodeToString(h.Sum(nil)), gen: gen}
+		err = m.client().Set(manifestItem(mk, mf, flagStream|flagNoTouch, expirationOf(exp)))
+	}
+	o.written(size)
+	o.end(err)
+	if err != nil {
+		return m.opError("put", k, mk, nil, err)
+	}
+	m.counters.written(len(mk), size)
+	m.onStored(k, exp)
+	return nil
+}
+
+// GetReader method returns the reader of the cache entry written by
+// `PutReader`, chunks are fetched as they are read. Checksum of the entry is
+// verified at the end, mismatch is reported by the last Read. Returns error
+// of class `ErrMiss` if the entry does not exist.
+func (m *memcacheCache) GetReader(k string) (io.ReadCloser, error) {
+	o := m.begin("get", k)
+	mk := m.key(k)
+	v, err := m.getItem(mk)
+	if err == memcache.ErrCacheMiss {
+		o.miss()
+		o.end(nil)
+		return nil, m.opError("get", k, mk, nil, err)
+	}
+	if err != nil {
+		o.end(err)
+		return nil, m.opError("get", k, mk, nil, err)
+	}
+	o.hit(len(v.Value))
+	o.end(nil)
+	if v.Flags&flagStream != flagStream {
+		return nil, m.opError("get", k, mk, nil, ErrNotStream)
+	}
+	mf, err := parseManifest(v)
+	if err != nil {
+		m.undecodable(k, mk, err)
+		return nil, m.opError("get", k, mk, ErrCorrupt, err)
+	}
+	return &chunkReader{m: m, mk: mk, mf: mf, h: sha256.New()}, nil
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// oversizeStream method reports whether the stream of given size exceeds
+// `max_value_size`, the `chunk` policy has no limit.
+func (m *memcacheCache) oversizeStream(size int) bool {
+	return m.size.max > 0 && size > m.size.max && m.size.policy != oversizeChunk
+}
+
+// chunkReader reads the chunks of the stream entry one at a time.
+type chunkReader struct {
+	m  *memcacheCache
+	mk string
+	mf manifest
+	h  hash.Hash
+
+	next int
+	read int
+	buf  []byte
+}
+
+func (cr *chunkReader) Read(p []byte) (int, error) {
+	for len(cr.buf) == 0 {
+		if cr.next == cr.mf.n {
+			if cr.read != cr.mf.size || hex.EncodeToString(cr.h.Sum(nil)) != cr.mf.sum {
+				return 0, errChecksum
+			}
+			return 0, io.EOF
+		}
+		item, err := cr.m.client().Get(chunkKey(cr.mk, cr.mf.gen, cr.next))
+		if err != nil {
+			return 0, err
+		}
+		cr.next++
+		cr.buf = item.Value
+		_, _ = cr.h.Write(cr.buf)
+		cr.read += len(cr.buf)
+	}
+	c := copy(p, cr.buf)
+	cr.buf = cr.buf[c:]
+	return c, nil
+}
+
+func (cr *chunkReader) Close() error {
+	cr.buf, cr.next = nil, cr.mf.n
+	return nil
+}
@@ -0,0 +1,70 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"strings"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheStream(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		streamcache {
+			chunk_size = "1KB"
+		}
+	}
+`, &cache.Config{Name: "streamcache", ProviderName: "memcache1"}).(Cache)
+
+	report := []byte(strings.Repeat("report line\n", 1000))
+	assert.Nil(t, c.PutReader("report1", bytes.NewReader(report), 3*time.Second))
+
+	r, err := c.GetReader("report1")
+	assert.Nil(t, err)
+	b, err := ioutil.ReadAll(r)
+	assert.Nil(t, err)
+	assert.Nil(t, r.Close())
+	assert.Equal(t, report, b)
+	assert.Equal(t, report, c.Get("report1"))
+
+	_, err = c.GetReader("report2")
+	assert.True(t, errors.Is(err, ErrMiss))
+
+	assert.Nil(t, c.Put("key1", "value1", 3*time.Second))
+	_, err = c.GetReader("key1")
+	assert.True(t, errors.Is(err, ErrNotStream))
+
+	c.Flush()
+}
+
+func TestMemcacheStreamMaxSize(t *testing.T) {
+	m := newBenchCache()
+	m.p.dryRun.max = 10
+	m.chunkLimit = 4
+	m.size = sizeLimit{max: 10, policy: oversizeReject}
+
+	err := m.PutReader("key1", strings.NewReader(strings.Repeat("x", 20)), time.Minute)
+	assert.True(t, errors.Is(err, ErrValueTooLarge))
+	assert.Equal(t, 2, len(m.p.dryRun.ops), "manifest is not written")
+
+	m.size.policy = oversizeSkip
+	assert.Nil(t, m.PutReader("key2", strings.NewReader(strings.Repeat("x", 20)), time.Minute))
+	assert.Equal(t, uint64(1), m.counters.oversizeSkipped)
+
+	m.size.policy = oversizeChunk
+	assert.Nil(t, m.PutReader("key3", strings.NewReader(strings.Repeat("x", 20)), time.Minute))
+	assert.Nil(t, m.PutReader("key4", strings.NewReader(strings.Repeat("x", 10)), time.Minute))
+}
@@ -0,0 +1,80 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"reflect"
+
+	"aahframe.work/cache"
+)
+
+// TypeMismatchError struct describes the cached value whose type differs
+// from the type expected by the caller. It matches `ErrTypeMismatch` with
+// `errors.Is`.
+type TypeMismatchError struct {
+	Stored   string
+	Expected string
+}
+
+// Error method returns the error message.
+func (e *TypeMismatchError) Error() string {
+	return "cached value of type " + e.Stored + " does not match " + e.Expected
+}
+
+// Is method reports whether the target is `ErrTypeMismatch`.
+func (e *TypeMismatchError) Is(target error) bool {
+	return target == ErrTypeMismatch
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// verifyType method verifies the type of the entry recorded in the envelope
+// against the type expected by the caller. With `strict_types` enabled
+// `GetInto` and `Typed` caches return `ErrTypeMismatch` instead of
+// converting the value of another type, e.g. int64 stored by an older
+// release read into int32. Pointer to the expected type matches too. Values
+// without Go type, e.g. interop mode JSON values, are not verified.
+//
+//	cache {
+//	  mycache {
+//	    # default value is false
+//	    strict_types = true
+//	  }
+//	}
+func (m *memcacheCache) verifyType(k string, e *entry, t reflect.Type) error {
+	if !m.strictTypes || e.V == Nil {
+		return nil
+	}
+	stored := e.S
+	if stored == "" {
+		stored = schemaHint(e.V)
+	}
+	if typeMatches(stored, t) || m.interop != interopNone {
+		return nil
+	}
+	return m.opError("get", k, m.key(k), ErrTypeMismatch,
+		&TypeMismatchError{Stored: stored, Expected: t.String()})
+}
+
+// typeMatches function reports whether the stored type name matches given
+// type, unknown stored type and interface type match any.
+func typeMatches(stored string, t reflect.Type) bool {
+	if stored == "" || t.Kind() == reflect.Interface {
+		return true
+	}
+	return stored == t.String() || stored == "*"+t.String()
+}
+
+// isStrict function reports whether the cache has `strict_types` enabled.
+func isStrict(c cache.Cache) bool {
+	mc, ok := Extended(c)
+	if !ok {
+		return false
+	}
+	m, ok := mc.(*memcacheCache)
+	return ok && m.strictTypes
+}
@@ -0,0 +1,49 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheStrictTypes(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	p.appCfg.Store(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "strict"}, p: p, keyPrefix: "strict-",
+		counters: new(counters)}
+
+	int32Type := reflect.TypeOf(int32(0))
+	e := &entry{V: int64(42), S: "int64"}
+	assert.Nil(t, m.verifyType("count", e, int32Type))
+	assert.False(t, isStrict(m))
+
+	m.strictTypes = true
+	assert.True(t, isStrict(m))
+	err := m.verifyType("count", e, int32Type)
+	assert.True(t, errors.Is(err, ErrTypeMismatch))
+	var tme *TypeMismatchError
+	assert.True(t, errors.As(err, &tme))
+	assert.Equal(t, "int64", tme.Stored)
+	assert.Equal(t, "int32", tme.Expected)
+	assert.Equal(t, "aah/cache/strict: key(count) cached value of type int64 does not match int32", err.Error())
+
+	// type of the value without recorded name, pointer and interface
+	assert.Nil(t, m.verifyType("count", &entry{V: int32(1)}, int32Type))
+	assert.NotNil(t, m.verifyType("count", &entry{V: "1"}, int32Type))
+	assert.Nil(t, m.verifyType("user", &entry{S: "*memcache.queryUser"}, reflect.TypeOf(queryUser{})))
+	assert.Nil(t, m.verifyType("any", e, reflect.TypeOf((*interface{})(nil)).Elem()))
+	assert.Nil(t, m.verifyType("nil", &entry{}, int32Type))
+
+	m.interop = interopJSON
+	assert.Nil(t, m.verifyType("count", e, int32Type))
+}
@@ -0,0 +1,198 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// subjectKeyPrefix is the cache key prefix of the subject index entries.
+const subjectKeyPrefix = "~subject:"
+
+// WithSubject option tags the entry with given subject identifiers, e.g. the
+// user ID whose personal data the value holds, so that `PurgeSubject`
+// deletes it.
+//
+//	err := mc.PutWith("profile:42", profile, time.Hour, memcache.WithSubject("user:42"))
+func WithSubject(ids ...string) PutOption {
+	return func(o *putOptions) error {
+		for _, id := range ids {
+			if id == "" {
+				return errors.New("aah/cache: subject id is required")
+			}
+		}
+		o.subjects = append(o.subjects, ids...)
+		return nil
+	}
+}
+
+// PurgeSubject method deletes all the entries tagged with given subject
+// identifier via `WithSubject`, e.g. for the GDPR/CCPA deletion workflows.
+//
+// Keys of the subject are indexed in the memcache entry of the subject, it
+// is shared across the app instances and updated with CAS, so the entries
+// tagged by any instance are purged. Entries tagged meanwhile are kept in
+// the index for the next purge. The index is stored outside of the TTL
+// policy of the cache and expires with the last entry of the subject, keys
+// of the expired entries are pruned on update; memcache could evict it under
+// memory pressure, then the entries are removed by their own expiration.
+func (m *memcacheCache) PurgeSubject(subjectID string) error {
+	if subjectID == "" {
+		return fmt.Errorf("aah/cache/%s: subject id is required", m.Name())
+	}
+	var keys []string
+	ik := subjectKeyPrefix + subjectID
+	err := m.updateSubject(subjectID, func(idx subjectIndex, found bool) (subjectIndex, error) {
+		if !found {
+			return idx, errNoSubject
+		}
+		keys = idx.keys()
+		return subjectIndex{}, nil
+	})
+	if err == errNoSubject {
+		m.audit("purgesubject", "", 0, nil)
+		return nil
+	}
+	if err != nil {
+		m.audit("purgesubject", "", -1, err)
+		return err
+	}
+
+	pl := m.Pipeline()
+	for _, k := range keys {
+		pl.Delete(k)
+	}
+	pl.Delete(ik)
+	if err = pl.Exec(); err != nil {
+		err = fmt.Errorf("aah/cache/%s: unable to purge subject entries: %w", m.Name(), err)
+	}
+	m.audit("purgesubject", "", len(keys), err)
+	return err
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// errNoSubject aborts the purge of the subject without index.
+var errNoSubject = errors.New("no subject index")
+
+// subjectRetries is the max CAS conflicts of the subject index update.
+const subjectRetries = 10
+
+// subjectIndex holds the keys tagged with the subject.
+type subjectIndex struct {
+	Members []subjectMember
+}
+
+// subjectMember is the key tagged with the subject, Until is the unix time
+// the entry expires, 0 if it does not expire.
+type subjectMember struct {
+	Key   string
+	Until int64
+}
+
+func init() {
+	registerGob(subjectIndex{})
+}
+
+func (idx subjectIndex) keys() []string {
+	keys := make([]string, 0, len(idx.Members))
+	for _, sm := range idx.Members {
+		keys = append(keys, sm.Key)
+	}
+	return keys
+}
+
+// until method returns the unix time the last entry of the subject expires,
+// 0 if one does not expire.
+func (idx subjectIndex) until() int64 {
+	var until int64
+	for _, sm := range idx.Members {
+		if sm.Until == 0 {
+			return 0
+		}
+		if sm.Until > until {
+			until = sm.Until
+		}
+	}
+	return until
+}
+
+func (idx subjectIndex) ttl() time.Duration {
+	until := idx.until()
+	if until == 0 {
+		return NoExpiration
+	}
+	d := time.Until(time.Unix(until, 0)).Round(time.Second)
+	if d < time.Second {
+		d = time.Second
+	}
+	return d
+}
+
+// prune method returns the index without the keys of the entries expired by
+// given time.
+func (idx subjectIndex) prune(now time.Time) subjectIndex {
+	members := make([]subjectMember, 0, len(idx.Members))
+	for _, sm := range idx.Members {
+		if sm.Until == 0 || sm.Until > now.Unix() {
+			members = append(members, sm)
+		}
+	}
+	return subjectIndex{Members: members}
+}
+
+// tag method returns the index with the key tagged until given unix time.
+func (idx subjectIndex) tag(k string, until int64) subjectIndex {
+	for i, sm := range idx.Members {
+		if sm.Key == k {
+			idx.Members[i].Until = until
+			return idx
+		}
+	}
+	idx.Members = append(idx.Members, subjectMember{Key: k, Until: until})
+	return idx
+}
+
+// tagSubjects method adds the key to the index of given subjects, d is the
+// expiration of the entry in seconds. It is done before the entry is stored,
+// so a failed tagging never leaves the entry of the subject unindexed.
+func (m *memcacheCache) tagSubjects(k string, subjects []string, d int32) error {
+	var until int64
+	if d > 0 {
+		until = time.Now().Add(time.Duration(d) * time.Second).Unix()
+	}
+	for _, id := range subjects {
+		err := m.updateSubject(id, func(idx subjectIndex, _ bool) (subjectIndex, error) {
+			return idx.tag(k, until), nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateSubject method updates the index of given subject with the
+// Gets/CAS loop, the func gets the index pruned of expired keys. The index
+// is stored with the expiration of its last entry as is, `max_ttl`,
+// `ttl_jitter` apply to the entries only.
+func (m *memcacheCache) updateSubject(id string, fn func(idx subjectIndex, found bool) (subjectIndex, error)) error {
+	ik := subjectKeyPrefix + id
+	_, err := m.tryUpdate(ik, func(cur interface{}, found bool) (interface{}, time.Duration, error) {
+		idx, _ := cur.(subjectIndex)
+		idx, err := fn(idx.prune(time.Now()), found)
+		return idx, idx.ttl(), err
+	}, subjectRetries, m.casBackoff(), func(k string, v interface{}, d time.Duration, token CASToken) error {
+		if d == NoExpiration {
+			d = 0
+		}
+		return m.putCASEntry(k, newEntry(v, d), token)
+	})
+	return err
+}
@@ -0,0 +1,75 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/cache/provider/memcache/memcachetest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcachePurgeSubject(t *testing.T) {
+	srv, err := memcachetest.NewServer()
+	assert.Nil(t, err)
+	defer srv.Close()
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["`+srv.Addr()+`"]
+		}
+	}
+`, &cache.Config{Name: "subject", ProviderName: "memcache1"}).(Cache)
+
+	assert.Nil(t, c.PutWith("profile:42", "jeeva", time.Minute, WithSubject("user:42")))
+	assert.Nil(t, c.PutWith("orders:42", []string{"o1"}, time.Hour, WithSubject("user:42", "tenant:7")))
+	assert.Nil(t, c.PutWith("profile:42", "jeeva m", time.Minute, WithSubject("user:42")))
+	assert.Nil(t, c.PutWith("profile:7", "other", time.Minute, WithSubject("user:7")))
+
+	v, _, _ := c.Gets(subjectKeyPrefix + "user:42")
+	assert.Equal(t, []string{"profile:42", "orders:42"}, v.(subjectIndex).keys())
+
+	assert.Nil(t, c.PurgeSubject("user:42"))
+	assert.Nil(t, c.Get("profile:42"))
+	assert.Nil(t, c.Get("orders:42"))
+	assert.Equal(t, "other", c.Get("profile:7"))
+	assert.Nil(t, c.Get(subjectKeyPrefix+"user:42"))
+
+	// nothing tagged
+	assert.Nil(t, c.PurgeSubject("user:42"))
+	assert.NotNil(t, c.PurgeSubject(""))
+}
+
+func TestMemcacheSubjectOption(t *testing.T) {
+	var o putOptions
+	assert.Nil(t, WithSubject("user:1", "user:2")(&o))
+	assert.Equal(t, []string{"user:1", "user:2"}, o.subjects)
+	assert.NotNil(t, WithSubject("")(&o))
+
+	assert.Equal(t, NoExpiration, subjectIndex{}.ttl())
+	hour := time.Now().Add(time.Hour).Unix()
+	d := subjectIndex{Members: []subjectMember{{Key: "k1", Until: hour}}}.ttl()
+	assert.True(t, d > 59*time.Minute && d <= time.Hour)
+	assert.Equal(t, time.Second, subjectIndex{Members: []subjectMember{{Key: "k1", Until: 1}}}.ttl())
+}
+
+func TestMemcacheSubjectIndex(t *testing.T) {
+	now := time.Now()
+	minute, hour := now.Add(time.Minute).Unix(), now.Add(time.Hour).Unix()
+
+	var idx subjectIndex
+	idx = idx.tag("k1", minute).tag("k2", hour).tag("k1", minute+1)
+	assert.Equal(t, []string{"k1", "k2"}, idx.keys())
+	assert.Equal(t, hour, idx.until())
+	assert.Equal(t, int64(0), idx.tag("k3", 0).until())
+
+	// expired keys are pruned
+	idx = idx.prune(now.Add(2 * time.Minute))
+	assert.Equal(t, []string{"k2"}, idx.keys())
+	assert.Equal(t, 0, len(idx.prune(now.Add(2*time.Hour)).Members))
+}
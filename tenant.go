@@ -0,0 +1,177 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var (
+	// ErrInvalidTenant error is returned by `ForTenant` for empty tenant ID
+	// or ID having whitespace, control characters or the delimiter.
+	ErrInvalidTenant = errors.New("aah/cache: invalid tenant id")
+
+	// ErrNoTenant error is returned by `ForContext` when the context does
+	// not carry tenant ID.
+	ErrNoTenant = errors.New("aah/cache: no tenant in context")
+
+	// ErrTenantFlush error is returned by Flush of the tenant cache when
+	// neither `prefix_invalidation` nor `key_tracking` is enabled, flushing
+	// the memcache servers would flush all the tenants.
+	ErrTenantFlush = errors.New("aah/cache: tenant flush requires prefix_invalidation or key_tracking")
+)
+
+type tenantCtxKey struct{}
+
+// WithTenant function returns the copy of given context carrying the tenant
+// ID, use it with `ForContext`.
+func WithTenant(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, tenantCtxKey{}, id)
+}
+
+// TenantFromContext function returns the tenant ID carried by given context.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(tenantCtxKey{}).(string)
+	return id, ok && id != ""
+}
+
+// ForTenant method returns the cache of given tenant, the tenant ID is folded
+// into the key of every entry, so the tenants of SaaS apps never read each
+// other's entries for the same key. Tenant caches share the settings, stats
+// and hooks of this cache.
+//
+// Flush of the tenant cache flushes only the tenant entries: with
+// `prefix_invalidation` enabled it invalidates the tenant prefix, with
+// `key_tracking` it deletes the tracked tenant keys, otherwise it returns
+// `ErrTenantFlush`. `InvalidatePrefix` is scoped to the tenant.
+//
+//	cache {
+//	  mycache {
+//	    prefix_invalidation {
+//	      enable = true
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) ForTenant(id string) (Cache, error) {
+	root := m.root()
+	if id == "" || strings.IndexFunc(id, invalidTenantRune) >= 0 || strings.Contains(id, root.tenantDelim()) {
+		return nil, ErrInvalidTenant
+	}
+	if t, found := root.tenants.Load(id); found {
+		return t.(*memcacheCache), nil
+	}
+	actual, _ := root.tenants.LoadOrStore(id, root.newTenant(id))
+	return actual.(*memcacheCache), nil
+}
+
+// ForContext method returns the cache of the tenant carried by given context,
+// see `WithTenant`. It returns `ErrNoTenant` if the context does not carry
+// tenant, so the tenant data is never cached in the shared key space by
+// mistake.
+func (m *memcacheCache) ForContext(ctx context.Context) (Cache, error) {
+	id, found := TenantFromContext(ctx)
+	if !found {
+		return nil, ErrNoTenant
+	}
+	return m.ForTenant(id)
+}
+
+// Tenant method returns the tenant ID of the cache, empty for the non-tenant
+// cache.
+func (m *memcacheCache) Tenant() string {
+	return strings.TrimSuffix(m.tenant, m.root().tenantDelim())
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// root method returns the cache the tenant cache is derived from, the cache
+// itself for the non-tenant cache.
+func (m *memcacheCache) root() *memcacheCache {
+	if m.parent != nil {
+		return m.parent
+	}
+	return m
+}
+
+// newTenant method returns the tenant view of the cache. It copies the
+// settings of the cache and shares its stats, write-behind queue, toucher and
+// hot key tracker, so the tenant starts no background goroutines of its own.
+// Prefix generations and tracked keys are kept per tenant for its Flush.
+func (m *memcacheCache) newTenant(id string) *memcacheCache {
+	t := &memcacheCache{
+		cfg:               m.cfg,
+		keyPrefix:         m.keyPrefix,
+		p:                 m.p,
+		softTTL:           m.softTTL,
+		negativeTTL:       m.negativeTTL,
+		slowThreshold:     m.slowThreshold,
+		logMisses:         m.logMisses,
+		redactor:          m.redactor,
+		keyEcho:           m.keyEcho,
+		ttl:               m.ttl,
+		toucher:           m.toucher,
+		size:              m.size,
+		chunkLimit:        m.chunkLimit,
+		rawValues:         m.rawValues,
+		fastCodec:         m.fastCodec,
+		strictTypes:       m.strictTypes,
+		interop:           m.interop,
+		lease:             m.lease,
+		deadline:          m.deadline,
+		labels:            m.labels,
+		lowPriority:       m.lowPriority,
+		deleteCorrupt:     m.deleteCorrupt,
+		readOnly:          m.readOnly,
+		writeOnly:         m.writeOnly,
+		hot:               m.hot,
+		counters:          m.counters,
+		vars:              m.vars,
+		wq:                m.wq,
+		deleteBatch:       m.deleteBatch,
+		deleteConcurrency: m.deleteConcurrency,
+		parent:            m,
+		tenant:            id + m.tenantDelim(),
+	}
+	if m.gens != nil {
+		t.gens = newGenerations(t)
+	}
+	if m.registry != nil {
+		t.registry = newKeyRegistry(m.registry.max)
+	}
+	return t
+}
+
+// tenantDelim method returns the delimiter following the tenant ID in the
+// key, it is the prefix invalidation delimiter so that the tenant is a
+// prefix of its keys.
+func (m *memcacheCache) tenantDelim() string {
+	if m.gens != nil {
+		return m.gens.delim
+	}
+	return ":"
+}
+
+// flushTenant method flushes the entries of the tenant cache.
+func (m *memcacheCache) flushTenant() error {
+	switch {
+	case m.gens != nil:
+		return m.InvalidatePrefix("")
+	case m.registry != nil:
+		keys := m.registry.keys()
+		m.registry.reset()
+		err := m.deleteMulti(keys)
+		m.audit("flush", "", len(keys), err)
+		return err
+	}
+	return ErrTenantFlush
+}
+
+func invalidTenantRune(r rune) bool {
+	return r <= ' ' || r == 0x7f
+}
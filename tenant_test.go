@@ -0,0 +1,120 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"runtime"
+	"strconv"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheTenantIsolation(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		tenantcache {
+			prefix_invalidation {
+				enable = true
+			}
+		}
+	}
+`, &cache.Config{Name: "tenantcache", ProviderName: "memcache1"}).(Cache)
+
+	acme, err := c.ForTenant("acme")
+	assert.Nil(t, err)
+	globex, err := c.ForContext(WithTenant(context.Background(), "globex"))
+	assert.Nil(t, err)
+	assert.Equal(t, "acme", acme.Tenant())
+	assert.Equal(t, "", c.Tenant())
+
+	assert.Nil(t, acme.Put("key1", "acme value", time.Minute))
+	assert.Nil(t, globex.Put("key1", "globex value", time.Minute))
+	assert.Nil(t, c.Get("key1"))
+	assert.Equal(t, "acme value", acme.Get("key1"))
+	assert.Equal(t, "globex value", globex.Get("key1"))
+
+	same, _ := c.ForTenant("acme")
+	assert.Equal(t, acme, same)
+	assert.Equal(t, acme.Get("key1"), same.Get("key1"))
+
+	// tenant flush leaves the other tenants as is
+	assert.Nil(t, acme.Flush())
+	assert.Nil(t, acme.Get("key1"))
+	assert.Equal(t, "globex value", globex.Get("key1"))
+	assert.Nil(t, globex.Flush())
+}
+
+func TestMemcacheTenantFlushUnsupported(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "tenantnoflush", ProviderName: "memcache1"}).(Cache)
+
+	acme, err := c.ForTenant("acme")
+	assert.Nil(t, err)
+	assert.Equal(t, ErrTenantFlush, acme.Flush())
+}
+
+func TestMemcacheTenantKey(t *testing.T) {
+	root := &memcacheCache{cfg: &cache.Config{Name: "tenantkey"}, keyPrefix: "tenantkey-"}
+	m := &memcacheCache{cfg: root.cfg, keyPrefix: root.keyPrefix, parent: root, tenant: "acme:"}
+	assert.Equal(t, "tenantkey-acme:key1", m.key("key1"))
+	assert.Equal(t, "tenantkey-key1", root.key("key1"))
+	assert.Equal(t, "acme", m.Tenant())
+	assert.True(t, m.root() == root)
+	assert.True(t, root.root() == root)
+
+	for _, id := range []string{"", "ac me", "acme:1", "acme\n"} {
+		_, err := root.ForTenant(id)
+		assert.Equal(t, ErrInvalidTenant, err, id)
+	}
+
+	_, err := root.ForContext(context.Background())
+	assert.Equal(t, ErrNoTenant, err)
+	_, found := TenantFromContext(WithTenant(context.Background(), ""))
+	assert.False(t, found)
+	id, found := TenantFromContext(WithTenant(context.Background(), "acme"))
+	assert.True(t, found)
+	assert.Equal(t, "acme", id)
+}
+
+func TestMemcacheTenantShared(t *testing.T) {
+	root := newBenchCache()
+	root.wq = &writeQueue{m: root, ch: make(chan *writeOp, 10), queued: make(map[string]*writeOp)}
+	root.hot = &hotKeys{}
+	root.registry = newKeyRegistry(10)
+
+	before := runtime.NumGoroutine()
+	for i := 0; i < 10; i++ {
+		c, err := root.ForTenant("acme" + strconv.Itoa(i))
+		assert.Nil(t, err)
+		tc := c.(*memcacheCache)
+		assert.True(t, tc.wq == root.wq)
+		assert.True(t, tc.hot == root.hot)
+		assert.True(t, tc.counters == root.counters)
+		assert.False(t, tc.registry == root.registry)
+		assert.Equal(t, 10, tc.registry.max)
+	}
+	assert.Equal(t, before, runtime.NumGoroutine())
+
+	acme, _ := root.ForTenant("acme1")
+	assert.Nil(t, acme.Put("key1", "v", time.Minute))
+	assert.Equal(t, 1, len(root.wq.ch))
+	op := <-root.wq.ch
+	assert.Equal(t, "bench-acme1:key1", op.item.Key)
+	assert.True(t, op.m == acme)
+}
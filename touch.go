@@ -0,0 +1,132 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// toucher extends the expiration of the entries read in slide eviction mode
+// in the background, so Get does not wait for the touch. Touches of the same
+// key within the interval are deduped; when the pending touches reach
+// `max_pending`, touch is issued synchronously, or dropped with `drop`
+// overflow. Saturation is reported in `Stats.TouchQueueDepth` and
+// `Stats.TouchesDropped`.
+//
+//	cache {
+//	  mycache {
+//	    slide_touch {
+//	      # default value is true
+//	      async = true
+//	      # default value is 500ms
+//	      interval = "500ms"
+//	      # default value is 10000
+//	      max_pending = 10000
+//	      # sync or drop; default value is sync
+//	      overflow = "sync"
+//	    }
+//	  }
+//	}
+type toucher struct {
+	m          *memcacheCache
+	interval   time.Duration
+	maxPending int
+	drop       bool
+	once       sync.Once
+
+	mu      sync.Mutex
+	pending map[string]int32
+}
+
+func newToucher(m *memcacheCache) *toucher {
+	t := &toucher{
+		m:          m,
+		interval:   parseDuration(m.settingString("slide_touch.interval", ""), "500ms"),
+		maxPending: m.settingInt("slide_touch.max_pending", 10000),
+		drop:       m.settingString("slide_touch.overflow", overflowSync) == overflowDrop,
+		pending:    make(map[string]int32),
+	}
+	if t.interval <= 0 {
+		t.interval = 500 * time.Millisecond
+	}
+	return t
+}
+
+// touch method extends the expiration of given item key by d seconds.
+func (m *memcacheCache) touch(k, mk string, d int32) {
+	if m.toucher == nil {
+		m.touchNow(k, mk, d)
+		return
+	}
+	if m.toucher.enqueue(mk, d) {
+		return
+	}
+	if m.toucher.drop {
+		atomic.AddUint64(&m.counters.touchesDropped, 1)
+		return
+	}
+	m.touchNow(k, mk, d)
+}
+
+func (m *memcacheCache) touchNow(k, mk string, d int32) {
+	atomic.AddUint64(&m.counters.touches, 1)
+	err := m.client().Touch(mk, expirationOf(d))
+	if err == nil || err == memcache.ErrCacheMiss {
+		return
+	}
+	atomic.AddUint64(&m.counters.touchFailures, 1)
+	m.p.logError(m.opError("touch", k, mk, nil, err))
+}
+
+// enqueue method queues the touch, false if the queue is full.
+func (t *toucher) enqueue(mk string, d int32) bool {
+	t.once.Do(func() { go t.run() })
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, found := t.pending[mk]; !found && len(t.pending) >= t.maxPending {
+		return false
+	}
+	t.pending[mk] = d
+	return true
+}
+
+func (t *toucher) run() {
+	for range time.Tick(t.interval) {
+		t.flush()
+	}
+}
+
+func (t *toucher) flush() {
+	t.mu.Lock()
+	if len(t.pending) == 0 {
+		t.mu.Unlock()
+		return
+	}
+	batch := t.pending
+	t.pending = make(map[string]int32, len(batch))
+	t.mu.Unlock()
+
+	for mk, d := range batch {
+		t.m.touchNow("", mk, d)
+	}
+}
+
+// depth method returns the number of touches waiting for the flush.
+func (t *toucher) depth() int {
+	if t == nil {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.pending)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheToucherDedupe(t *testing.T) {
+	tc := &toucher{interval: time.Hour, maxPending: 2, pending: make(map[string]int32)}
+	assert.True(t, tc.enqueue("k1", 10))
+	assert.True(t, tc.enqueue("k1", 20))
+	assert.True(t, tc.enqueue("k2", 10))
+	assert.False(t, tc.enqueue("k3", 10))
+	assert.True(t, tc.enqueue("k2", 30))
+	assert.Equal(t, map[string]int32{"k1": 20, "k2": 30}, tc.pending)
+}
+
+func TestMemcacheAsyncTouch(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		touchcache {
+			slide_touch {
+				interval = "100ms"
+			}
+		}
+	}
+`, &cache.Config{Name: "touchcache", ProviderName: "memcache1", EvictionMode: cache.EvictionModeSlide}).(Cache)
+
+	assert.Nil(t, c.Put("key1", "value1", 2*time.Second))
+	for i := 0; i < 5; i++ {
+		assert.Equal(t, "value1", c.Get("key1"))
+	}
+	time.Sleep(300 * time.Millisecond)
+	assert.Equal(t, uint64(1), c.Stats().Touches)
+	assert.Equal(t, uint64(0), c.Stats().TouchFailures)
+
+	c.Flush()
+}
+
+func TestMemcacheToucherDrop(t *testing.T) {
+	m := &memcacheCache{counters: new(counters)}
+	m.toucher = &toucher{m: m, interval: time.Hour, maxPending: 1, drop: true, pending: make(map[string]int32)}
+	m.touch("k1", "k1", 10)
+	m.touch("k2", "k2", 10)
+
+	s := m.Stats()
+	assert.Equal(t, 1, s.TouchQueueDepth)
+	assert.Equal(t, uint64(1), s.TouchesDropped)
+	assert.Equal(t, uint64(0), s.Touches)
+}
@@ -0,0 +1,121 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"hash/fnv"
+	"strconv"
+)
+
+// Span attribute keys set by the provider.
+const (
+	AttrCacheName = "cache.name"
+	AttrKeyHash   = "cache.key_hash"
+	AttrKeyCount  = "cache.key_count"
+	AttrServer    = "cache.server"
+	AttrHit       = "cache.hit"
+	AttrBytes     = "cache.bytes"
+)
+
+// Tracer interface is used by the provider to emit a span for each cache
+// operation, so cache latency shows up in distributed traces. Context
+// operations, e.g. `GetContext`, start the span with the given context, so
+// it is the child span of the request trace; other operations start it with
+// `context.Background()`. It is deliberately small, OpenTelemetry
+// `trace.TracerProvider` is adapted to it in a few lines, e.g.
+//
+//	type otelTracer struct{ t trace.Tracer }
+//
+//	func (o otelTracer) StartSpan(ctx context.Context, op string) memcache.Span {
+//	  _, s := o.t.Start(ctx, "memcache."+op,
+//	      trace.WithSpanKind(trace.SpanKindClient))
+//	  return otelSpan{s}
+//	}
+//
+//	type otelSpan struct{ s trace.Span }
+//
+//	func (o otelSpan) SetAttribute(k string, v interface{}) {
+//	  o.s.SetAttributes(attribute.String(k, fmt.Sprint(v)))
+//	}
+//
+//	func (o otelSpan) End(err error) {
+//	  if err != nil {
+//	    o.s.RecordError(err)
+//	    o.s.SetStatus(codes.Error, err.Error())
+//	  }
+//	  o.s.End()
+//	}
+//
+//	provider.SetTracer(otelTracer{otel.GetTracerProvider().Tracer("aah/cache")})
+type Tracer interface {
+	StartSpan(ctx context.Context, op string) Span
+}
+
+// Span interface represents single traced cache operation.
+type Span interface {
+	SetAttribute(key string, value interface{})
+	End(err error)
+}
+
+// SetTracer method sets the tracer of the provider, spans are emitted for
+// Get, Put, Delete and GetMulti operations of all the caches created by the
+// provider. Passing nil disables tracing.
+func (p *Provider) SetTracer(t Tracer) {
+	p.tracer.Store(tracerHolder{t: t})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// tracerHolder wraps the tracer, `atomic.Value` requires consistent
+// concrete type.
+type tracerHolder struct {
+	t Tracer
+}
+
+type noopSpan struct{}
+
+func (noopSpan) SetAttribute(string, interface{}) {}
+func (noopSpan) End(error)                        {}
+
+// startSpan method starts the span for given operation and key within given
+// context, key is hashed to keep user data out of traces. Empty key is not
+// recorded.
+func (m *memcacheCache) startSpan(ctx context.Context, op, k string) Span {
+	th, _ := m.p.tracer.Load().(tracerHolder)
+	if th.t == nil {
+		return noopSpan{}
+	}
+	s := th.t.StartSpan(ctx, op)
+	s.SetAttribute(AttrCacheName, m.Name())
+	if k != "" {
+		s.SetAttribute(AttrKeyHash, hashKey(k))
+		if addr := m.p.serverAddr(m.key(k)); addr != "" {
+			s.SetAttribute(AttrServer, addr)
+		}
+	}
+	return s
+}
+
+// serverAddr method returns the address of memcache server for given
+// memcache key.
+func (p *Provider) serverAddr(mk string) string {
+	if p.servers == nil {
+		return ""
+	}
+	addr, err := p.servers.PickServer(mk)
+	if err != nil {
+		return ""
+	}
+	return addr.String()
+}
+
+func hashKey(k string) string {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(k))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
@@ -0,0 +1,100 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (tt *testTracer) StartSpan(ctx context.Context, op string) Span {
+	tt.mu.Lock()
+	defer tt.mu.Unlock()
+	s := &testSpan{ctx: ctx, op: op, attrs: make(map[string]interface{})}
+	tt.spans = append(tt.spans, s)
+	return s
+}
+
+type testSpan struct {
+	ctx   context.Context
+	op    string
+	attrs map[string]interface{}
+	ended bool
+	err   error
+}
+
+func (ts *testSpan) SetAttribute(k string, v interface{}) { ts.attrs[k] = v }
+func (ts *testSpan) End(err error)                        { ts.ended, ts.err = true, err }
+
+func TestMemcacheTracing(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "tracecache", ProviderName: "memcache1"}))
+	c := mgr.Cache("tracecache").(Cache)
+
+	tt := new(testTracer)
+	mgr.Provider("memcache1").(*Provider).SetTracer(tt)
+
+	assert.Nil(t, c.Put("key1", "value1", 3*time.Second))
+	assert.Equal(t, "value1", c.Get("key1"))
+	assert.Nil(t, c.Get("key2"))
+	_ = c.GetMulti([]string{"key1", "key2"})
+	assert.Nil(t, c.Delete("key1"))
+
+	assert.Equal(t, 5, len(tt.spans))
+	ops := []string{"put", "get", "get", "getmulti", "delete"}
+	for i, s := range tt.spans {
+		assert.Equal(t, ops[i], s.op)
+		assert.True(t, s.ended)
+		assert.Equal(t, "tracecache", s.attrs[AttrCacheName])
+	}
+	assert.Equal(t, hashKey("key1"), tt.spans[0].attrs[AttrKeyHash])
+	assert.NotNil(t, tt.spans[0].attrs[AttrServer])
+	assert.Equal(t, true, tt.spans[1].attrs[AttrHit])
+	assert.Equal(t, false, tt.spans[2].attrs[AttrHit])
+	assert.Equal(t, 2, tt.spans[3].attrs[AttrKeyCount])
+
+	mgr.Provider("memcache1").(*Provider).SetTracer(nil)
+	c.Flush()
+}
+
+type traceCtxKey struct{}
+
+func TestMemcacheTracingContext(t *testing.T) {
+	m := newBenchCache()
+	tt := new(testTracer)
+	m.p.SetTracer(tt)
+
+	ctx := context.WithValue(context.Background(), traceCtxKey{}, "req-1")
+	_, _ = m.GetContext(ctx, "key1")
+	_ = m.PutContext(ctx, "key1", "value1", time.Minute)
+	_ = m.DeleteContext(ctx, "key1")
+	_, _ = m.GetMultiContext(ctx, []string{"key1", "key2"})
+	_ = m.Get("key1")
+
+	assert.Equal(t, 5, len(tt.spans))
+	ops := []string{"get", "put", "delete", "getmulti"}
+	for i, op := range ops {
+		assert.Equal(t, op, tt.spans[i].op)
+		assert.Equal(t, "req-1", tt.spans[i].ctx.Value(traceCtxKey{}))
+	}
+	assert.Nil(t, tt.spans[4].ctx.Value(traceCtxKey{}))
+}
@@ -0,0 +1,173 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NoExpiration duration explicitly stores the entry without expiration
+// regardless of the `zero_ttl` policy, `max_ttl` still applies.
+const NoExpiration time.Duration = -1
+
+// ErrZeroTTL error is returned when the cache `zero_ttl` policy is `reject`
+// and the caller passes 0 duration.
+var ErrZeroTTL = errors.New("aah/cache: zero duration is rejected, use explicit duration or NoExpiration")
+
+const (
+	zeroTTLDefault = "default"
+	zeroTTLNever   = "never"
+	zeroTTLReject  = "reject"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// ttlPolicy applies the cache level TTL settings to the requested duration
+// of the entry. Duration over 30 days is sent to memcache as absolute unix
+// time, see `expirationOf`.
+//
+//	cache {
+//	  mycache {
+//	    # meaning of 0 duration passed by the caller, supported values are
+//	    #   default - use default_ttl, no expiration if it is not set
+//	    #   never   - no expiration
+//	    #   reject  - returns ErrZeroTTL
+//	    # default value is default
+//	    zero_ttl = "default"
+//
+//	    # applied when the caller passes 0 duration; default value is 0s,
+//	    # entry does not expire
+//	    default_ttl = "10m"
+//
+//	    # caps the requested duration, including 0 duration i.e. no expiration;
+//	    # default value is 0s, disabled
+//	    max_ttl = "24h"
+//
+//	    # randomizes the expiration within +/- band, so that mass-written
+//	    # entries do not expire at the same second. Percentage of the TTL or
+//	    # duration; default value is 0, disabled
+//	    ttl_jitter = "10%"
+//	  }
+//	}
+type ttlPolicy struct {
+	zero      string
+	def       time.Duration
+	max       time.Duration
+	jitter    float64
+	jitterDur time.Duration
+}
+
+func newTTLPolicy(m *memcacheCache) (ttlPolicy, error) {
+	tp := ttlPolicy{zero: m.settingString("zero_ttl", zeroTTLDefault)}
+	switch tp.zero {
+	case zeroTTLDefault, zeroTTLNever, zeroTTLReject:
+	default:
+		return tp, fmt.Errorf("aah/cache/%s: unsupported zero_ttl '%s'", m.Name(), tp.zero)
+	}
+	var err error
+	if tp.def, err = m.settingTTL("default_ttl"); err != nil {
+		return tp, err
+	}
+	if tp.max, err = m.settingTTL("max_ttl"); err != nil {
+		return tp, err
+	}
+	if v := strings.TrimSpace(m.settingString("ttl_jitter", "")); v != "" {
+		if strings.HasSuffix(v, "%") {
+			f, err := strconv.ParseFloat(strings.TrimSuffix(v, "%"), 64)
+			if err != nil || f < 0 || f > 100 {
+				return tp, fmt.Errorf("aah/cache/%s: invalid ttl_jitter '%s'", m.Name(), v)
+			}
+			tp.jitter = f / 100
+		} else {
+			d, err := time.ParseDuration(v)
+			if err != nil || d < 0 {
+				return tp, fmt.Errorf("aah/cache/%s: invalid ttl_jitter '%s'", m.Name(), v)
+			}
+			tp.jitterDur = d
+		}
+	}
+	return tp, nil
+}
+
+// apply method returns the effective duration for the requested one, 0 is
+// no expiration.
+func (tp ttlPolicy) apply(d time.Duration) (time.Duration, error) {
+	switch {
+	case d == NoExpiration:
+		return tp.clamp(0), nil
+	case d == 0 && tp.zero == zeroTTLReject:
+		return 0, ErrZeroTTL
+	case d == 0 && tp.zero == zeroTTLNever:
+		return tp.clamp(0), nil
+	case d == 0:
+		d = tp.def
+	}
+	if d <= 0 {
+		return tp.clamp(d), nil
+	}
+	band := tp.jitterDur
+	if tp.jitter > 0 {
+		band = time.Duration(float64(d) * tp.jitter)
+	}
+	if band > 0 {
+		d += time.Duration(rand.Int63n(int64(2*band)+1)) - band
+		if d < time.Second {
+			d = time.Second
+		}
+	}
+	return tp.clamp(d), nil
+}
+
+// clamp method caps the duration to `max_ttl`.
+func (tp ttlPolicy) clamp(d time.Duration) time.Duration {
+	if tp.max > 0 && (d == 0 || d > tp.max) {
+		return tp.max
+	}
+	return d
+}
+
+// expirationOf function returns the memcache expiration of given duration
+// in seconds. Memcache reads the expiration over 30 days as absolute unix
+// time, so the longer duration is converted to it.
+func expirationOf(d int32) int32 {
+	if d <= maxRelativeExpiration {
+		return d
+	}
+	exp := time.Now().Unix() + int64(d)
+	if exp > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	return int32(exp)
+}
+
+func (m *memcacheCache) settingTTL(key string) (time.Duration, error) {
+	v := m.settingString(key, "")
+	if v == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("aah/cache/%s: invalid %s '%s'", m.Name(), key, v)
+	}
+	return d, nil
+}
+
+// newEntry method returns the cache entry for given key and value with the
+// TTL policy applied.
+func (m *memcacheCache) newEntry(k string, v interface{}, d time.Duration) (*entry, error) {
+	d, err := m.ttl.apply(d)
+	if err != nil {
+		return nil, m.opError("put", k, "", nil, err)
+	}
+	return newEntry(v, d), nil
+}
@@ -0,0 +1,112 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheTTLJitter(t *testing.T) {
+	tp := ttlPolicy{jitter: 0.1}
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 100; i++ {
+		d := mustApply(t, tp, 100*time.Second)
+		assert.True(t, d >= 90*time.Second && d <= 110*time.Second, "out of band %v", d)
+		seen[d] = true
+	}
+	assert.True(t, len(seen) > 1)
+
+	tp = ttlPolicy{jitterDur: 5 * time.Second}
+	d := mustApply(t, tp, 2*time.Second)
+	assert.True(t, d >= time.Second && d <= 7*time.Second)
+
+	assert.Equal(t, time.Duration(0), mustApply(t, tp, 0))
+	assert.Equal(t, time.Minute, mustApply(t, ttlPolicy{}, time.Minute))
+}
+
+func TestMemcacheDefaultTTL(t *testing.T) {
+	tp := ttlPolicy{def: 10 * time.Minute}
+	assert.Equal(t, 10*time.Minute, mustApply(t, tp, 0))
+	assert.Equal(t, time.Minute, mustApply(t, tp, time.Minute))
+
+	e, err := (&memcacheCache{ttl: tp}).newEntry("key1", "value1", 0)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(600), e.D)
+}
+
+func TestMemcacheMaxTTL(t *testing.T) {
+	tp := ttlPolicy{max: time.Hour}
+	assert.Equal(t, time.Hour, mustApply(t, tp, 0))
+	assert.Equal(t, time.Hour, mustApply(t, tp, 48*time.Hour))
+	assert.Equal(t, time.Minute, mustApply(t, tp, time.Minute))
+
+	tp = ttlPolicy{max: time.Hour, jitter: 0.5}
+	for i := 0; i < 20; i++ {
+		assert.True(t, mustApply(t, tp, time.Hour) <= time.Hour)
+	}
+}
+
+func TestMemcacheTTLJitterInvalid(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		jittercache {
+			ttl_jitter = "150%"
+		}
+	}
+`)
+	err := mgr.CreateCache(&cache.Config{Name: "jittercache", ProviderName: "memcache1"})
+	assert.Equal(t, "aah/cache/jittercache: invalid ttl_jitter '150%'", err.Error())
+}
+
+func TestMemcacheZeroTTLPolicy(t *testing.T) {
+	tp := ttlPolicy{zero: zeroTTLNever, def: time.Minute}
+	assert.Equal(t, time.Duration(0), mustApply(t, tp, 0))
+	assert.Equal(t, time.Duration(0), mustApply(t, tp, NoExpiration))
+
+	tp = ttlPolicy{zero: zeroTTLReject}
+	_, err := tp.apply(0)
+	assert.Equal(t, ErrZeroTTL, err)
+	assert.Equal(t, time.Duration(0), mustApply(t, tp, NoExpiration))
+
+	tp = ttlPolicy{zero: zeroTTLDefault, def: time.Minute, max: time.Hour}
+	assert.Equal(t, time.Minute, mustApply(t, tp, 0))
+	assert.Equal(t, time.Hour, mustApply(t, tp, NoExpiration))
+
+	m := &memcacheCache{cfg: &cache.Config{Name: "zerottl"}, p: &Provider{}, ttl: ttlPolicy{zero: zeroTTLReject}}
+	_, err = m.newEntry("key1", "value1", 0)
+	assert.True(t, errors.Is(err, ErrZeroTTL))
+}
+
+func mustApply(t *testing.T, tp ttlPolicy, d time.Duration) time.Duration {
+	ed, err := tp.apply(d)
+	assert.Nil(t, err)
+	return ed
+}
+
+func TestMemcacheExpirationOf(t *testing.T) {
+	assert.Equal(t, int32(0), expirationOf(0))
+	assert.Equal(t, int32(maxRelativeExpiration), expirationOf(maxRelativeExpiration))
+
+	d := int32(60 * 24 * time.Hour / time.Second)
+	exp := int64(expirationOf(d))
+	now := time.Now().Unix()
+	assert.True(t, exp >= now+int64(d)-1 && exp <= now+int64(d)+1)
+	assert.Equal(t, int32(math.MaxInt32), expirationOf(math.MaxInt32))
+
+	m := newBenchCache()
+	item, err := m.encodeEntry("key1", newEntry("v", 60*24*time.Hour))
+	assert.Nil(t, err)
+	assert.True(t, int64(item.Expiration) > now)
+}
@@ -0,0 +1,57 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import "time"
+
+// RetryPolicy struct holds the retry settings of the `retry` middleware.
+type RetryPolicy struct {
+	// Max is the number of retries after the failed attempt.
+	Max int
+
+	// Backoff is the wait before the first retry, it grows linearly.
+	Backoff time.Duration
+}
+
+// SetTimeout method sets the socket read/write timeout of the memcache
+// client at runtime, e.g. from an ops dashboard during an incident.
+// Operations in-flight complete with the previous timeout.
+func (p *Provider) SetTimeout(d time.Duration) {
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	c := p.Client()
+	p.client.Store(p.newClient(d, c.MaxIdleConns))
+	p.logger.Infof("aah/cache/provider: %s timeout set to %v", p.name, d)
+}
+
+// SetMaxIdleConns method sets the max idle connections per server of the
+// memcache client at runtime. Idle connections of the previous client are
+// not reused.
+func (p *Provider) SetMaxIdleConns(n int) {
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+	c := p.Client()
+	p.client.Store(p.newClient(c.Timeout, n))
+	p.logger.Infof("aah/cache/provider: %s max_idle_conns set to %d", p.name, n)
+}
+
+// SetRetryPolicy method overrides the retry settings of the `retry`
+// middleware configured via `middlewares` setting, for all the caches of the
+// provider. It applies to the operations started afterwards. Middlewares
+// added by `RetryMiddleware` keep their settings.
+func (p *Provider) SetRetryPolicy(policy RetryPolicy) {
+	p.retry.Store(&policy)
+	p.logger.Infof("aah/cache/provider: %s retry policy set to max %d, backoff %v",
+		p.name, policy.Max, policy.Backoff)
+}
+
+// RetryPolicy method returns the retry policy set by `SetRetryPolicy`,
+// false if not set.
+func (p *Provider) RetryPolicy() (RetryPolicy, bool) {
+	if policy, ok := p.retry.Load().(*RetryPolicy); ok {
+		return *policy, true
+	}
+	return RetryPolicy{}, false
+}
@@ -0,0 +1,51 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"testing"
+	"time"
+
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheSetTimeoutMaxIdleConns(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l, servers: new(memcache.ServerList)}
+	p.client.Store(p.newClient(time.Second, 2))
+
+	p.SetTimeout(3 * time.Second)
+	assert.Equal(t, 3*time.Second, p.Client().Timeout)
+	assert.Equal(t, 2, p.Client().MaxIdleConns)
+
+	p.SetMaxIdleConns(10)
+	assert.Equal(t, 3*time.Second, p.Client().Timeout)
+	assert.Equal(t, 10, p.Client().MaxIdleConns)
+}
+
+func TestMemcacheSetRetryPolicy(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	p := &Provider{name: "memcache1", logger: l}
+	_, found := p.RetryPolicy()
+	assert.False(t, found)
+
+	f := &failingCache{fails: 3}
+	r := RetryMiddleware(1, time.Millisecond)(f).(*retryCache)
+	r.p = p
+	assert.Equal(t, errFailing, r.Put("key1", "value1", time.Second))
+	assert.Equal(t, 2, f.attempts)
+
+	p.SetRetryPolicy(RetryPolicy{Max: 3, Backoff: time.Millisecond})
+	policy, found := p.RetryPolicy()
+	assert.True(t, found)
+	assert.Equal(t, 3, policy.Max)
+
+	f.attempts = 0
+	assert.Nil(t, r.Put("key1", "value1", time.Second))
+	assert.Equal(t, 4, f.attempts)
+}
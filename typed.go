@@ -0,0 +1,83 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+
+package memcache
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"aahframe.work/cache"
+)
+
+// TypedCache type wraps the cache with the values of type T, so that the app
+// code does not type assert the values.
+type TypedCache[T any] struct {
+	c cache.Cache
+}
+
+// Typed function returns the typed wrapper of given cache, it requires Go
+// 1.18 or later.
+//
+//	users := memcache.Typed[User](c)
+//	u, found, err := users.Get("user:1")
+func Typed[T any](c cache.Cache) *TypedCache[T] {
+	return &TypedCache[T]{c: c}
+}
+
+// Get method returns the value of given key, found is false on miss. Error
+// is returned if the cached value is not of type T. Values convertible to T
+// are converted, with `strict_types` enabled `ErrTypeMismatch` is returned
+// for them.
+func (t *TypedCache[T]) Get(k string) (T, bool, error) {
+	var v T
+	cv := t.c.Get(k)
+	if cv == nil {
+		return v, false, nil
+	}
+	if tv, ok := cv.(T); ok {
+		return tv, true, nil
+	}
+	if cv == Nil {
+		return v, true, nil
+	}
+	if rt := reflect.TypeOf(&v).Elem(); isStrict(t.c) && !typeMatches(schemaHint(cv), rt) {
+		return v, false, fmt.Errorf("aah/cache/%s: key(%s) %w", t.c.Name(), logKeyOf(t.c, k),
+			&TypeMismatchError{Stored: schemaHint(cv), Expected: rt.String()})
+	}
+	if err := assign(reflect.ValueOf(&v).Elem(), cv); err != nil {
+		return v, false, fmt.Errorf("aah/cache/%s: key(%s) %w", t.c.Name(), logKeyOf(t.c, k), err)
+	}
+	return v, true, nil
+}
+
+// Put method adds the value of given key with given expiration, type T is
+// registered with gob.
+func (t *TypedCache[T]) Put(k string, v T, d time.Duration) error {
+	registerGob(v)
+	return t.c.Put(k, v, d)
+}
+
+// GetOrLoad method returns the value of given key, on miss it calls given
+// func and puts its result. With the memcache cache it goes via `Fetch`, so
+// concurrent loads are coalesced and `ErrNotFound` is cached as negative
+// entry.
+func (t *TypedCache[T]) GetOrLoad(k string, d time.Duration, fn func() (T, error)) (T, error) {
+	var v T
+	if mc, ok := Extended(t.c); ok {
+		err := mc.Query(k, d, &v, func() (interface{}, error) { return fn() })
+		return v, err
+	}
+	v, found, err := t.Get(k)
+	if found || err != nil {
+		return v, err
+	}
+	if v, err = fn(); err != nil {
+		return v, err
+	}
+	return v, t.Put(k, v, d)
+}
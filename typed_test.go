@@ -0,0 +1,76 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build go1.18
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+type mapCache struct {
+	cache.Cache
+	m map[string]interface{}
+}
+
+func (c *mapCache) Name() string { return "map" }
+
+func (c *mapCache) Get(k string) interface{} { return c.m[k] }
+
+func (c *mapCache) Put(k string, v interface{}, d time.Duration) error {
+	c.m[k] = v
+	return nil
+}
+
+func TestMemcacheTyped(t *testing.T) {
+	c := &mapCache{m: map[string]interface{}{"text": "hello"}}
+	users := Typed[queryUser](c)
+
+	_, found, err := users.Get("user:1")
+	assert.Nil(t, err)
+	assert.False(t, found)
+
+	assert.Nil(t, users.Put("user:1", queryUser{ID: 1, Name: "jeeva"}, time.Minute))
+	u, found, err := users.Get("user:1")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, "jeeva", u.Name)
+
+	_, _, err = users.Get("text")
+	assert.NotNil(t, err)
+
+	calls := 0
+	load := func() (queryUser, error) {
+		calls++
+		return queryUser{ID: 2}, nil
+	}
+	u, err = users.GetOrLoad("user:2", time.Minute, load)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, u.ID)
+	_, _ = users.GetOrLoad("user:2", time.Minute, load)
+	assert.Equal(t, 1, calls)
+
+	ferr := errors.New("db down")
+	_, err = users.GetOrLoad("user:3", time.Minute, func() (queryUser, error) { return queryUser{}, ferr })
+	assert.Equal(t, ferr, err)
+
+	ids := Typed[int64](c)
+	assert.Nil(t, c.Put("id", 42, 0))
+	id, found, err := ids.Get("id")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, int64(42), id)
+
+	assert.Nil(t, c.Put("user:4", Nil, 0))
+	u, found, err = users.Get("user:4")
+	assert.Nil(t, err)
+	assert.True(t, found)
+	assert.Equal(t, queryUser{}, u)
+}
@@ -0,0 +1,107 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ErrTooManyConflicts error is returned by `TryUpdate` when the update did
+// not succeed within max retries.
+var ErrTooManyConflicts = errors.New("aah/cache: too many update conflicts")
+
+// UpdateFunc type computes the new value and its expiration from the current
+// cached value, found is false if the key does not exist. It is called again
+// on every conflict, so it must not have side effects. Returning error aborts
+// the update.
+type UpdateFunc func(current interface{}, found bool) (interface{}, time.Duration, error)
+
+// BackoffFunc type returns the wait duration before given retry attempt,
+// attempt starts from 1.
+type BackoffFunc func(attempt int) time.Duration
+
+// ExponentialBackoff function returns the backoff doubling from given base
+// up to max, with full jitter.
+func ExponentialBackoff(base, max time.Duration) BackoffFunc {
+	return func(attempt int) time.Duration {
+		d := base << uint(attempt-1)
+		if d <= 0 || d > max {
+			d = max
+		}
+		return time.Duration(rand.Int63n(int64(d) + 1))
+	}
+}
+
+// TryUpdate method updates the value of given key with the result of given
+// func using the Gets/CAS loop. On conflict the update is retried up to maxRetries times after the
+// backoff, it returns the number of conflicts encountered. Default backoff
+// is exponential from `cas_retry.backoff` up to `cas_retry.max_backoff`.
+//
+//	cache {
+//	  mycache {
+//	    cas_retry {
+//	      # default value is 5ms
+//	      backoff = "5ms"
+//	      # default value is 200ms
+//	      max_backoff = "200ms"
+//	    }
+//	  }
+//	}
+func (m *memcacheCache) TryUpdate(k string, fn UpdateFunc, maxRetries int, backoff ...BackoffFunc) (int, error) {
+	bf := m.casBackoff()
+	if len(backoff) > 0 && backoff[0] != nil {
+		bf = backoff[0]
+	}
+	return m.tryUpdate(k, fn, maxRetries, bf, m.PutCAS)
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// casBackoff method returns the default backoff of `TryUpdate`.
+func (m *memcacheCache) casBackoff() BackoffFunc {
+	return ExponentialBackoff(
+		parseDuration(m.settingString("cas_retry.backoff", ""), "5ms"),
+		parseDuration(m.settingString("cas_retry.max_backoff", ""), "200ms"))
+}
+
+// tryUpdate method is the Gets/CAS loop of `TryUpdate` storing the value
+// with given put func.
+func (m *memcacheCache) tryUpdate(k string, fn UpdateFunc, maxRetries int, bf BackoffFunc,
+	put func(k string, v interface{}, d time.Duration, token CASToken) error) (int, error) {
+	conflicts := 0
+	for {
+		cur, token, err := m.Gets(k)
+		found := err == nil
+		if err != nil && !errors.Is(err, ErrMiss) {
+			return conflicts, err
+		}
+		v, d, err := fn(cur, found)
+		if err != nil {
+			return conflicts, err
+		}
+		err = put(k, v, d, token)
+		if !isConflict(err) {
+			return conflicts, err
+		}
+		conflicts++
+		if conflicts > maxRetries {
+			return conflicts, m.opError("put", k, m.key(k), nil, ErrTooManyConflicts)
+		}
+		time.Sleep(bf(conflicts))
+	}
+}
+
+// isConflict function reports whether the CAS store lost the race, value was
+// modified, deleted or added meanwhile.
+func isConflict(err error) bool {
+	return errors.Is(err, memcache.ErrCASConflict) || errors.Is(err, memcache.ErrNotStored) ||
+		errors.Is(err, memcache.ErrCacheMiss)
+}
@@ -0,0 +1,86 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheTryUpdate(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "tryupdate", ProviderName: "memcache1"}).(Cache)
+
+	incr := func(cur interface{}, found bool) (interface{}, time.Duration, error) {
+		if !found {
+			return 1, time.Minute, nil
+		}
+		return cur.(int) + 1, time.Minute, nil
+	}
+	conflicts, err := c.TryUpdate("key1", incr, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, conflicts)
+	assert.Equal(t, 1, c.Get("key1"))
+
+	// concurrent writer wins the first attempt
+	raced := false
+	conflicts, err = c.TryUpdate("key1", func(cur interface{}, found bool) (interface{}, time.Duration, error) {
+		if !raced {
+			raced = true
+			assert.Nil(t, c.Put("key1", 10, time.Minute))
+		}
+		return incr(cur, found)
+	}, 3)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, conflicts)
+	assert.Equal(t, 11, c.Get("key1"))
+
+	// every attempt conflicts
+	n := 0
+	conflicts, err = c.TryUpdate("key1", func(cur interface{}, found bool) (interface{}, time.Duration, error) {
+		n++
+		assert.Nil(t, c.Put("key1", n, time.Minute))
+		return incr(cur, found)
+	}, 2, func(int) time.Duration { return 0 })
+	assert.True(t, errors.Is(err, ErrTooManyConflicts))
+	assert.Equal(t, 3, conflicts)
+
+	errAbort := errors.New("abort")
+	_, err = c.TryUpdate("key1", func(interface{}, bool) (interface{}, time.Duration, error) {
+		return nil, 0, errAbort
+	}, 2)
+	assert.Equal(t, errAbort, err)
+
+	assert.Nil(t, c.Delete("key1"))
+}
+
+func TestMemcacheExponentialBackoff(t *testing.T) {
+	bf := ExponentialBackoff(10*time.Millisecond, 50*time.Millisecond)
+	for attempt := 1; attempt < 70; attempt++ {
+		d := bf(attempt)
+		assert.True(t, d >= 0)
+		assert.True(t, d <= 50*time.Millisecond)
+	}
+	assert.True(t, bf(1) <= 10*time.Millisecond)
+}
+
+func TestMemcacheIsConflict(t *testing.T) {
+	assert.True(t, isConflict(memcache.ErrCASConflict))
+	assert.True(t, isConflict(memcache.ErrNotStored))
+	assert.True(t, isConflict(memcache.ErrCacheMiss))
+	assert.False(t, isConflict(nil))
+	assert.False(t, isConflict(memcache.ErrServerError))
+}
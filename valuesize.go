@@ -0,0 +1,136 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// ErrValueTooLarge error class reports the encoded cache value exceeds the
+// cache `max_value_size`.
+var ErrValueTooLarge = errors.New("aah/cache: value too large")
+
+// errLargeValue is the log limiter class of the large value warnings.
+var errLargeValue = errors.New("large value")
+
+const (
+	oversizeReject = "reject"
+	oversizeSkip   = "skip"
+	oversizeChunk  = "chunk"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+// sizeLimit enforces the encoded value size of the cache entries.
+//
+//	cache {
+//	  mycache {
+//	    # size in bytes or with KB, MB suffix; default value is 0, disabled
+//	    max_value_size = "1MB"
+//	    # supported values are
+//	    #   reject - Put returns error of class ErrValueTooLarge
+//	    #   skip   - entry is not stored, counted in Metrics.OversizeSkipped
+//	    #   chunk  - entry is stored as chunks, see chunk_size
+//	    # default value is reject
+//	    oversize_policy = "reject"
+//	  }
+//	}
+//
+// Encoded value sizes are recorded in `Metrics.ValueSizes`. Values nearing
+// the memcache item size limit of 1MB are counted in `Metrics.LargeValues`
+// and logged at WARN level, at most once per `error_log.interval`.
+//
+//	cache {
+//	  mycache {
+//	    # size in bytes or with KB, MB suffix; default value is 900KB, 0 disables
+//	    value_size_warn = "900KB"
+//	  }
+//	}
+type sizeLimit struct {
+	max    int
+	warn   int
+	policy string
+}
+
+func newSizeLimit(m *memcacheCache) (sizeLimit, error) {
+	sl := sizeLimit{policy: m.settingString("oversize_policy", oversizeReject)}
+	switch sl.policy {
+	case oversizeReject, oversizeSkip, oversizeChunk:
+	default:
+		return sl, fmt.Errorf("aah/cache/%s: unsupported oversize_policy '%s'", m.Name(), sl.policy)
+	}
+	var err error
+	w := m.settingString("value_size_warn", "900KB")
+	if sl.warn, err = parseSize(w); err != nil {
+		return sl, fmt.Errorf("aah/cache/%s: invalid value_size_warn '%s'", m.Name(), w)
+	}
+	v := m.settingString("max_value_size", "")
+	if v == "" {
+		return sl, nil
+	}
+	n, err := parseSize(v)
+	if err != nil {
+		return sl, fmt.Errorf("aah/cache/%s: invalid max_value_size '%s'", m.Name(), v)
+	}
+	sl.max = n
+	return sl, nil
+}
+
+// checkSize method applies the oversize policy to the encoded item, it
+// reports whether the item to be skipped.
+func (m *memcacheCache) checkSize(k string, item *memcache.Item) (bool, error) {
+	m.recordSize(k, item)
+	if m.size.max <= 0 || len(item.Value) <= m.size.max || m.size.policy == oversizeChunk {
+		return false, nil
+	}
+	if m.size.policy == oversizeSkip {
+		atomic.AddUint64(&m.counters.oversizeSkipped, 1)
+		return true, nil
+	}
+	return false, m.opError("put", k, item.Key, ErrValueTooLarge,
+		fmt.Errorf("value size %d exceeds max_value_size %d", len(item.Value), m.size.max))
+}
+
+// recordSize method records the encoded value size of the item and warns
+// about the large value.
+func (m *memcacheCache) recordSize(k string, item *memcache.Item) {
+	n := len(item.Value)
+	large := m.size.warn > 0 && n >= m.size.warn
+	m.counters.sized(n, large)
+	if !large {
+		return
+	}
+	if ok, suppressed := m.p.errlog.allow(errLargeValue, time.Now()); ok {
+		m.p.logger.Warnf("aah/cache/%s: key(%s) encoded value of %d bytes is near the memcache item size limit, large values since last warning(%d)",
+			m.Name(), m.logKey(k), n, suppressed)
+	}
+}
+
+func parseSize(v string) (int, error) {
+	s := strings.ToUpper(strings.TrimSpace(v))
+	mul := 1
+	switch {
+	case strings.HasSuffix(s, "KB"):
+		mul, s = 1024, strings.TrimSuffix(s, "KB")
+	case strings.HasSuffix(s, "MB"):
+		mul, s = 1024*1024, strings.TrimSuffix(s, "MB")
+	case strings.HasSuffix(s, "B"):
+		s = strings.TrimSuffix(s, "B")
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size '%s'", v)
+	}
+	return n * mul, nil
+}
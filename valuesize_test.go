@@ -0,0 +1,77 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheParseSize(t *testing.T) {
+	for v, n := range map[string]int{"512": 512, "100B": 100, "2KB": 2048, "1mb": 1048576, " 4 KB ": 4096} {
+		s, err := parseSize(v)
+		assert.Nil(t, err)
+		assert.Equal(t, n, s, v)
+	}
+	_, err := parseSize("1GB")
+	assert.NotNil(t, err)
+}
+
+func TestMemcacheCheckSize(t *testing.T) {
+	m := &memcacheCache{
+		cfg:      &cache.Config{Name: "sizecache"},
+		p:        &Provider{},
+		counters: new(counters),
+		size:     sizeLimit{max: 4, policy: oversizeReject},
+	}
+	skip, err := m.checkSize("key1", &memcache.Item{Value: []byte("1234")})
+	assert.False(t, skip)
+	assert.Nil(t, err)
+
+	skip, err = m.checkSize("key1", &memcache.Item{Value: []byte("12345")})
+	assert.False(t, skip)
+	assert.True(t, errors.Is(err, ErrValueTooLarge))
+	assert.Equal(t, "aah/cache/sizecache: key(key1) value size 5 exceeds max_value_size 4", err.Error())
+
+	m.size.policy = oversizeSkip
+	skip, err = m.checkSize("key1", &memcache.Item{Value: []byte("12345")})
+	assert.True(t, skip)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1), m.Metrics().OversizeSkipped)
+
+	m.size.max = 0
+	skip, err = m.checkSize("key1", &memcache.Item{Value: []byte("12345")})
+	assert.False(t, skip)
+	assert.Nil(t, err)
+}
+
+func TestMemcacheValueSizes(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	m := &memcacheCache{
+		cfg:      &cache.Config{Name: "sizecache"},
+		p:        &Provider{logger: l},
+		counters: new(counters),
+		size:     sizeLimit{warn: 900 << 10, policy: oversizeReject},
+	}
+	for _, n := range []int{100, 100, 2000, 950 << 10} {
+		_, err := m.checkSize("key1", &memcache.Item{Value: make([]byte, n)})
+		assert.Nil(t, err)
+	}
+	mt := m.Metrics()
+	assert.Equal(t, uint64(4), mt.ValueSizes.Count)
+	assert.Equal(t, 950<<10, mt.ValueSizes.Max)
+	assert.True(t, mt.ValueSizes.P50 >= 100 && mt.ValueSizes.P50 < 128)
+	assert.Equal(t, uint64(1), mt.LargeValues)
+
+	m.size.warn = 0
+	_, _ = m.checkSize("key1", &memcache.Item{Value: make([]byte, 2<<20)})
+	assert.Equal(t, uint64(1), m.Metrics().LargeValues)
+}
@@ -0,0 +1,105 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import "time"
+
+// AddWarmup method registers the loader func of given key for the cache of
+// given name, warmups run when the cache is created, so that critical
+// entries are populated before the app takes traffic. Register warmups
+// before the caches are created.
+//
+// Warmups run in the registration order, entries already present are not
+// reloaded unless `warmup.overwrite` is set. Warmup is bounded by
+// `warmup.timeout`, entries not loaded within it are left to load on demand;
+// `warmup.rate` limits the loads per second.
+//
+//	cache {
+//	  mycache {
+//	    warmup {
+//	      # default value is 30s
+//	      timeout = "30s"
+//
+//	      # loads per second; default value is 0, unlimited
+//	      rate = 50
+//
+//	      # default value is false
+//	      overwrite = false
+//	    }
+//	  }
+//	}
+func (p *Provider) AddWarmup(cacheName, k string, d time.Duration, fn LoaderFunc) {
+	p.cachesMu.Lock()
+	defer p.cachesMu.Unlock()
+	if p.warmups == nil {
+		p.warmups = make(map[string][]warmupEntry)
+	}
+	p.warmups[cacheName] = append(p.warmups[cacheName], warmupEntry{k: k, d: d, fn: fn})
+}
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported types and methods
+//______________________________________________________________________________
+
+type warmupEntry struct {
+	k  string
+	d  time.Duration
+	fn LoaderFunc
+}
+
+// warmup method runs the warmups registered for the cache, it returns the
+// number of entries loaded.
+func (m *memcacheCache) warmup() int {
+	m.p.cachesMu.RLock()
+	entries := m.p.warmups[m.Name()]
+	m.p.cachesMu.RUnlock()
+	if len(entries) == 0 {
+		return 0
+	}
+
+	timeout := parseDuration(m.settingString("warmup.timeout", ""), "30s")
+	overwrite := m.settingBool("warmup.overwrite", false)
+	var interval time.Duration
+	if rate := m.settingInt("warmup.rate", 0); rate > 0 {
+		interval = time.Second / time.Duration(rate)
+	}
+
+	start := time.Now()
+	deadline := start.Add(timeout)
+	var last time.Time
+	loaded, skipped := 0, 0
+	for i, w := range entries {
+		if time.Now().After(deadline) {
+			m.p.logger.Warnf("aah/cache/%s: warmup timed out after %v, %d entries not loaded",
+				m.Name(), timeout, len(entries)-i)
+			break
+		}
+		if !overwrite && m.Exists(w.k) {
+			skipped++
+			continue
+		}
+		if interval > 0 && !last.IsZero() {
+			if wait := interval - time.Since(last); wait > 0 {
+				time.Sleep(wait)
+			}
+		}
+		last = time.Now()
+		v, err := w.fn()
+		if err == nil {
+			err = m.Put(w.k, v, w.d)
+		}
+		if err == ErrNotFound {
+			continue
+		}
+		if err != nil {
+			m.p.logger.Errorf("aah/cache/%s: warmup key(%s) %v", m.Name(), m.logKey(w.k), err)
+			continue
+		}
+		loaded++
+	}
+	m.p.logger.Infof("aah/cache/%s: warmup loaded %d, skipped %d of %d entries in %v",
+		m.Name(), loaded, skipped, len(entries), time.Since(start))
+	return loaded
+}
@@ -0,0 +1,56 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheWarmup(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		warmup {
+			warmup {
+				rate = 100
+			}
+		}
+	}
+`)
+	p := mgr.Provider("memcache1").(*Provider)
+	calls := 0
+	p.AddWarmup("warmup", "config", time.Minute, func() (interface{}, error) {
+		calls++
+		return "settings", nil
+	})
+	p.AddWarmup("warmup", "missing", time.Minute, func() (interface{}, error) {
+		calls++
+		return nil, ErrNotFound
+	})
+	p.AddWarmup("warmup", "failing", time.Minute, func() (interface{}, error) {
+		calls++
+		return nil, errors.New("db down")
+	})
+
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "warmup", ProviderName: "memcache1"}))
+	c := mgr.Cache("warmup")
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, "settings", c.Get("config"))
+
+	// present entries are not reloaded
+	m := c.(*memcacheCache)
+	assert.Equal(t, 0, m.warmup())
+	assert.Equal(t, 5, calls)
+
+	assert.Nil(t, c.Delete("config"))
+}
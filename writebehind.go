@@ -0,0 +1,371 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// Write-behind queue overflow policies.
+const (
+	overflowBlock = "block"
+	overflowDrop  = "drop"
+	overflowSync  = "sync"
+)
+
+// Write-behind queue error policies.
+const (
+	onErrorLog   = "log"
+	onErrorRetry = "retry"
+)
+
+// ErrQueueFull error is returned when write-behind queue is full and the
+// overflow policy is `drop`.
+var ErrQueueFull = errors.New("aah/cache: write-behind queue is full")
+
+// writeQueue is the bounded in-memory queue of write-behind mode, drained by
+// the worker goroutines. With block overflow the Put waits for a free slot up
+// to `block_timeout`, then the write is dropped with `ErrQueueFull`; 0s waits
+// indefinitely. Saturation is reported in `Stats.WriteQueueDepth`,
+// `Stats.WriteQueueFull` and `Stats.WriteQueueDropped`.
+//
+// Put of a key already queued is coalesced into the queued write and returns
+// nil right away, such Put is fire-and-forget: if the queued write is dropped
+// on overflow later, only the Put that queued it gets `ErrQueueFull`. Use
+// `PutAsync` to learn the outcome, the callbacks of all the coalesced writes
+// are called with the drop or write error.
+//
+// Delete of a key drops its queued write and waits for its write in
+// progress, so the value is not written back after the Delete. Callbacks
+// of the dropped write are called with nil.
+//
+//	cache {
+//	  mycache {
+//	    write_behind {
+//	      enable = true
+//	      # default value is 1000
+//	      queue_size = 1000
+//	      # default value is 2
+//	      workers = 2
+//	      # block, drop or sync; default value is block
+//	      overflow = "block"
+//	      # default value is 0s, wait indefinitely
+//	      block_timeout = "100ms"
+//	      # log or retry; default value is log
+//	      on_error = "log"
+//	      # default value is 3, applicable to on_error = "retry"
+//	      retries = 3
+//	    }
+//	  }
+//	}
+type writeQueue struct {
+	m        *memcacheCache
+	ch       chan *writeOp
+	overflow string
+	blockFor time.Duration
+	onError  string
+	retries  int
+	pending  sync.WaitGroup
+
+	mu      sync.Mutex
+	queued  map[string]*writeOp
+	writing map[string]int
+	written *sync.Cond
+}
+
+// writeOp is the queued write of a key, subsequent Puts of the same key
+// while it is still queued replace its item and add their callbacks. The
+// cache of the op is the cache of the Put, e.g. the tenant cache sharing the
+// queue of its root cache.
+type writeOp struct {
+	m        *memcacheCache
+	k        string
+	item     *memcache.Item
+	done     []func(error)
+	merged   int
+	canceled bool
+}
+
+func newWriteQueue(m *memcacheCache) *writeQueue {
+	wq := &writeQueue{
+		m:        m,
+		ch:       make(chan *writeOp, m.settingInt("write_behind.queue_size", 1000)),
+		overflow: m.settingString("write_behind.overflow", overflowBlock),
+		blockFor: parseDuration(m.settingString("write_behind.block_timeout", ""), "0s"),
+		onError:  m.settingString("write_behind.on_error", onErrorLog),
+		retries:  m.settingInt("write_behind.retries", 3),
+		queued:   make(map[string]*writeOp),
+	}
+	workers := m.settingInt("write_behind.workers", 2)
+	if workers < 1 {
+		workers = 1
+	}
+	for i := 0; i < workers; i++ {
+		go wq.work()
+	}
+	return wq
+}
+
+// PutAsync method adds the cache entry with specified expiration without
+// blocking the caller, given func is called with the result of the write
+// (nil on success). Encode error is reported to the func immediately.
+//
+// It uses the write-behind queue of the cache, if write-behind mode is not
+// enabled, a queue with default `write_behind` settings is created on first use.
+func (m *memcacheCache) PutAsync(k string, v interface{}, d time.Duration, fn func(error)) {
+	wq := m.wq
+	if wq == nil {
+		root := m.root()
+		root.asyncMu.Lock()
+		if root.async == nil {
+			root.async = newWriteQueue(root)
+		}
+		wq = root.async
+		root.asyncMu.Unlock()
+	}
+	e, err := m.newEntry(k, v, d)
+	if err == nil {
+		err = wq.put(m, k, e, fn)
+	}
+	if err != nil && fn != nil {
+		fn(err)
+	}
+}
+
+// Sync method blocks until all the writes queued so far are written to
+// memcache. It is no-op if nothing was queued.
+func (m *memcacheCache) Sync() {
+	if m.wq != nil {
+		m.wq.pending.Wait()
+	}
+	if async := m.asyncQueue(); async != nil {
+		async.pending.Wait()
+	}
+}
+
+// putBehind method enqueues the entry into write-behind queue.
+func (m *memcacheCache) putBehind(k string, e *entry, done func(error)) error {
+	return m.wq.put(m, k, e, done)
+}
+
+// put method encodes the entry of given cache and enqueues it, it applies the
+// overflow policy when the queue is full. If the key is already queued and not
+// yet picked up by a worker, its pending write is replaced with latest value.
+func (wq *writeQueue) put(m *memcacheCache, k string, e *entry, done func(error)) error {
+	item, err := m.encodeEntry(k, e)
+	if err != nil {
+		return err
+	}
+	if skip, err := m.checkSize(k, item); skip || err != nil {
+		return err
+	}
+	if len(item.Value) > m.chunkSize() {
+		// chunked value is written synchronously
+		o := m.begin("put", k)
+		o.written(len(item.Value))
+		err = m.storeChunked(m.client().Set, k, item)
+		o.end(err)
+		if err != nil {
+			return m.opError("put", k, item.Key, nil, err)
+		}
+		m.counters.written(len(item.Key), len(item.Value))
+		m.onStored(k, item.Expiration)
+		if done != nil {
+			done(nil)
+		}
+		return nil
+	}
+	m.onStored(k, item.Expiration)
+
+	wq.mu.Lock()
+	if op, found := wq.queued[item.Key]; found {
+		op.item = item
+		op.merged++
+		if done != nil {
+			op.done = append(op.done, done)
+		}
+		wq.mu.Unlock()
+		return nil
+	}
+	op := &writeOp{m: m, k: k, item: item}
+	if done != nil {
+		op.done = append(op.done, done)
+	}
+	wq.queued[item.Key] = op
+	wq.mu.Unlock()
+
+	wq.pending.Add(1)
+	select {
+	case wq.ch <- op:
+		return nil
+	default:
+	}
+
+	atomic.AddUint64(&m.counters.writeQueueFull, 1)
+	switch wq.overflow {
+	case overflowDrop:
+		return wq.reject(op, done != nil)
+	case overflowSync:
+		wq.write(op)
+		return nil
+	default:
+		if wq.blockFor <= 0 {
+			wq.ch <- op
+			return nil
+		}
+		t := time.NewTimer(wq.blockFor)
+		defer t.Stop()
+		select {
+		case wq.ch <- op:
+			return nil
+		case <-t.C:
+			return wq.reject(op, done != nil)
+		}
+	}
+}
+
+// reject method drops the op not fitting into the queue, the callbacks of
+// the Puts coalesced into it are called with `ErrQueueFull`, own callback is
+// left to the caller.
+func (wq *writeQueue) reject(op *writeOp, ownDone bool) error {
+	m := op.m
+	_, done := wq.dequeue(op)
+	wq.pending.Done()
+	atomic.AddUint64(&m.counters.writeQueueDropped, 1)
+	m.p.logger.Warnf("aah/cache/%s: key(%s) %v, %d coalesced writes dropped", m.Name(), m.logKey(op.k),
+		ErrQueueFull, op.merged)
+	if ownDone && len(done) > 0 {
+		done = done[1:]
+	}
+	for _, fn := range done {
+		fn(ErrQueueFull)
+	}
+	return ErrQueueFull
+}
+
+// depth method returns the number of writes waiting in the queue.
+func (wq *writeQueue) depth() int {
+	if wq == nil {
+		return 0
+	}
+	return len(wq.ch)
+}
+
+func (wq *writeQueue) work() {
+	for op := range wq.ch {
+		wq.write(op)
+	}
+}
+
+// dequeue method removes the op from queued keys and returns its latest
+// item and callbacks, further Puts of the key are queued afresh.
+func (wq *writeQueue) dequeue(op *writeOp) (*memcache.Item, []func(error)) {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+	return wq.dequeueLocked(op)
+}
+
+func (wq *writeQueue) dequeueLocked(op *writeOp) (*memcache.Item, []func(error)) {
+	if mk := op.item.Key; wq.queued[mk] == op {
+		delete(wq.queued, mk)
+	}
+	return op.item, op.done
+}
+
+// take method dequeues the op for writing and marks its key in progress,
+// it returns nil item if the op is canceled by Delete.
+func (wq *writeQueue) take(op *writeOp) (*memcache.Item, []func(error)) {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+	if op.canceled {
+		return nil, op.done
+	}
+	item, done := wq.dequeueLocked(op)
+	if wq.writing == nil {
+		wq.writing = make(map[string]int)
+	}
+	wq.writing[item.Key]++
+	return item, done
+}
+
+// cancel method drops the queued write of given memcache key and waits for
+// its write in progress.
+func (wq *writeQueue) cancel(mk string) {
+	if wq == nil {
+		return
+	}
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+	if op, found := wq.queued[mk]; found {
+		op.canceled = true
+		delete(wq.queued, mk)
+	}
+	for wq.writing[mk] > 0 {
+		wq.cond().Wait()
+	}
+}
+
+// cond method returns the condition of finished writes, wq.mu must be held.
+func (wq *writeQueue) cond() *sync.Cond {
+	if wq.written == nil {
+		wq.written = sync.NewCond(&wq.mu)
+	}
+	return wq.written
+}
+
+func (wq *writeQueue) finish(mk string) {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+	if wq.writing[mk]--; wq.writing[mk] <= 0 {
+		delete(wq.writing, mk)
+	}
+	wq.cond().Broadcast()
+}
+
+func (wq *writeQueue) write(op *writeOp) {
+	defer wq.pending.Done()
+	m := op.m
+	item, done := wq.take(op)
+	if item == nil {
+		for _, fn := range done {
+			fn(nil)
+		}
+		return
+	}
+	defer wq.finish(item.Key)
+
+	o := m.begin("put", op.k)
+	o.written(len(item.Value))
+	err := m.client().Set(item)
+	if err != nil && wq.onError == onErrorRetry {
+		for i := 0; i < wq.retries && err != nil; i++ {
+			time.Sleep(time.Duration(i+1) * 10 * time.Millisecond)
+			err = m.client().Set(item)
+		}
+	}
+	o.end(err)
+	if err == nil {
+		m.counters.written(len(item.Key), len(item.Value))
+	} else {
+		err = m.opError("put", op.k, item.Key, nil, err)
+		m.p.logError(err)
+	}
+	for _, fn := range done {
+		fn(err)
+	}
+}
+
+// asyncQueue method returns the queue of `PutAsync`, nil if not created yet.
+func (m *memcacheCache) asyncQueue() *writeQueue {
+	root := m.root()
+	root.asyncMu.Lock()
+	defer root.asyncMu.Unlock()
+	return root.async
+}
@@ -0,0 +1,208 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"aahframe.work/config"
+	"aahframe.work/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheWriteBehind(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		writebehindcache {
+			write_behind {
+				enable = true
+				queue_size = 5
+				workers = 1
+				overflow = "sync"
+			}
+		}
+	}
+`, &cache.Config{Name: "writebehindcache", ProviderName: "memcache1"}).(Cache)
+
+	for i := 0; i < 20; i++ {
+		assert.Nil(t, c.Put(fmt.Sprintf("key_%v", i), i, 3*time.Second))
+	}
+	c.Sync()
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, i, c.Get(fmt.Sprintf("key_%v", i)))
+	}
+
+	for i := 0; i < 50; i++ {
+		assert.Nil(t, c.Put("counter", i, 3*time.Second))
+	}
+	c.Sync()
+	assert.Equal(t, 49, c.Get("counter"))
+
+	type unregistered struct{ Name string }
+	assert.NotNil(t, c.Put("key_x", unregistered{Name: "x"}, 3*time.Second))
+
+	c.Flush()
+}
+
+func TestMemcachePutAsync(t *testing.T) {
+	c := createTestCache(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+	}
+`, &cache.Config{Name: "putasynccache", ProviderName: "memcache1"}).(Cache)
+
+	done := make(chan error, 1)
+	c.PutAsync("key1", "async value", 3*time.Second, func(err error) { done <- err })
+	assert.Nil(t, <-done)
+	assert.Equal(t, "async value", c.Get("key1"))
+
+	type unregistered struct{ Name string }
+	c.PutAsync("key2", unregistered{Name: "x"}, 3*time.Second, func(err error) { done <- err })
+	assert.NotNil(t, <-done)
+
+	c.Flush()
+}
+
+func TestWriteQueueCoalesce(t *testing.T) {
+	m := &memcacheCache{cfg: &cache.Config{Name: "coalesce"}, keyPrefix: "coalesce-",
+		p: &Provider{}, chunkLimit: defaultChunkSize}
+	wq := &writeQueue{m: m, ch: make(chan *writeOp, 10), queued: make(map[string]*writeOp)}
+	m.wq = wq
+
+	for i := 0; i < 5; i++ {
+		assert.Nil(t, wq.put(m, "key1", newEntry(i, time.Second), func(error) {}))
+	}
+	assert.Nil(t, wq.put(m, "key2", newEntry("v", time.Second), nil))
+	assert.Equal(t, 2, len(wq.ch))
+
+	op := <-wq.ch
+	item, done := wq.dequeue(op)
+	assert.Equal(t, "coalesce-key1", item.Key)
+	assert.Equal(t, 5, len(done))
+
+	e, _ := m.decodeItem("key1", item)
+	assert.Equal(t, 4, e.V)
+}
+
+func TestWriteQueueOverflow(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "overflow"}, keyPrefix: "overflow-",
+		p: &Provider{logger: l}, chunkLimit: defaultChunkSize, counters: new(counters)}
+	wq := &writeQueue{m: m, ch: make(chan *writeOp, 1), queued: make(map[string]*writeOp),
+		overflow: overflowBlock, blockFor: 20 * time.Millisecond}
+	m.wq = wq
+
+	assert.Nil(t, wq.put(m, "key1", newEntry(1, time.Second), nil))
+	start := time.Now()
+	blocked := make(chan error)
+	go func() { blocked <- wq.put(m, "key2", newEntry(2, time.Second), nil) }()
+	for wq.pendingKey("key2") == nil {
+		time.Sleep(time.Millisecond)
+	}
+	coalesced := make(chan error, 1)
+	assert.Nil(t, wq.put(m, "key2", newEntry(3, time.Second), func(err error) { coalesced <- err }))
+	assert.Equal(t, ErrQueueFull, <-blocked)
+	assert.True(t, time.Since(start) >= 20*time.Millisecond)
+	assert.Equal(t, ErrQueueFull, <-coalesced)
+
+	wq.overflow = overflowDrop
+	err := wq.put(m, "key3", newEntry(4, time.Second), func(error) { t.Error("own callback is called") })
+	assert.Equal(t, ErrQueueFull, err)
+
+	s := m.Stats()
+	assert.Equal(t, 1, s.WriteQueueDepth)
+	assert.Equal(t, uint64(2), s.WriteQueueFull)
+	assert.Equal(t, uint64(2), s.WriteQueueDropped)
+}
+
+func TestWriteQueueCoalesceDrop(t *testing.T) {
+	l, _ := log.New(config.NewEmpty())
+	m := &memcacheCache{cfg: &cache.Config{Name: "coalescedrop"}, keyPrefix: "coalescedrop-",
+		p: &Provider{logger: l}, chunkLimit: defaultChunkSize, counters: new(counters)}
+	wq := &writeQueue{m: m, ch: make(chan *writeOp, 1), queued: make(map[string]*writeOp),
+		overflow: overflowBlock, blockFor: 20 * time.Millisecond}
+	m.wq = wq
+
+	assert.Nil(t, wq.put(m, "key1", newEntry(1, time.Second), nil))
+	blocked := make(chan error)
+	go func() { blocked <- wq.put(m, "key2", newEntry(2, time.Second), nil) }()
+	for wq.pendingKey("key2") == nil {
+		time.Sleep(time.Millisecond)
+	}
+	results := make(chan error, 2)
+	assert.Nil(t, wq.put(m, "key2", newEntry(3, time.Second), func(err error) { results <- err }))
+	assert.Nil(t, wq.put(m, "key2", newEntry(4, time.Second), nil)) // fire-and-forget
+	assert.Nil(t, wq.put(m, "key2", newEntry(5, time.Second), func(err error) { results <- err }))
+	assert.Equal(t, 3, wq.pendingKey("key2").merged)
+
+	assert.Equal(t, ErrQueueFull, <-blocked)
+	assert.Equal(t, ErrQueueFull, <-results)
+	assert.Equal(t, ErrQueueFull, <-results)
+	assert.Nil(t, wq.pendingKey("key2"))
+	assert.Equal(t, uint64(1), m.Stats().WriteQueueDropped)
+}
+
+func TestWriteQueueDelete(t *testing.T) {
+	m := newBenchCache()
+	m.p.dryRun.max = 10
+	wq := &writeQueue{m: m, ch: make(chan *writeOp, 10), queued: make(map[string]*writeOp)}
+	m.wq = wq
+
+	results := make(chan error, 1)
+	assert.Nil(t, wq.put(m, "key1", newEntry(1, time.Second), func(err error) { results <- err }))
+	assert.Nil(t, m.Delete("key1"))
+	assert.Nil(t, wq.pendingKey("key1"))
+	wq.write(<-wq.ch)
+	assert.Nil(t, <-results)
+	assert.Equal(t, 1, len(m.p.dryRun.ops))
+	assert.Equal(t, "delete", m.p.dryRun.ops[0].Op)
+
+	// write in progress is waited for
+	assert.Nil(t, wq.put(m, "key2", newEntry(2, time.Second), nil))
+	op := <-wq.ch
+	item, _ := wq.take(op)
+	deleted := make(chan struct{})
+	go func() {
+		_ = m.Delete("key2")
+		close(deleted)
+	}()
+	select {
+	case <-deleted:
+		t.Error("delete did not wait for the write in progress")
+	case <-time.After(20 * time.Millisecond):
+	}
+	wq.finish(item.Key)
+	<-deleted
+}
+
+func TestWriteQueueInstrumented(t *testing.T) {
+	m := newBenchCache()
+	wq := &writeQueue{m: m, ch: make(chan *writeOp, 10), queued: make(map[string]*writeOp)}
+	m.wq = wq
+
+	assert.Nil(t, m.Put("key1", "v", time.Minute))
+	assert.Equal(t, uint64(0), m.counters.ops)
+	wq.write(<-wq.ch)
+	assert.Equal(t, uint64(1), m.counters.ops)
+	_, found := m.counters.latencies()["put"]
+	assert.True(t, found)
+}
+
+func (wq *writeQueue) pendingKey(k string) *writeOp {
+	wq.mu.Lock()
+	defer wq.mu.Unlock()
+	return wq.queued[wq.m.key(k)]
+}
@@ -0,0 +1,46 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+//‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾‾
+// Unexported methods
+//______________________________________________________________________________
+
+// getItem method reads the memcache item of the cache entry. In write-only
+// mode the entry reads report miss without reaching memcache while the
+// writes proceed, so a new cluster is warmed by live traffic before the
+// reads are switched over. Internal reads, e.g. locks, counters and `Gets`,
+// are not affected.
+//
+//	cache {
+//	  mycache {
+//	    # default value is false
+//	    write_only = true
+//	  }
+//	}
+func (m *memcacheCache) getItem(mk string) (*memcache.Item, error) {
+	return m.getItemContext(context.Background(), mk)
+}
+
+func (m *memcacheCache) getItemContext(ctx context.Context, mk string) (*memcache.Item, error) {
+	if m.writeOnly {
+		return nil, memcache.ErrCacheMiss
+	}
+	return m.clientContext(ctx).Get(mk)
+}
+
+// getItems method is the multi-key version of `getItem`.
+func (m *memcacheCache) getItems(ctx context.Context, mks []string) (map[string]*memcache.Item, error) {
+	if m.writeOnly {
+		return map[string]*memcache.Item{}, nil
+	}
+	return m.clientContext(ctx).GetMulti(mks)
+}
@@ -0,0 +1,61 @@
+// Copyright (c) Jeevanandam M. (https://github.com/jeevatkm)
+// Source code and usage is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package memcache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"aahframe.work/cache"
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemcacheWriteOnly(t *testing.T) {
+	mgr := createCacheMgr(t, "memcache1", `
+	cache {
+		memcache1 {
+			provider = "memcache"
+			addresses = ["localhost:11211"]
+		}
+		writeonlycache {
+			write_only = true
+		}
+		exclusivecache {
+			read_only = true
+			write_only = true
+		}
+	}
+`)
+	assert.Nil(t, mgr.CreateCache(&cache.Config{Name: "writeonlycache", ProviderName: "memcache1"}))
+	c := mgr.Cache("writeonlycache").(Cache)
+	p := mgr.Provider("memcache1").(*Provider)
+
+	assert.Nil(t, c.Put("key1", "value1", time.Minute))
+	assert.Nil(t, c.Get("key1"))
+	assert.False(t, c.Exists("key1"))
+	assert.Equal(t, 0, len(c.GetMulti([]string{"key1"})))
+	_, err := p.Client().Get("writeonlycache-key1")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(3), c.Stats().Misses)
+
+	v, err := c.GetOrPut("key1", "value2", time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, "value2", v)
+	assert.Nil(t, c.Delete("key1"))
+
+	err = mgr.CreateCache(&cache.Config{Name: "exclusivecache", ProviderName: "memcache1"})
+	assert.Equal(t, "aah/cache/exclusivecache: read_only and write_only are mutually exclusive", err.Error())
+}
+
+func TestMemcacheWriteOnlyGetItem(t *testing.T) {
+	m := &memcacheCache{writeOnly: true}
+	_, err := m.getItem("key1")
+	assert.Equal(t, memcache.ErrCacheMiss, err)
+	items, err := m.getItems(context.Background(), []string{"key1", "key2"})
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(items))
+}